@@ -5,14 +5,25 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"arista_engine/internal/alert"
+	"arista_engine/internal/cassette"
+	"arista_engine/internal/catalog"
 	"arista_engine/internal/client"
+	"arista_engine/internal/collector"
 	"arista_engine/internal/core"
+	"arista_engine/internal/credentials"
 	"arista_engine/internal/enum"
+	"arista_engine/internal/inventory"
 	"arista_engine/internal/netvisor"
+	"arista_engine/internal/secrets"
 	"arista_engine/internal/store"
+	"arista_engine/internal/topology"
+	"arista_engine/internal/tsdb"
 	"arista_engine/internal/uiapi"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -27,9 +38,25 @@ type App struct {
 	store      *store.Store
 	eapiClient *client.EAPIClient
 	cvClient   *client.CloudVisionClient
-	apiParser  *enum.APIParser
+	gnmiClient *client.GNMIClient
+	sshClient  *client.SSHClient
+	catalogs   *catalog.Manager
 	uiAPI      *uiapi.ExplorerAPI
 	netvisorDB *netvisor.NetVisorDB
+	tsdb       *tsdb.DB
+	collector  *collector.Collector
+	alerts     *alert.Engine
+	alertSinks *alert.SinkSet
+	inventory  *inventory.Service
+	topology   *topology.Discoverer
+	creds      *credentials.Manager
+	secrets    *secrets.Registry
+	localStore *secrets.FileProvider
+
+	sessionMu  sync.Mutex
+	recorder   *cassette.Recorder
+	player     *cassette.Player
+	replayPath string
 }
 
 // NewApp creates a new App application struct
@@ -65,15 +92,33 @@ func NewApp() *App {
 	}
 	logger.Info("Database initialized successfully")
 
+	// Initialize secret providers for credential references such as "vault:kv/net/eapi#password"
+	localSecrets := secrets.NewFileProvider("secrets.enc")
+	secretRegistry := secrets.NewRegistry(secrets.DefaultCacheTTL,
+		secrets.EnvProvider{},
+		localSecrets,
+		secrets.NewExecProvider(secrets.DefaultExecCommands...),
+		secrets.NewVaultProvider(),
+		secrets.NewSecretServiceProvider(),
+	)
+
+	// Initialize encrypted credential profiles
+	creds, err := credentials.NewManager(store, "credentials.key", secretRegistry)
+	if err != nil {
+		logger.Fatal("Failed to initialize credential profiles", zap.Error(err))
+	}
+
 	// Initialize API clients
 	eapiClient := client.NewEAPIClient(true, 30*time.Second)
 	cvClient := client.NewCloudVisionClient(true, 30*time.Second)
+	gnmiClient := client.NewGNMIClient(true, 30*time.Second)
+	sshClient := client.NewSSHClient(true, 30*time.Second)
 
-	// Initialize API parser
-	apiParser := enum.NewAPIParser()
+	// Initialize API catalog
+	catalogs := catalog.NewManager(store, "Enumerated_API.md")
 
 	// Initialize UI API
-	uiAPI := uiapi.NewExplorerAPI(store, eapiClient, cvClient)
+	uiAPI := uiapi.NewExplorerAPI(store, eapiClient, cvClient, gnmiClient, sshClient, creds)
 
 	// Initialize NetVisor database
 	netvisorDB, err := netvisor.NewNetVisorDB("netvisor_api_v711.db")
@@ -85,14 +130,72 @@ func NewApp() *App {
 		logger.Info("NetVisor database initialized successfully")
 	}
 
+	// Initialize time-series storage and the collector feeding it
+	seriesDB, err := tsdb.Open("timeseries.db", tsdb.DefaultRetention)
+	if err != nil {
+		logger.Fatal("Failed to initialize time-series database", zap.Error(err))
+	}
+	seriesDB.OnError(func(err error) {
+		logger.Warn("Failed to store samples", zap.Error(err))
+	})
+	coll := collector.NewCollector(store, eapiClient, gnmiClient, creds)
+	coll.AddHandler(func(sample core.Sample) {
+		if err := seriesDB.WriteSample(sample); err != nil {
+			logger.Warn("Failed to write sample",
+				zap.String("device", sample.Device),
+				zap.String("path", sample.Path),
+				zap.Error(err),
+			)
+		}
+	})
+
+	// Initialize alerting on collected samples
+	alerts, err := alert.NewEngine(store)
+	if err != nil {
+		logger.Fatal("Failed to initialize alert engine", zap.Error(err))
+	}
+	alerts.OnError(func(err error) {
+		logger.Error("Alert processing failed", zap.Error(err))
+	})
+	coll.AddHandler(alerts.Observe)
+
+	// Outbound sinks are built once and rebuilt when their configuration changes
+	alertSinks := &alert.SinkSet{}
+	if sinks, err := store.GetAlertSinks(); err != nil {
+		logger.Error("Failed to load alert sinks", zap.Error(err))
+	} else if err := alertSinks.Set(sinks); err != nil {
+		logger.Warn("Invalid alert sinks", zap.Error(err))
+	}
+
+	// Keep inventory records up to date with collected device facts. Imports may only
+	// refer to secrets through credential profiles.
+	inventorySvc := inventory.NewService(store)
+	inventorySvc.RejectReferences(secretRegistry.IsReference)
+	coll.AddFactsHandler(func(endpointID string, facts core.DeviceFacts) {
+		if err := inventorySvc.ApplyFacts(endpointID, facts); err != nil {
+			logger.Warn("Failed to store device facts", zap.String("endpoint", endpointID), zap.Error(err))
+		}
+	})
+
 	return &App{
 		logger:     logger,
 		store:      store,
 		eapiClient: eapiClient,
 		cvClient:   cvClient,
-		apiParser:  apiParser,
+		gnmiClient: gnmiClient,
+		sshClient:  sshClient,
+		catalogs:   catalogs,
 		uiAPI:      uiAPI,
 		netvisorDB: netvisorDB,
+		tsdb:       seriesDB,
+		collector:  coll,
+		alerts:     alerts,
+		alertSinks: alertSinks,
+		inventory:  inventorySvc,
+		topology:   topology.NewDiscoverer(store, eapiClient, creds),
+		creds:      creds,
+		secrets:    secretRegistry,
+		localStore: localSecrets,
 	}
 }
 
@@ -114,78 +217,152 @@ func (a *App) startup(ctx context.Context) {
 		}
 	}
 
-	// Load API catalog if it exists, otherwise parse the enumerated API
-	catalogPath := "api_catalog.json"
-	if _, err := os.Stat(catalogPath); os.IsNotExist(err) {
-		a.logger.Info("API catalog not found, parsing enumerated API...")
-		if err := a.apiParser.ParseEnumeratedAPI("Enumerated_API.md"); err != nil {
-			a.logger.Error("Failed to parse enumerated API", zap.Error(err))
-			runtime.LogError(ctx, fmt.Sprintf("Failed to parse enumerated API: %v", err))
-		} else {
-			// Save the parsed catalog
-			if err := a.apiParser.SaveCatalog(catalogPath); err != nil {
-				a.logger.Error("Failed to save API catalog", zap.Error(err))
-			} else {
-				a.logger.Info("API catalog saved successfully")
-			}
+	// Report catalog builds to the UI and the log
+	a.catalogs.OnProgress(func(p core.CatalogProgress) {
+		runtime.EventsEmit(ctx, "catalog:progress", p)
+		switch p.Stage {
+		case "done":
+			a.logger.Info("API catalog source built", zap.String("source", p.Source), zap.Int("endpoints", p.Endpoints))
+		case "failed":
+			a.logger.Error("Failed to build API catalog source", zap.String("source", p.Source), zap.String("error", p.Error))
 		}
+	})
+
+	// Serve the stored catalog right away and rebuild it in the background when the
+	// enumerated API changed since it was built
+	stale, err := a.catalogs.Load()
+	if err != nil {
+		a.logger.Error("Failed to load API catalog", zap.Error(err))
+		runtime.LogError(ctx, fmt.Sprintf("Failed to load API catalog: %v", err))
 	} else {
-		// Load existing catalog
-		if err := a.apiParser.LoadCatalog(catalogPath); err != nil {
-			a.logger.Error("Failed to load API catalog", zap.Error(err))
-		} else {
-			a.logger.Info("API catalog loaded successfully")
+		a.logger.Info("API catalog loaded", zap.String("version", a.catalogs.Catalog().SourceVersion), zap.Bool("stale", stale))
+	}
+	if _, statErr := os.Stat(bundledCatalog); statErr == nil {
+		if _, err := a.catalogs.ImportIfChanged(bundledCatalog); err != nil {
+			a.logger.Error("Failed to import bundled API catalog", zap.String("path", bundledCatalog), zap.Error(err))
 		}
 	}
+	if stale {
+		a.logger.Info("Rebuilding API catalog from the enumerated API...")
+		a.catalogs.RebuildInBackground()
+	}
+
+	// Bring the device inventory in line with the configured endpoints
+	if created, removed, err := a.inventory.Reconcile(); err != nil {
+		a.logger.Error("Failed to reconcile device inventory", zap.Error(err))
+	} else if created > 0 || removed > 0 {
+		a.logger.Info("Device inventory reconciled",
+			zap.Int("created", created),
+			zap.Int("removed", removed),
+		)
+	}
+
+	// Forward alert state changes to the UI and the configured outbound sinks
+	a.alerts.AddNotifier(alert.NotifierFunc(func(event core.AlertEvent) error {
+		runtime.EventsEmit(ctx, "alert", event)
+		return nil
+	}))
+	a.alerts.AddNotifier(a.alertSinks)
+
+	// Report failed telemetry subscriptions; the collector retries them with backoff
+	a.collector.OnSubscriptionError(func(endpointID string, err error, retryIn time.Duration) {
+		a.logger.Warn("Telemetry subscription failed",
+			zap.String("endpoint", endpointID),
+			zap.Error(err),
+			zap.Duration("retryIn", retryIn),
+		)
+		runtime.EventsEmit(ctx, "telemetry:error", map[string]any{
+			"endpointId": endpointID,
+			"error":      err.Error(),
+			"retryIn":    retryIn.Seconds(),
+		})
+	})
+
+	// Warn about expiring CloudVision tokens at startup and every few hours
+	go func() {
+		ticker := time.NewTicker(6 * time.Hour)
+		defer ticker.Stop()
+		for {
+			a.checkCloudVisionTokens(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	// Apply time-series retention periodically
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if err := a.tsdb.Prune(time.Now()); err != nil {
+				a.logger.Error("Failed to prune time-series data", zap.Error(err))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
 
 	a.logger.Info("Arista Engine started successfully")
 	runtime.LogInfo(ctx, "Arista Engine started successfully")
 }
 
-// GetEndpoints returns all configured endpoints
+// shutdown is called when the app is closing
+func (a *App) shutdown(ctx context.Context) {
+	a.collector.Stop()
+	a.alerts.Close()
+	if err := a.tsdb.Close(); err != nil {
+		a.logger.Error("Failed to close time-series database", zap.Error(err))
+	}
+	if err := a.store.Close(); err != nil {
+		a.logger.Error("Failed to close database", zap.Error(err))
+	}
+	a.logger.Info("Arista Engine stopped")
+	a.logger.Sync()
+}
+
+// GetEndpoints returns all configured endpoints with their encrypted proxy secrets removed
 func (a *App) GetEndpoints() ([]core.Endpoint, error) {
-	return a.store.GetEndpoints()
+	endpoints, err := a.store.GetEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		endpoints[i] = credentials.RedactProxy(endpoints[i])
+	}
+	return endpoints, nil
 }
 
-// AddEndpoint adds a new endpoint
+// AddEndpoint adds a new endpoint and its device inventory record
 func (a *App) AddEndpoint(endpoint core.Endpoint) error {
-	endpoint.ID = fmt.Sprintf("ep_%d", time.Now().UnixNano())
-	endpoint.Created = time.Now()
-
-	if err := a.store.SaveEndpoint(endpoint); err != nil {
-		a.logger.Error("Failed to save endpoint", zap.Error(err))
+	if err := a.creds.SealProxy(&endpoint, nil); err != nil {
 		return err
 	}
-
-	// Also add to device inventory
-	device := core.DeviceInventory{
-		ID:           endpoint.ID,
-		Name:         endpoint.Name,
-		DeviceType:   string(endpoint.Type), // This will be the API type (eapi, cloudvision, etc.)
-		URL:          endpoint.URL,
-		Username:     endpoint.Username,
-		Password:     endpoint.Password,
-		Type:         string(endpoint.Type),
-		Status:       "disconnected",
-		AddedAt:      time.Now(),
-		LastTested:   time.Time{},
-		TestCount:    0,
-		SuccessCount: 0,
-		Notes:        fmt.Sprintf("Added via Endpoint Manager - %s", endpoint.Type),
-	}
-
-	if err := a.store.AddDeviceToInventory(device); err != nil {
-		a.logger.Error("Failed to add device to inventory", zap.Error(err))
-		// Don't fail the endpoint creation if inventory fails
+	endpoint, err := a.inventory.AddEndpoint(endpoint)
+	if err != nil {
+		a.logger.Error("Failed to save endpoint", zap.Error(err))
+		return err
 	}
 
 	a.logger.Info("Endpoint added", zap.String("id", endpoint.ID), zap.String("name", endpoint.Name))
 	return nil
 }
 
-// UpdateEndpoint updates an existing endpoint
+// UpdateEndpoint updates an existing endpoint and its device inventory record
 func (a *App) UpdateEndpoint(endpoint core.Endpoint) error {
-	if err := a.store.SaveEndpoint(endpoint); err != nil {
+	stored, err := a.store.GetEndpoint(endpoint.ID)
+	if err != nil {
+		return err
+	}
+	if err := a.creds.SealProxy(&endpoint, stored.Proxy); err != nil {
+		return err
+	}
+	if err := a.inventory.UpdateEndpoint(endpoint); err != nil {
 		a.logger.Error("Failed to update endpoint", zap.Error(err))
 		return err
 	}
@@ -194,12 +371,17 @@ func (a *App) UpdateEndpoint(endpoint core.Endpoint) error {
 	return nil
 }
 
-// DeleteEndpoint deletes an endpoint
+// DeleteEndpoint deletes an endpoint along with its device inventory record and test history
 func (a *App) DeleteEndpoint(endpointID string) error {
-	if err := a.store.DeleteEndpoint(endpointID); err != nil {
+	if err := a.inventory.DeleteEndpoint(endpointID); err != nil {
 		a.logger.Error("Failed to delete endpoint", zap.Error(err))
 		return err
 	}
+	a.collector.StopSubscription(endpointID)
+	a.eapiClient.ForgetEndpoint(endpointID)
+	a.cvClient.ForgetEndpoint(endpointID)
+	a.gnmiClient.ForgetEndpoint(endpointID)
+	a.sshClient.ForgetEndpoint(endpointID)
 
 	a.logger.Info("Endpoint deleted", zap.String("id", endpointID))
 	return nil
@@ -211,6 +393,10 @@ func (a *App) TestConnection(endpointID string) (core.ConnectionTestResult, erro
 	if err != nil {
 		return core.ConnectionTestResult{}, err
 	}
+	endpoint, err = a.creds.Resolve(endpoint)
+	if err != nil {
+		return core.ConnectionTestResult{}, err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -219,67 +405,229 @@ func (a *App) TestConnection(endpointID string) (core.ConnectionTestResult, erro
 	var message string
 	var elapsed time.Duration
 
+	// Inspect DNS, TCP and TLS posture before authenticating
+	report := client.ProbeEndpoint(ctx, endpoint)
+	var status int
+	var device *core.DeviceMetadata
+
 	switch endpoint.Type {
 	case core.EndpointEAPI:
-		success, message, elapsed, err = a.eapiClient.TestConnection(ctx, endpoint.URL, endpoint.Username, endpoint.Password)
+		eapiClient, cerr := a.eapiClient.ForEndpoint(endpoint)
+		if cerr != nil {
+			return core.ConnectionTestResult{}, cerr
+		}
+		device, status, elapsed, err = eapiClient.ShowVersion(ctx, endpoint.URL, endpoint.Username, endpoint.Password)
+		success = err == nil && status == 200
+		message = "Connection successful"
+		if !success {
+			message = "Connection failed"
+		}
 	case core.EndpointCV:
-		success, message, elapsed, err = a.cvClient.TestConnection(ctx, endpoint.URL, endpoint.Token)
+		cvClient, cerr := a.cvClient.ForEndpoint(endpoint)
+		if cerr != nil {
+			return core.ConnectionTestResult{}, cerr
+		}
+		success, message, status, elapsed, err = cvClient.TestConnection(ctx, endpoint.URL, endpoint.Token)
+		if info, perr := client.ParseToken(endpoint.Token, time.Now()); perr == nil {
+			report.Token = info
+		}
+		if success {
+			device, _ = cvClient.GetVersion(ctx, endpoint.URL, endpoint.Token)
+		}
+	case core.EndpointTelemetry:
+		gnmiClient, cerr := a.gnmiClient.ForEndpoint(endpoint)
+		if cerr != nil {
+			return core.ConnectionTestResult{}, cerr
+		}
+		success, message, elapsed, err = gnmiClient.TestConnection(ctx, endpoint.URL, endpoint.Username, endpoint.Password)
+	case core.EndpointSSH:
+		sshClient, cerr := a.sshClient.ForEndpoint(endpoint)
+		if cerr != nil {
+			return core.ConnectionTestResult{}, cerr
+		}
+		device, elapsed, err = sshClient.ShowVersion(ctx, endpoint.URL, endpoint.Username, endpoint.Password)
+		success = err == nil
+		message = "Connection successful"
+		if !success {
+			message = "Connection failed"
+		}
 	default:
 		return core.ConnectionTestResult{}, fmt.Errorf("unsupported endpoint type: %s", endpoint.Type)
 	}
 
 	result := core.ConnectionTestResult{
-		Success:   success,
-		Message:   message,
-		ElapsedMs: elapsed.Milliseconds(),
+		Success:    success,
+		Message:    message,
+		StatusCode: status,
+		ElapsedMs:  elapsed.Milliseconds(),
 	}
 
 	if err != nil {
 		result.Message = err.Error()
 	}
 
-	// Update endpoint status
-	endpoint.Status = "Connected"
-	if !success {
-		endpoint.Status = "Failed"
+	report.Auth = &core.AuthReport{
+		Success:    success,
+		StatusCode: status,
+		Message:    result.Message,
+	}
+	report.Device = device
+	result.Details = report
+
+	// Update endpoint and inventory status and record the result
+	if err := a.inventory.RecordTest(endpoint.ID, result); err != nil {
+		a.logger.Error("Failed to record test result", zap.String("id", endpoint.ID), zap.Error(err))
 	}
-	a.store.SaveEndpoint(endpoint)
 
 	return result, nil
 }
 
 // GetAPICatalog returns the complete API catalog
 func (a *App) GetAPICatalog() (*core.APICatalog, error) {
-	return a.apiParser.GetCatalog(), nil
+	return a.catalogs.Catalog(), nil
 }
 
 // ServeAPICatalog serves the API catalog as JSON for frontend consumption
 func (a *App) ServeAPICatalog() (map[string]interface{}, error) {
-	catalog := a.apiParser.GetCatalog()
+	catalog := a.catalogs.Catalog()
 	if catalog == nil {
 		return nil, fmt.Errorf("API catalog not loaded")
 	}
 
 	// Convert to map for JSON serialization
 	result := map[string]interface{}{
-		"eapi":        catalog.EAPI,
-		"cloudvision": catalog.CloudVision,
-		"eos_rest":    catalog.EOSREST,
-		"telemetry":   catalog.Telemetry,
-		"lastUpdated": catalog.LastUpdated,
+		"eapi":          catalog.EAPI,
+		"cloudvision":   catalog.CloudVision,
+		"eos_rest":      catalog.EOSREST,
+		"telemetry":     catalog.Telemetry,
+		"lastUpdated":   catalog.LastUpdated,
+		"sourceVersion": catalog.SourceVersion,
 	}
 
 	return result, nil
 }
 
+// ListCatalogVersions lists the stored API catalog versions
+func (a *App) ListCatalogVersions() ([]core.CatalogVersion, error) {
+	return a.store.ListCatalogVersions()
+}
+
+// ImportAPIDocument parses another release of the API reference and stores its catalog
+// as a version to diff against. The active catalog is not changed.
+func (a *App) ImportAPIDocument(path string) (core.CatalogVersion, error) {
+	parser := enum.NewAPIParser()
+	if err := parser.ParseEnumeratedAPI(path); err != nil {
+		a.logger.Error("Failed to parse API document", zap.String("path", path), zap.Error(err))
+		return core.CatalogVersion{}, err
+	}
+	catalog := parser.GetCatalog()
+	if err := a.store.SaveCatalogVersion(catalog); err != nil {
+		a.logger.Error("Failed to store API catalog version", zap.String("path", path), zap.Error(err))
+		return core.CatalogVersion{}, err
+	}
+
+	report := parser.Report()
+	a.logger.Info("API document imported",
+		zap.String("path", path),
+		zap.String("version", catalog.SourceVersion),
+		zap.Int("endpoints", report.Endpoints),
+	)
+	return core.CatalogVersion{
+		Version:     catalog.SourceVersion,
+		Source:      catalog.Source,
+		Endpoints:   report.Endpoints,
+		LastUpdated: catalog.LastUpdated,
+	}, nil
+}
+
+// DiffCatalogVersions compares two stored catalog versions and lists the logged queries
+// that use a removed or changed operation
+func (a *App) DiffCatalogVersions(from, to string) (core.CatalogDiff, error) {
+	fromCatalog, err := a.store.GetCatalogVersion(from)
+	if err != nil {
+		return core.CatalogDiff{}, err
+	}
+	toCatalog, err := a.store.GetCatalogVersion(to)
+	if err != nil {
+		return core.CatalogDiff{}, err
+	}
+
+	diff := enum.DiffCatalogs(fromCatalog, toCatalog)
+	records, err := a.store.GetQueryLog()
+	if err != nil {
+		a.logger.Error("Failed to load query log for catalog diff", zap.Error(err))
+		return diff, nil
+	}
+	diff.AffectedQueries = enum.FindAffectedQueries(diff, records)
+
+	a.logger.Info("API catalog versions compared",
+		zap.String("from", from),
+		zap.String("to", to),
+		zap.Int("added", len(diff.Added)),
+		zap.Int("removed", len(diff.Removed)),
+		zap.Int("changed", len(diff.Changed)),
+		zap.Int("affectedQueries", len(diff.AffectedQueries)),
+	)
+	return diff, nil
+}
+
+// DeleteCatalogVersion removes a stored API catalog version
+func (a *App) DeleteCatalogVersion(version string) error {
+	if version == a.catalogs.Catalog().SourceVersion {
+		return fmt.Errorf("version %s is the active catalog", version)
+	}
+	if err := a.store.DeleteCatalogVersion(version); err != nil {
+		return err
+	}
+	a.logger.Info("API catalog version deleted", zap.String("version", version))
+	return nil
+}
+
+// bundledCatalog is merged into the API catalog at startup
+const bundledCatalog = "configs/complete_api_catalog.json"
+
+// GetCatalogSources lists the documents the API catalog is built from
+func (a *App) GetCatalogSources() ([]core.CatalogSource, error) {
+	return a.catalogs.Sources()
+}
+
+// ImportCatalogFile merges a catalog JSON file into the API catalog
+func (a *App) ImportCatalogFile(path string) (core.CatalogSource, error) {
+	source, err := a.catalogs.Import(path)
+	if err != nil {
+		a.logger.Error("Failed to import API catalog", zap.String("path", path), zap.Error(err))
+		return core.CatalogSource{}, err
+	}
+	return source, nil
+}
+
+// RemoveCatalogSource drops an imported catalog from the API catalog
+func (a *App) RemoveCatalogSource(id string) error {
+	if err := a.catalogs.RemoveSource(id); err != nil {
+		return err
+	}
+	a.logger.Info("API catalog source removed", zap.String("id", id))
+	return nil
+}
+
+// RebuildAPICatalog parses the enumerated API again in the background; progress is
+// reported with "catalog:progress" events
+func (a *App) RebuildAPICatalog() error {
+	if a.catalogs.Building() {
+		return fmt.Errorf("a catalog rebuild is already running")
+	}
+	a.catalogs.RebuildInBackground()
+	return nil
+}
+
 // GetEndpointsByService returns endpoints for a specific service
 func (a *App) GetEndpointsByService(service string) (map[string]core.APIDefinition, error) {
-	return a.apiParser.GetEndpointsByService(service), nil
+	return a.catalogs.Parser().GetEndpointsByService(service), nil
 }
 
 // SearchEndpoints searches for API endpoints
 func (a *App) SearchEndpoints(query string) ([]core.APIDefinition, error) {
-	return a.apiParser.SearchEndpoints(query), nil
+	return a.catalogs.Parser().SearchEndpoints(query), nil
 }
 
 // RunAPIRequest executes an API request
@@ -298,11 +646,807 @@ func (a *App) ExportResults(format string, data []core.APIQueryRecord) (string,
 	return "", fmt.Errorf("export not yet implemented")
 }
 
+// StartCounterPolling starts periodic polling of interface counters on all eAPI endpoints
+func (a *App) StartCounterPolling(intervalSeconds int) error {
+	if err := a.collector.StartPolling(time.Duration(intervalSeconds) * time.Second); err != nil {
+		return err
+	}
+	a.logger.Info("Counter polling started", zap.Int("intervalSeconds", intervalSeconds))
+	return nil
+}
+
+// StopCounterPolling stops periodic counter polling
+func (a *App) StopCounterPolling() {
+	a.collector.StopPolling()
+	a.logger.Info("Counter polling stopped")
+}
+
+// StartTelemetryCollection subscribes to gNMI paths on a telemetry endpoint and records them as time series
+func (a *App) StartTelemetryCollection(endpointID string, paths []string, sampleIntervalMs int) error {
+	endpoint, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return err
+	}
+
+	interval := time.Duration(sampleIntervalMs) * time.Millisecond
+	if err := a.collector.StartSubscription(endpoint, paths, interval); err != nil {
+		return err
+	}
+	a.logger.Info("Telemetry collection started", zap.String("endpoint", endpointID), zap.Strings("paths", paths))
+	return nil
+}
+
+// StopTelemetryCollection stops the gNMI subscription for an endpoint
+func (a *App) StopTelemetryCollection(endpointID string) {
+	a.collector.StopSubscription(endpointID)
+	a.logger.Info("Telemetry collection stopped", zap.String("endpoint", endpointID))
+}
+
+// ListSeries returns the recorded time series for a device ("device|path"); empty device lists all
+func (a *App) ListSeries(device string) ([]string, error) {
+	return a.tsdb.ListSeries(device)
+}
+
+// GetSeries returns a time series for charting. Times are Unix milliseconds; stepSeconds of 0 returns raw samples.
+func (a *App) GetSeries(device, path string, fromMs, toMs int64, stepSeconds int) ([]core.SeriesPoint, error) {
+	return a.tsdb.Query(device, path, time.UnixMilli(fromMs), time.UnixMilli(toMs), time.Duration(stepSeconds)*time.Second)
+}
+
+// GetSeriesStats returns min/max/avg aggregations of a time series over a window
+func (a *App) GetSeriesStats(device, path string, fromMs, toMs int64) (core.SeriesStats, error) {
+	return a.tsdb.Aggregate(device, path, time.UnixMilli(fromMs), time.UnixMilli(toMs))
+}
+
+// GetAlertRules returns all alert rules
+func (a *App) GetAlertRules() ([]core.AlertRule, error) {
+	return a.store.GetAlertRules()
+}
+
+// SaveAlertRule creates or updates an alert rule
+func (a *App) SaveAlertRule(rule core.AlertRule) (core.AlertRule, error) {
+	if _, err := alert.ParseCondition(rule.Expression); err != nil {
+		return rule, err
+	}
+	if rule.ID == "" {
+		rule.ID = fmt.Sprintf("rule_%d", time.Now().UnixNano())
+		rule.Created = time.Now()
+	}
+	if rule.Severity == "" {
+		rule.Severity = "warning"
+	}
+
+	if err := a.store.SaveAlertRule(rule); err != nil {
+		a.logger.Error("Failed to save alert rule", zap.Error(err))
+		return rule, err
+	}
+	if err := a.alerts.Reload(); err != nil {
+		return rule, err
+	}
+
+	a.logger.Info("Alert rule saved", zap.String("id", rule.ID), zap.String("expression", rule.Expression))
+	return rule, nil
+}
+
+// DeleteAlertRule deletes an alert rule
+func (a *App) DeleteAlertRule(ruleID string) error {
+	if err := a.store.DeleteAlertRule(ruleID); err != nil {
+		a.logger.Error("Failed to delete alert rule", zap.Error(err))
+		return err
+	}
+	return a.alerts.Reload()
+}
+
+// GetActiveAlerts returns the alerts that are currently firing
+func (a *App) GetActiveAlerts() []core.AlertEvent {
+	return a.alerts.Active()
+}
+
+// GetAlertHistory returns alert events since the given Unix millisecond timestamp (0 for all)
+func (a *App) GetAlertHistory(sinceMs int64) ([]core.AlertEvent, error) {
+	since := time.Time{}
+	if sinceMs > 0 {
+		since = time.UnixMilli(sinceMs)
+	}
+	return a.store.GetAlertHistory(since)
+}
+
+// GetAlertSinks returns the configured outbound alert sinks
+func (a *App) GetAlertSinks() ([]core.AlertSink, error) {
+	return a.store.GetAlertSinks()
+}
+
+// SaveAlertSink creates or updates an outbound alert sink (webhook or syslog)
+func (a *App) SaveAlertSink(sink core.AlertSink) (core.AlertSink, error) {
+	if _, err := alert.NewSink(sink); err != nil {
+		return sink, err
+	}
+	if sink.ID == "" {
+		sink.ID = fmt.Sprintf("sink_%d", time.Now().UnixNano())
+	}
+	if err := a.store.SaveAlertSink(sink); err != nil {
+		a.logger.Error("Failed to save alert sink", zap.Error(err))
+		return sink, err
+	}
+	return sink, a.reloadAlertSinks()
+}
+
+// DeleteAlertSink deletes an outbound alert sink
+func (a *App) DeleteAlertSink(sinkID string) error {
+	if err := a.store.DeleteAlertSink(sinkID); err != nil {
+		return err
+	}
+	return a.reloadAlertSinks()
+}
+
+// reloadAlertSinks rebuilds the outbound alert sinks from the store
+func (a *App) reloadAlertSinks() error {
+	sinks, err := a.store.GetAlertSinks()
+	if err != nil {
+		return err
+	}
+	if err := a.alertSinks.Set(sinks); err != nil {
+		a.logger.Warn("Invalid alert sinks", zap.Error(err))
+	}
+	return nil
+}
+
+// GetCredentialProfiles returns all credential profiles without their secrets
+func (a *App) GetCredentialProfiles() ([]core.CredentialProfile, error) {
+	return a.creds.Profiles()
+}
+
+// SaveCredentialProfile creates or updates a credential profile; empty secrets keep the stored values
+func (a *App) SaveCredentialProfile(profile core.CredentialProfile) (core.CredentialProfile, error) {
+	saved, err := a.creds.SaveProfile(profile)
+	if err != nil {
+		a.logger.Error("Failed to save credential profile", zap.Error(err))
+		return saved, err
+	}
+
+	a.logger.Info("Credential profile saved", zap.String("id", saved.ID), zap.String("name", saved.Name))
+	return saved, nil
+}
+
+// DeleteCredentialProfile deletes a credential profile that no endpoint references
+func (a *App) DeleteCredentialProfile(profileID string) error {
+	if err := a.creds.DeleteProfile(profileID); err != nil {
+		a.logger.Error("Failed to delete credential profile", zap.String("id", profileID), zap.Error(err))
+		return err
+	}
+
+	a.logger.Info("Credential profile deleted", zap.String("id", profileID))
+	return nil
+}
+
+// RotateCredentialProfile replaces the secrets of a profile and returns the endpoints using it
+func (a *App) RotateCredentialProfile(profileID, username, password, token string) ([]string, error) {
+	endpoints, err := a.creds.Rotate(profileID, username, password, token)
+	if err != nil {
+		a.logger.Error("Failed to rotate credential profile", zap.String("id", profileID), zap.Error(err))
+		return nil, err
+	}
+
+	a.logger.Info("Credential profile rotated", zap.String("id", profileID), zap.Int("endpoints", len(endpoints)))
+	return endpoints, nil
+}
+
+// GetSecretSchemes returns the secret reference schemes credentials can use
+func (a *App) GetSecretSchemes() []string {
+	schemes := a.secrets.Schemes()
+	sort.Strings(schemes)
+	return schemes
+}
+
+// GetLocalSecretNames lists the secrets in the encrypted local secrets file
+func (a *App) GetLocalSecretNames() ([]string, error) {
+	return a.localStore.Names()
+}
+
+// SetLocalSecret stores a secret in the encrypted local secrets file, referenced as "file:<name>"
+func (a *App) SetLocalSecret(name, value string) error {
+	if err := a.localStore.Set(name, value); err != nil {
+		a.logger.Error("Failed to store local secret", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	a.secrets.Invalidate()
+	a.logger.Info("Local secret stored", zap.String("name", name))
+	return nil
+}
+
+// DeleteLocalSecret removes a secret from the encrypted local secrets file
+func (a *App) DeleteLocalSecret(name string) error {
+	if err := a.localStore.Delete(name); err != nil {
+		a.logger.Error("Failed to delete local secret", zap.String("name", name), zap.Error(err))
+		return err
+	}
+
+	a.secrets.Invalidate()
+	a.logger.Info("Local secret deleted", zap.String("name", name))
+	return nil
+}
+
 // GetDeviceInventory retrieves the device inventory
 func (a *App) GetDeviceInventory() ([]core.DeviceInventory, error) {
 	return a.store.GetDeviceInventory()
 }
 
+// ImportInventory imports endpoints from a CSV, YAML or Ansible inventory file.
+// With dryRun set nothing is saved and the result previews the import.
+func (a *App) ImportInventory(format, content string, dryRun bool) (core.ImportResult, error) {
+	result, err := a.inventory.Import(format, []byte(content), dryRun)
+	if err != nil {
+		a.logger.Error("Failed to import inventory", zap.String("format", format), zap.Error(err))
+		return result, err
+	}
+
+	a.logger.Info("Inventory imported",
+		zap.String("format", format),
+		zap.Bool("dryRun", dryRun),
+		zap.Int("added", result.Added),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("invalid", result.Invalid),
+	)
+	return result, nil
+}
+
+// ExportInventory exports all endpoints as a CSV, YAML or Ansible inventory file
+func (a *App) ExportInventory(format string) (string, error) {
+	data, err := a.inventory.Export(format)
+	if err != nil {
+		a.logger.Error("Failed to export inventory", zap.String("format", format), zap.Error(err))
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SyncCloudVisionInventory pulls the device list of a CloudVision endpoint and creates
+// or updates eAPI endpoints and inventory records from it
+func (a *App) SyncCloudVisionInventory(endpointID string) (core.CVSyncResult, error) {
+	endpoint, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return core.CVSyncResult{}, err
+	}
+	if endpoint.Type != core.EndpointCV {
+		return core.CVSyncResult{}, fmt.Errorf("endpoint %s is not a CloudVision endpoint", endpoint.Name)
+	}
+	endpoint, err = a.creds.Resolve(endpoint)
+	if err != nil {
+		return core.CVSyncResult{}, err
+	}
+
+	cvClient, err := a.cvClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.CVSyncResult{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	devices, err := cvClient.ListDevices(ctx, endpoint.URL, endpoint.Token)
+	if err != nil {
+		a.logger.Error("Failed to fetch CloudVision inventory", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return core.CVSyncResult{}, err
+	}
+
+	result, err := a.inventory.SyncCloudVision(endpoint, devices)
+	if err != nil {
+		a.logger.Error("Failed to sync CloudVision inventory", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return result, err
+	}
+
+	a.logger.Info("CloudVision inventory synced",
+		zap.String("cluster", result.Cluster),
+		zap.Int("devices", result.Devices),
+		zap.Int("created", len(result.Created)),
+		zap.Int("updated", len(result.Updated)),
+		zap.Int("missing", len(result.Missing)),
+	)
+	return result, nil
+}
+
+// cloudVisionEndpoint loads a CloudVision endpoint, resolves its credentials and
+// returns it with a client using its TLS settings
+func (a *App) cloudVisionEndpoint(endpointID string) (core.Endpoint, *client.CloudVisionClient, error) {
+	endpoint, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return core.Endpoint{}, nil, err
+	}
+	if endpoint.Type != core.EndpointCV {
+		return core.Endpoint{}, nil, fmt.Errorf("endpoint %s is not a CloudVision endpoint", endpoint.Name)
+	}
+	resolved, err := a.creds.Resolve(endpoint)
+	if err != nil {
+		return core.Endpoint{}, nil, err
+	}
+	cvClient, err := a.cvClient.ForEndpoint(resolved)
+	if err != nil {
+		return core.Endpoint{}, nil, err
+	}
+	return resolved, cvClient, nil
+}
+
+// GetCloudVisionTokenInfo decodes the expiry and identity of a CloudVision endpoint's token
+func (a *App) GetCloudVisionTokenInfo(endpointID string) (*core.TokenInfo, error) {
+	endpoint, _, err := a.cloudVisionEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	return client.ParseToken(endpoint.Token, time.Now())
+}
+
+// LoginCloudVision logs in with a username and password and stores the minted session
+// token on the endpoint. Empty credentials fall back to the endpoint's own.
+func (a *App) LoginCloudVision(endpointID, username, password string) (*core.TokenInfo, error) {
+	endpoint, cvClient, err := a.cloudVisionEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if username == "" {
+		username, password = endpoint.Username, endpoint.Password
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, err := cvClient.Login(ctx, endpoint.URL, username, password)
+	if err != nil {
+		a.logger.Error("CloudVision login failed", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return nil, err
+	}
+	if err := a.storeCloudVisionToken(endpointID, token); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("CloudVision session token stored", zap.String("endpoint", endpoint.Name), zap.String("user", username))
+	info, _ := client.ParseToken(token, time.Now())
+	return info, nil
+}
+
+// ListCloudVisionServiceTokens lists the service account tokens of a CloudVision cluster
+func (a *App) ListCloudVisionServiceTokens(endpointID string) ([]core.CVServiceToken, error) {
+	endpoint, cvClient, err := a.cloudVisionEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return cvClient.ListServiceAccountTokens(ctx, endpoint.URL, endpoint.Token)
+}
+
+// CreateCloudVisionServiceToken mints a token for a service account, valid for validDays.
+// The token is returned to the caller and not stored.
+func (a *App) CreateCloudVisionServiceToken(endpointID, user, description string, validDays int) (core.CVServiceToken, error) {
+	endpoint, cvClient, err := a.cloudVisionEndpoint(endpointID)
+	if err != nil {
+		return core.CVServiceToken{}, err
+	}
+	if validDays <= 0 {
+		return core.CVServiceToken{}, fmt.Errorf("validity must be at least one day")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, err := cvClient.CreateServiceAccountToken(ctx, endpoint.URL, endpoint.Token, user, description, time.Now().AddDate(0, 0, validDays))
+	if err != nil {
+		a.logger.Error("Failed to create service account token", zap.String("endpoint", endpoint.Name), zap.String("user", user), zap.Error(err))
+		return core.CVServiceToken{}, err
+	}
+
+	a.logger.Info("Service account token created", zap.String("endpoint", endpoint.Name), zap.String("user", user), zap.String("id", token.ID))
+	return token, nil
+}
+
+// RotateCloudVisionToken mints a new service account token with the endpoint's current
+// token, stores it where the current one lives and revokes the old token
+func (a *App) RotateCloudVisionToken(endpointID, user string, validDays int) (*core.TokenInfo, error) {
+	endpoint, cvClient, err := a.cloudVisionEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if validDays <= 0 {
+		return nil, fmt.Errorf("validity must be at least one day")
+	}
+	stored, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if a.secrets.IsReference(stored.Token) {
+		return nil, fmt.Errorf("the token of %s is held in an external secret store and must be rotated there", endpoint.Name)
+	}
+
+	old, _ := client.ParseToken(endpoint.Token, time.Now())
+	if user == "" && old != nil {
+		user = old.Subject
+	}
+	if user == "" {
+		return nil, fmt.Errorf("service account user is required")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	token, err := cvClient.CreateServiceAccountToken(ctx, endpoint.URL, endpoint.Token, user, "Rotated by Arista Engine", time.Now().AddDate(0, 0, validDays))
+	if err != nil {
+		a.logger.Error("Failed to rotate CloudVision token", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return nil, err
+	}
+
+	// Profile-backed tokens are rotated in the profile so every endpoint sharing it follows
+	if stored.Token == "" && stored.CredentialRef != "" {
+		if _, err := a.creds.Rotate(stored.CredentialRef, "", "", token.Token); err != nil {
+			return nil, err
+		}
+	} else if err := a.storeCloudVisionToken(endpointID, token.Token); err != nil {
+		return nil, err
+	}
+
+	// Revoke the previous token once the new one is in place
+	if old != nil && old.ID != "" && old.ID != token.ID {
+		if err := cvClient.DeleteServiceAccountToken(ctx, endpoint.URL, token.Token, old.ID); err != nil {
+			a.logger.Warn("Failed to revoke previous CloudVision token", zap.String("endpoint", endpoint.Name), zap.String("id", old.ID), zap.Error(err))
+		}
+	}
+
+	a.logger.Info("CloudVision token rotated", zap.String("endpoint", endpoint.Name), zap.String("user", user), zap.String("id", token.ID))
+	info, _ := client.ParseToken(token.Token, time.Now())
+	return info, nil
+}
+
+// storeCloudVisionToken saves a new bearer token on an endpoint
+func (a *App) storeCloudVisionToken(endpointID, token string) error {
+	endpoint, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return err
+	}
+	endpoint.Token = token
+	if err := a.inventory.UpdateEndpoint(endpoint); err != nil {
+		a.logger.Error("Failed to store CloudVision token", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// checkCloudVisionTokens warns about CloudVision tokens that have expired or expire soon
+func (a *App) checkCloudVisionTokens(ctx context.Context) {
+	endpoints, err := a.store.GetEndpoints()
+	if err != nil {
+		a.logger.Error("Failed to load endpoints for token check", zap.Error(err))
+		return
+	}
+
+	for _, ep := range endpoints {
+		if ep.Type != core.EndpointCV {
+			continue
+		}
+		resolved, err := a.creds.Resolve(ep)
+		if err != nil {
+			continue
+		}
+		info, err := client.ParseToken(resolved.Token, time.Now())
+		if err != nil || info.Warning == "" {
+			continue
+		}
+
+		a.logger.Warn("CloudVision token needs attention",
+			zap.String("endpoint", ep.Name),
+			zap.String("warning", info.Warning),
+		)
+		runtime.EventsEmit(ctx, "cloudvision:token", map[string]any{
+			"endpointId": ep.ID,
+			"name":       ep.Name,
+			"token":      info,
+		})
+	}
+}
+
+// GetTransportStats returns the request counters and circuit breaker state of the
+// eAPI and CloudVision endpoints contacted so far
+func (a *App) GetTransportStats() []core.TransportStats {
+	return append(a.eapiClient.Transports().Stats(), a.cvClient.Transports().Stats()...)
+}
+
+// ResetCircuitBreaker closes the circuit breaker of an endpoint so requests resume immediately
+func (a *App) ResetCircuitBreaker(endpointID string) error {
+	eapiReset := a.eapiClient.Transports().ResetCircuit(endpointID)
+	cvReset := a.cvClient.Transports().ResetCircuit(endpointID)
+	if !eapiReset && !cvReset {
+		return fmt.Errorf("no active connection for endpoint %s", endpointID)
+	}
+
+	a.logger.Info("Circuit breaker reset", zap.String("id", endpointID))
+	return nil
+}
+
+// cassetteDir holds recorded sessions
+const cassetteDir = "cassettes"
+
+// setInterceptor routes eAPI and CloudVision traffic through i, or straight to the network when nil
+func (a *App) setInterceptor(i client.Interceptor) {
+	a.eapiClient.Transports().SetInterceptor(i)
+	a.cvClient.Transports().SetInterceptor(i)
+}
+
+// StartRecording records all eAPI and CloudVision traffic, with secrets redacted,
+// until StopRecording saves it as a cassette
+func (a *App) StartRecording(name string) error {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	if a.recorder != nil || a.player != nil {
+		return fmt.Errorf("a session is already being recorded or replayed")
+	}
+	if strings.TrimSpace(name) == "" {
+		name = "session"
+	}
+
+	a.recorder = cassette.NewRecorder(name)
+	a.setInterceptor(a.recorder)
+	a.logger.Info("Recording started", zap.String("name", name))
+	return nil
+}
+
+// StopRecording stops recording and saves the cassette, returning its path
+func (a *App) StopRecording() (string, error) {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	if a.recorder == nil {
+		return "", fmt.Errorf("no recording in progress")
+	}
+
+	rec := a.recorder
+	a.setInterceptor(nil)
+	a.recorder = nil
+
+	path := filepath.Join(cassetteDir, cassette.FileName(rec.Name(), time.Now()))
+	if err := rec.Save(path); err != nil {
+		a.logger.Error("Failed to save cassette", zap.String("path", path), zap.Error(err))
+		return "", err
+	}
+	a.logger.Info("Recording saved", zap.String("path", path), zap.Int("interactions", rec.Len()))
+	return path, nil
+}
+
+// StartReplay answers all eAPI and CloudVision requests from a cassette instead of the
+// network. With realtime set, responses take as long as they did when recorded.
+func (a *App) StartReplay(path string, realtime bool) error {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	if a.recorder != nil || a.player != nil {
+		return fmt.Errorf("a session is already being recorded or replayed")
+	}
+
+	c, err := cassette.Load(path)
+	if err != nil {
+		a.logger.Error("Failed to load cassette", zap.String("path", path), zap.Error(err))
+		return err
+	}
+
+	a.player = cassette.NewPlayer(c, realtime)
+	a.replayPath = path
+	a.setInterceptor(a.player)
+	a.logger.Info("Replay started", zap.String("path", path), zap.Int("interactions", len(c.Interactions)))
+	return nil
+}
+
+// StopReplay returns to live traffic
+func (a *App) StopReplay() error {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	if a.player == nil {
+		return fmt.Errorf("no replay in progress")
+	}
+
+	a.setInterceptor(nil)
+	a.logger.Info("Replay stopped", zap.String("path", a.replayPath), zap.Int("missed", a.player.Missed()))
+	a.player = nil
+	a.replayPath = ""
+	return nil
+}
+
+// GetSessionStatus reports whether traffic is live, being recorded or replayed
+func (a *App) GetSessionStatus() core.SessionStatus {
+	a.sessionMu.Lock()
+	defer a.sessionMu.Unlock()
+	switch {
+	case a.recorder != nil:
+		return core.SessionStatus{Mode: core.SessionRecording, Name: a.recorder.Name(), Interactions: a.recorder.Len()}
+	case a.player != nil:
+		return core.SessionStatus{
+			Mode:         core.SessionReplay,
+			Name:         a.player.Name(),
+			Path:         a.replayPath,
+			Interactions: a.player.Len(),
+			Missed:       a.player.Missed(),
+		}
+	}
+	return core.SessionStatus{Mode: core.SessionLive}
+}
+
+// ListCassettes lists the recorded sessions, newest first
+func (a *App) ListCassettes() ([]cassette.Info, error) {
+	return cassette.List(cassetteDir)
+}
+
+// GetDeviceTestHistory retrieves the most recent connection test results of a device
+func (a *App) GetDeviceTestHistory(deviceID string, limit int) ([]core.TestHistoryEntry, error) {
+	return a.inventory.GetTestHistory(deviceID, limit)
+}
+
+// RefreshDeviceFacts collects facts from one eAPI endpoint and stores them on its inventory record
+func (a *App) RefreshDeviceFacts(endpointID string) (core.DeviceFacts, error) {
+	endpoint, err := a.store.GetEndpoint(endpointID)
+	if err != nil {
+		return core.DeviceFacts{}, err
+	}
+
+	facts, err := a.collector.RefreshEndpointFacts(context.Background(), endpoint)
+	if err != nil {
+		a.logger.Error("Failed to collect device facts", zap.String("endpoint", endpoint.Name), zap.Error(err))
+		return facts, err
+	}
+	return facts, nil
+}
+
+// RefreshAllDeviceFacts collects facts from every eAPI endpoint and returns the number refreshed
+func (a *App) RefreshAllDeviceFacts() (int, error) {
+	count, err := a.collector.RefreshFacts(context.Background())
+	if err != nil {
+		a.logger.Warn("Some device facts could not be collected", zap.Error(err))
+	}
+	return count, err
+}
+
+// StartFactsRefresh refreshes device facts from all eAPI endpoints on a schedule
+func (a *App) StartFactsRefresh(intervalMinutes int) error {
+	if err := a.collector.StartFactsRefresh(time.Duration(intervalMinutes) * time.Minute); err != nil {
+		return err
+	}
+	a.logger.Info("Device facts refresh started", zap.Int("intervalMinutes", intervalMinutes))
+	return nil
+}
+
+// StopFactsRefresh stops the scheduled device facts refresh
+func (a *App) StopFactsRefresh() {
+	a.collector.StopFactsRefresh()
+	a.logger.Info("Device facts refresh stopped")
+}
+
+// QueryDeviceInventory returns the inventory records matching a filter
+func (a *App) QueryDeviceInventory(filter core.InventoryFilter) ([]core.DeviceInventory, error) {
+	return a.inventory.Query(filter)
+}
+
+// GroupDeviceInventory returns the inventory records matching a filter, grouped by model, version, status, source or type
+func (a *App) GroupDeviceInventory(filter core.InventoryFilter, by string) ([]core.InventoryGroup, error) {
+	return a.inventory.Group(filter, by)
+}
+
+// DiscoverTopology polls LLDP neighbors on every eAPI endpoint and builds the topology graph
+func (a *App) DiscoverTopology() (core.Topology, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	result, err := a.topology.Discover(ctx)
+	if err != nil {
+		a.logger.Error("Topology discovery failed", zap.Error(err))
+		return result, err
+	}
+
+	a.logger.Info("Topology discovered",
+		zap.Int("nodes", len(result.Nodes)),
+		zap.Int("links", len(result.Links)),
+		zap.Int("errors", len(result.Errors)),
+	)
+	return result, nil
+}
+
+// GetTopology returns the latest topology snapshot
+func (a *App) GetTopology() (*core.Topology, error) {
+	return a.store.GetTopology()
+}
+
+// ExportTopology renders the latest topology snapshot as "dot", "graphml" or "json"
+func (a *App) ExportTopology(format string) (string, error) {
+	snapshot, err := a.store.GetTopology()
+	if err != nil {
+		return "", err
+	}
+	if snapshot == nil {
+		return "", fmt.Errorf("no topology has been discovered yet")
+	}
+
+	data, err := topology.Export(*snapshot, format)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WalkNeighbors explores outward from a seed endpoint over LLDP and stores the
+// discovered devices as candidates for review; nothing is added to the inventory
+func (a *App) WalkNeighbors(req core.NeighborWalkRequest) ([]core.DiscoveryCandidate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	candidates, err := a.topology.Walk(ctx, req)
+	if err != nil {
+		a.logger.Error("Neighbor walk failed", zap.String("seed", req.SeedEndpointID), zap.Error(err))
+		return redactCandidates(candidates), err
+	}
+	if err := a.store.SaveDiscoveryCandidates(candidates); err != nil {
+		return redactCandidates(candidates), err
+	}
+
+	a.logger.Info("Neighbor walk completed",
+		zap.String("seed", req.SeedEndpointID),
+		zap.Int("candidates", len(candidates)),
+	)
+	return redactCandidates(candidates), nil
+}
+
+// GetDiscoveryCandidates returns the discovered devices awaiting review
+func (a *App) GetDiscoveryCandidates() ([]core.DiscoveryCandidate, error) {
+	candidates, err := a.store.GetDiscoveryCandidates()
+	if err != nil {
+		return nil, err
+	}
+	return redactCandidates(candidates), nil
+}
+
+// redactCandidates removes the encrypted secrets of the seed proxy that candidates inherit
+func redactCandidates(candidates []core.DiscoveryCandidate) []core.DiscoveryCandidate {
+	for i := range candidates {
+		candidates[i].Endpoint = credentials.RedactProxy(candidates[i].Endpoint)
+	}
+	return candidates
+}
+
+// ApproveDiscoveryCandidates adds the selected candidates to the inventory as eAPI endpoints
+func (a *App) ApproveDiscoveryCandidates(candidateIDs []string) ([]core.Endpoint, error) {
+	candidates, err := a.store.GetDiscoveryCandidates()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]core.DiscoveryCandidate)
+	for _, c := range candidates {
+		byID[c.ID] = c
+	}
+
+	var added []core.Endpoint
+	for _, id := range candidateIDs {
+		candidate, ok := byID[id]
+		if !ok {
+			return added, fmt.Errorf("discovery candidate not found: %s", id)
+		}
+
+		endpoint := candidate.Endpoint
+		endpoint.ID = ""
+		endpoint, err := a.inventory.AddEndpoint(endpoint)
+		if err != nil {
+			a.logger.Error("Failed to add discovered endpoint", zap.String("candidate", id), zap.Error(err))
+			return added, err
+		}
+		if err := a.store.DeleteDiscoveryCandidate(id); err != nil {
+			a.logger.Warn("Failed to remove approved discovery candidate", zap.String("candidate", id), zap.Error(err))
+		}
+		added = append(added, credentials.RedactProxy(endpoint))
+		a.logger.Info("Discovered endpoint added", zap.String("id", endpoint.ID), zap.String("name", endpoint.Name))
+	}
+	return added, nil
+}
+
+// RejectDiscoveryCandidates discards the selected candidates
+func (a *App) RejectDiscoveryCandidates(candidateIDs []string) error {
+	for _, id := range candidateIDs {
+		if err := a.store.DeleteDiscoveryCandidate(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ServeDeviceInventory serves the device inventory as JSON for frontend consumption
 func (a *App) ServeDeviceInventory() ([]core.DeviceInventory, error) {
 	return a.store.GetDeviceInventory()
@@ -347,7 +1491,7 @@ func (a *App) TestAPIConnection(apiType, url, username, password, token string)
 
 	switch apiType {
 	case "eapi":
-		if username == "" || password == "" {
+		if (username == "" || password == "") && !client.IsLocalURL(url) {
 			return map[string]interface{}{
 				"success": false,
 				"message": "Authentication required",
@@ -375,7 +1519,7 @@ func (a *App) TestAPIConnection(apiType, url, username, password, token string)
 				"details": "API token is required for CloudVision",
 			}, nil
 		}
-		success, message, _, err := a.cvClient.TestConnection(ctx, url, token)
+		success, message, _, _, err := a.cvClient.TestConnection(ctx, url, token)
 		if err != nil {
 			return map[string]interface{}{
 				"success": false,
@@ -388,6 +1532,41 @@ func (a *App) TestAPIConnection(apiType, url, username, password, token string)
 			"message": message,
 			"details": fmt.Sprintf("Connected to CloudVision Portal at %s", url),
 		}, nil
+	case "telemetry":
+		success, message, _, err := a.gnmiClient.TestConnection(ctx, url, username, password)
+		if err != nil {
+			return map[string]interface{}{
+				"success": false,
+				"message": "Connection failed",
+				"details": err.Error(),
+			}, nil
+		}
+		return map[string]interface{}{
+			"success": success,
+			"message": message,
+			"details": fmt.Sprintf("Connected to gNMI target at %s", url),
+		}, nil
+	case "ssh":
+		if _, urlUser, _ := client.SSHTarget(url); username == "" && urlUser == "" {
+			return map[string]interface{}{
+				"success": false,
+				"message": "Authentication required",
+				"details": "Username is required for SSH",
+			}, nil
+		}
+		success, message, _, err := a.sshClient.TestConnection(ctx, url, username, password)
+		if err != nil {
+			return map[string]interface{}{
+				"success": false,
+				"message": "Connection failed",
+				"details": err.Error(),
+			}, nil
+		}
+		return map[string]interface{}{
+			"success": success,
+			"message": message,
+			"details": fmt.Sprintf("Connected to EOS CLI over SSH at %s", url),
+		}, nil
 	case "eos_rest":
 		if username == "" || password == "" {
 			return map[string]interface{}{
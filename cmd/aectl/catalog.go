@@ -0,0 +1,112 @@
+package main
+
+import (
+	"arista_engine/internal/enum"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runCatalog parses the API reference and prints coverage statistics, or checks the
+// parser against its golden files
+func runCatalog(args []string) error {
+	fs := flag.NewFlagSet("catalog", flag.ExitOnError)
+	doc := fs.String("doc", "Enumerated_API.md", "API reference to parse")
+	out := fs.String("out", "", "write the parsed catalog to this file")
+	verbose := fs.Bool("v", false, "list every unparsed line and suspected header")
+	asJSON := fs.Bool("json", false, "print the parse report as JSON")
+	check := fs.String("check", "", "compare the parse of every excerpt in this directory with its golden file, e.g. internal/enum/testdata")
+	update := fs.Bool("update", false, "with -check, rewrite the golden files")
+	diffFrom := fs.String("diff", "", "compare the catalog with the one parsed from this earlier release of the reference")
+	fs.Parse(args)
+
+	if *check != "" {
+		return checkGolden(*check, *update)
+	}
+	if *diffFrom != "" {
+		return diffDocuments(*diffFrom, *doc, *asJSON)
+	}
+
+	parser := enum.NewAPIParser()
+	if err := parser.ParseEnumeratedAPI(*doc); err != nil {
+		return err
+	}
+	report := parser.Report()
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		report.Print(os.Stdout, *verbose)
+	}
+
+	if *out != "" {
+		if err := parser.SaveCatalog(*out); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "catalog written to %s\n", *out)
+	}
+	return nil
+}
+
+// checkGolden runs the golden-file harness and fails if any excerpt differs
+func checkGolden(dir string, update bool) error {
+	results, err := enum.CheckGolden(dir, update)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range results {
+		switch {
+		case r.Updated:
+			fmt.Printf("updated %s\n", r.Name)
+		case r.OK:
+			fmt.Printf("ok      %s\n", r.Name)
+		default:
+			failed++
+			fmt.Printf("FAIL    %s\n%s", r.Name, r.Diff)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d golden files differ; rerun with -update if the change is intended", failed, len(results))
+	}
+	return nil
+}
+
+// diffDocuments parses two releases of the API reference and prints what changed
+func diffDocuments(fromDoc, toDoc string, asJSON bool) error {
+	from := enum.NewAPIParser()
+	if err := from.ParseEnumeratedAPI(fromDoc); err != nil {
+		return err
+	}
+	to := enum.NewAPIParser()
+	if err := to.ParseEnumeratedAPI(toDoc); err != nil {
+		return err
+	}
+
+	diff := enum.DiffCatalogs(from.GetCatalog(), to.GetCatalog())
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			return fmt.Errorf("failed to encode diff: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("%s -> %s: %d added, %d removed, %d changed\n", diff.From, diff.To, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	for _, def := range diff.Added {
+		fmt.Printf("+ %-6s %s\n", def.Method, def.Path)
+	}
+	for _, def := range diff.Removed {
+		fmt.Printf("- %-6s %s\n", def.Method, def.Path)
+	}
+	for _, change := range diff.Changed {
+		fmt.Printf("~ %-6s %s (%s)\n", change.Method, change.Path, strings.Join(change.Fields, ", "))
+	}
+	return nil
+}
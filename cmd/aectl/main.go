@@ -0,0 +1,47 @@
+// Command aectl runs Arista Engine tooling from the command line, without the desktop UI.
+//
+//	aectl catalog [flags]                 parse the API reference, report coverage or diff releases
+//	aectl mock [flags]                    serve an offline mock EOS device and CloudVision cluster
+//	aectl replay [flags] <cassette.json>  serve a recorded session as a device
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// commands maps subcommand names to their entry points
+var commands = map[string]func(args []string) error{
+	"catalog": runCatalog,
+	"mock":    runMock,
+	"replay":  runReplay,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "aectl: unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "aectl %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the available subcommands
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: aectl <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  catalog  parse the API reference and report coverage")
+	fmt.Fprintln(os.Stderr, "  mock     serve an offline mock EOS device and CloudVision cluster")
+	fmt.Fprintln(os.Stderr, "  replay   serve a recorded session as a device")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'aectl <command> -h' for the flags of a command.")
+}
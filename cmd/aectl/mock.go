@@ -0,0 +1,119 @@
+package main
+
+import (
+	"arista_engine/internal/mock"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"flag"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// runMock serves a mock device until interrupted
+func runMock(args []string) error {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:8443", "address to listen on")
+	useTLS := fs.Bool("tls", true, "serve HTTPS with a self-signed certificate, like EOS")
+	restDoc := fs.String("rest-doc", "Enumerated_API.md", "EOS REST API reference providing /vRest example data; empty disables /vRest")
+	var cfg mock.Config
+	fs.StringVar(&cfg.Username, "user", "admin", "eAPI and /vRest username; empty accepts any credentials")
+	fs.StringVar(&cfg.Password, "pass", "admin", "eAPI and /vRest password")
+	fs.StringVar(&cfg.Token, "token", "", "CloudVision bearer token; empty accepts any token")
+	fs.StringVar(&cfg.Hostname, "hostname", "", "hostname reported by the device")
+	fs.StringVar(&cfg.SerialNumber, "serial", "", "serial number reported by the device")
+	fs.IntVar(&cfg.LatencyMs, "latency", 0, "latency added to every response, in milliseconds")
+	fs.IntVar(&cfg.JitterMs, "jitter", 0, "random extra latency up to this many milliseconds")
+	fs.Float64Var(&cfg.ErrorRate, "error-rate", 0, "fraction of requests answered with HTTP 503 (0-1)")
+	fs.BoolVar(&cfg.FailAuth, "fail-auth", false, "reject every credential")
+	failCmds := fs.String("fail-cmds", "", "comma-separated commands answered with an eAPI error")
+	fs.Parse(args)
+
+	if cfg.ErrorRate < 0 || cfg.ErrorRate > 1 {
+		return fmt.Errorf("-error-rate must be between 0 and 1")
+	}
+	for _, cmd := range strings.Split(*failCmds, ",") {
+		if cmd = strings.TrimSpace(cmd); cmd != "" {
+			cfg.FailCommands = append(cfg.FailCommands, cmd)
+		}
+	}
+	if *restDoc != "" {
+		if _, err := os.Stat(*restDoc); err != nil {
+			log.Printf("REST API reference %s not found, /vRest disabled", *restDoc)
+			*restDoc = ""
+		}
+	}
+
+	server, err := mock.NewServer(cfg, *restDoc)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *listen, err)
+	}
+	scheme := "http"
+	if *useTLS {
+		cert, err := selfSignedCert()
+		if err != nil {
+			return err
+		}
+		ln = tls.NewListener(ln, &tls.Config{Certificates: []tls.Certificate{cert}})
+		scheme = "https"
+	}
+
+	httpServer := &http.Server{Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt)
+		<-stop
+		httpServer.Close()
+	}()
+
+	base := fmt.Sprintf("%s://%s", scheme, ln.Addr())
+	log.Printf("mock device listening on %s", base)
+	log.Printf("  eAPI:        %s/command-api", base)
+	log.Printf("  EOS REST:    %s/vRest/... (%d operations)", base, server.RESTOperations())
+	log.Printf("  CloudVision: %s/api/resources/...", base)
+	log.Printf("  faults:      GET/PUT %s/mock/config", base)
+
+	if err := httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	log.Printf("served %d requests", server.Requests())
+	return nil
+}
+
+// selfSignedCert creates a certificate for localhost, as EOS ships a self-signed one
+func selfSignedCert() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to generate key: %w", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "aectl mock", Organization: []string{"Arista Engine"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to create certificate: %w", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"arista_engine/internal/cassette"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// runReplay serves a recorded session as a device until interrupted
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	listen := fs.String("listen", "127.0.0.1:8443", "address to listen on")
+	useTLS := fs.Bool("tls", true, "serve HTTPS with a self-signed certificate, like EOS")
+	realtime := fs.Bool("realtime", false, "delay each response by its recorded duration")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aectl replay [flags] <cassette.json>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("a cassette file is required")
+	}
+
+	c, err := cassette.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	player := cassette.NewPlayer(c, *realtime)
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", *listen, err)
+	}
+	scheme := "http"
+	if *useTLS {
+		cert, err := selfSignedCert()
+		if err != nil {
+			return err
+		}
+		ln = tls.NewListener(ln, &tls.Config{Certificates: []tls.Certificate{cert}})
+		scheme = "https"
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp, err := player.Intercept(r, nil)
+		if err != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.RequestURI(), err)
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		defer resp.Body.Close()
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	})
+	httpServer := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt)
+		<-stop
+		httpServer.Close()
+	}()
+
+	log.Printf("replaying %q (%d interactions, recorded %s) on %s://%s",
+		c.Name, len(c.Interactions), c.RecordedAt.Format(time.RFC3339), scheme, ln.Addr())
+	if err := httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	log.Printf("%d requests had no recorded interaction", player.Missed())
+	return nil
+}
@@ -0,0 +1,424 @@
+package alert
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/store"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Flap suppression: an alert that changes state FlapThreshold times within FlapWindow
+// is marked as flapping and its notifications are held until it is stable for a full window.
+const (
+	FlapWindow    = 10 * time.Minute
+	FlapThreshold = 4
+)
+
+// NotifyQueueSize is the number of alert events waiting for delivery before new
+// events are dropped
+const NotifyQueueSize = 256
+
+// Notifier receives alert state changes
+type Notifier interface {
+	Notify(event core.AlertEvent) error
+}
+
+// NotifierFunc adapts a function to the Notifier interface
+type NotifierFunc func(event core.AlertEvent) error
+
+// Notify calls f(event)
+func (f NotifierFunc) Notify(event core.AlertEvent) error {
+	return f(event)
+}
+
+// point is a single observed value kept for windowed evaluation
+type point struct {
+	ts    time.Time
+	value any
+}
+
+// state tracks one rule/device/path combination
+type state struct {
+	ruleID      string
+	device      string
+	path        string
+	firing      bool
+	flapping    bool
+	since       time.Time
+	value       any
+	transitions []time.Time
+	history     []point
+}
+
+// compiledRule pairs a rule with its parsed condition
+type compiledRule struct {
+	rule core.AlertRule
+	cond *Condition
+}
+
+// Engine evaluates alert rules against incoming samples
+type Engine struct {
+	store *store.Store
+
+	mu        sync.Mutex
+	rules     []compiledRule
+	states    map[string]*state
+	notifiers []Notifier
+	onError   func(error)
+	closed    bool
+
+	queue chan core.AlertEvent
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewEngine creates a new alert engine and loads the stored rules
+func NewEngine(store *store.Store) (*Engine, error) {
+	e := &Engine{
+		store:   store,
+		states:  make(map[string]*state),
+		onError: func(error) {},
+		queue:   make(chan core.AlertEvent, NotifyQueueSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+	go e.dispatch()
+	return e, nil
+}
+
+// Close stops accepting samples and waits for queued events to be delivered
+func (e *Engine) Close() {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.closed = true
+	e.mu.Unlock()
+
+	close(e.stop)
+	<-e.done
+}
+
+// Reload reloads rules from the store, dropping state for rules that no longer exist
+func (e *Engine) Reload() error {
+	rules, err := e.store.GetAlertRules()
+	if err != nil {
+		return fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	var compiled []compiledRule
+	for _, r := range rules {
+		cond, err := ParseCondition(r.Expression)
+		if err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		compiled = append(compiled, compiledRule{rule: r, cond: cond})
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = compiled
+
+	known := make(map[string]bool)
+	for _, r := range compiled {
+		known[r.rule.ID] = true
+	}
+	for key, st := range e.states {
+		if !known[st.ruleID] {
+			delete(e.states, key)
+		}
+	}
+	return nil
+}
+
+// AddNotifier registers a notifier for alert state changes
+func (e *Engine) AddNotifier(n Notifier) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notifiers = append(e.notifiers, n)
+}
+
+// OnError sets a callback for notifier and persistence errors
+func (e *Engine) OnError(fn func(error)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onError = fn
+}
+
+// Observe evaluates every matching rule against a sample
+func (e *Engine) Observe(sample core.Sample) {
+	var events []core.AlertEvent
+
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	for _, cr := range e.rules {
+		if !cr.rule.Enabled || !cr.cond.MatchesPath(sample.Path) || !matchesDevice(cr.rule.Device, sample.Device) {
+			continue
+		}
+
+		key := cr.rule.ID + "|" + sample.Device + "|" + sample.Path
+		st, ok := e.states[key]
+		if !ok {
+			st = &state{ruleID: cr.rule.ID, device: sample.Device, path: sample.Path}
+			e.states[key] = st
+		}
+
+		value, active, ok := evaluate(cr.cond, st, sample)
+		if !ok {
+			continue
+		}
+		if active == st.firing {
+			if event, settled := settle(cr.rule, st, sample); settled {
+				events = append(events, event)
+			}
+			continue
+		}
+
+		if event, notify := transition(cr.rule, st, sample, value, active); notify {
+			events = append(events, event)
+		}
+	}
+
+	// Persistence and notifiers can be slow; keep them off the sample path. Queueing
+	// under the lock keeps Close from stopping the worker between the check and the send.
+	var dropped []core.AlertEvent
+	for _, event := range events {
+		select {
+		case e.queue <- event:
+		default:
+			dropped = append(dropped, event)
+		}
+	}
+	onError := e.onError
+	e.mu.Unlock()
+
+	for _, event := range dropped {
+		onError(fmt.Errorf("alert notification queue is full, dropping %s event for rule %s", event.State, event.RuleID))
+	}
+}
+
+// dispatch saves queued events and delivers them to the notifiers until Close
+func (e *Engine) dispatch() {
+	defer close(e.done)
+	for {
+		select {
+		case event := <-e.queue:
+			e.deliver(event)
+		case <-e.stop:
+			for {
+				select {
+				case event := <-e.queue:
+					e.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver saves one event and sends it to every notifier
+func (e *Engine) deliver(event core.AlertEvent) {
+	e.mu.Lock()
+	notifiers := append([]Notifier(nil), e.notifiers...)
+	onError := e.onError
+	e.mu.Unlock()
+
+	if err := e.store.SaveAlertEvent(event); err != nil {
+		onError(fmt.Errorf("failed to save alert event: %w", err))
+	}
+	for _, n := range notifiers {
+		if err := n.Notify(event); err != nil {
+			onError(fmt.Errorf("failed to send alert notification: %w", err))
+		}
+	}
+}
+
+// evaluate records the sample in the state history and reports whether the condition holds
+func evaluate(cond *Condition, st *state, sample core.Sample) (any, bool, bool) {
+	st.history = append(st.history, point{ts: sample.Timestamp, value: sample.Value})
+	cutoff := sample.Timestamp.Add(-cond.Window)
+	for len(st.history) > 1 && st.history[0].ts.Before(cutoff) {
+		st.history = st.history[1:]
+	}
+
+	if !cond.IsNumber {
+		text := fmt.Sprint(sample.Value)
+		return text, cond.compareText(text), true
+	}
+
+	latest, ok := core.SampleFloat(sample.Value)
+	if !ok {
+		return nil, false, false
+	}
+
+	switch cond.Function {
+	case "":
+		return latest, cond.compareNumber(latest), true
+	case "delta", "rate":
+		first, ok := core.SampleFloat(st.history[0].value)
+		if !ok || len(st.history) < 2 {
+			return nil, false, false
+		}
+		delta := latest - first
+		if delta < 0 {
+			// Counter was cleared or wrapped; treat the latest value as the delta
+			delta = latest
+		}
+		if cond.Function == "rate" {
+			seconds := sample.Timestamp.Sub(st.history[0].ts).Seconds()
+			if seconds <= 0 {
+				return nil, false, false
+			}
+			delta = delta / seconds
+		}
+		return delta, cond.compareNumber(delta), true
+	default:
+		var values []float64
+		for _, p := range st.history {
+			if v, ok := core.SampleFloat(p.value); ok {
+				values = append(values, v)
+			}
+		}
+		agg := values[0]
+		for _, v := range values[1:] {
+			switch cond.Function {
+			case "min":
+				agg = math.Min(agg, v)
+			case "max":
+				agg = math.Max(agg, v)
+			case "avg":
+				agg += v
+			}
+		}
+		if cond.Function == "avg" {
+			agg = agg / float64(len(values))
+		}
+		return agg, cond.compareNumber(agg), true
+	}
+}
+
+// transition applies a state change and decides whether it should be notified
+func transition(rule core.AlertRule, st *state, sample core.Sample, value any, active bool) (core.AlertEvent, bool) {
+	now := sample.Timestamp
+	st.firing = active
+	st.since = now
+	st.value = value
+
+	// Track recent transitions for flap detection
+	st.transitions = append(st.transitions, now)
+	cutoff := now.Add(-FlapWindow)
+	for len(st.transitions) > 0 && st.transitions[0].Before(cutoff) {
+		st.transitions = st.transitions[1:]
+	}
+
+	wasFlapping := st.flapping
+	st.flapping = len(st.transitions) >= FlapThreshold
+
+	stateName := "resolved"
+	if active {
+		stateName = "firing"
+	}
+	event := core.AlertEvent{
+		ID:        fmt.Sprintf("alert_%d", time.Now().UnixNano()),
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Device:    sample.Device,
+		Path:      sample.Path,
+		State:     stateName,
+		Severity:  rule.Severity,
+		Value:     value,
+		Message:   fmt.Sprintf("%s %s on %s (%s = %v)", rule.Name, stateName, sample.Device, sample.Path, value),
+		Flapping:  st.flapping,
+		Timestamp: now,
+	}
+
+	// Notify once when flapping starts, then stay quiet until it settles
+	if st.flapping {
+		if !wasFlapping {
+			event.Message = fmt.Sprintf("%s is flapping on %s (%s)", rule.Name, sample.Device, sample.Path)
+			return event, true
+		}
+		return event, false
+	}
+	return event, true
+}
+
+// settle clears the flapping flag once an alert has been stable for a full flap window
+// and reports its current state, since notifications were held while it flapped
+func settle(rule core.AlertRule, st *state, sample core.Sample) (core.AlertEvent, bool) {
+	if !st.flapping || sample.Timestamp.Sub(st.since) < FlapWindow {
+		return core.AlertEvent{}, false
+	}
+	st.flapping = false
+	st.transitions = nil
+
+	stateName := "resolved"
+	if st.firing {
+		stateName = "firing"
+	}
+	return core.AlertEvent{
+		ID:        fmt.Sprintf("alert_%d", time.Now().UnixNano()),
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		Device:    sample.Device,
+		Path:      sample.Path,
+		State:     stateName,
+		Severity:  rule.Severity,
+		Value:     st.value,
+		Message:   fmt.Sprintf("%s stopped flapping on %s and is %s", rule.Name, sample.Device, stateName),
+		Timestamp: sample.Timestamp,
+	}, true
+}
+
+// Active returns the currently firing alerts
+func (e *Engine) Active() []core.AlertEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rules := make(map[string]core.AlertRule)
+	for _, cr := range e.rules {
+		rules[cr.rule.ID] = cr.rule
+	}
+
+	var active []core.AlertEvent
+	for _, st := range e.states {
+		if !st.firing {
+			continue
+		}
+		rule := rules[st.ruleID]
+		active = append(active, core.AlertEvent{
+			RuleID:    st.ruleID,
+			RuleName:  rule.Name,
+			Device:    st.device,
+			Path:      st.path,
+			State:     "firing",
+			Severity:  rule.Severity,
+			Value:     st.value,
+			Flapping:  st.flapping,
+			Timestamp: st.since,
+		})
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].Timestamp.Before(active[j].Timestamp) })
+	return active
+}
+
+// matchesDevice reports whether a rule's device selector matches a device ID
+func matchesDevice(selector, device string) bool {
+	if selector == "" {
+		return true
+	}
+	ok, _ := path.Match(selector, device)
+	return ok
+}
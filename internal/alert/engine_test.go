@@ -0,0 +1,212 @@
+package alert
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/store"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestEngine creates an engine over a temporary store holding the given rules
+func newTestEngine(t *testing.T, rules ...core.AlertRule) (*Engine, *store.Store) {
+	t.Helper()
+	s, err := store.NewStore(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	for _, r := range rules {
+		if err := s.SaveAlertRule(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e, err := NewEngine(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Close)
+	e.OnError(func(err error) { t.Error(err) })
+	return e, s
+}
+
+// recorder collects the events delivered to a notifier
+type recorder struct {
+	mu     sync.Mutex
+	events []core.AlertEvent
+}
+
+// Notify records an event
+func (r *recorder) Notify(event core.AlertEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// states returns the recorded states, with a "~" suffix for flapping events
+func (r *recorder) states() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var states []string
+	for _, e := range r.events {
+		s := e.State
+		if e.Flapping {
+			s += "~"
+		}
+		states = append(states, s)
+	}
+	return states
+}
+
+func TestEvaluateWindows(t *testing.T) {
+	base := time.Now()
+	type sample struct {
+		at    time.Duration
+		value any
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		samples []sample
+		value   any
+		active  bool
+		ok      bool
+	}{
+		{"delta needs two samples", "inErrors delta > 100", []sample{{0, 50.0}}, nil, false, false},
+		{"delta over the window", "inErrors delta > 100", []sample{{0, 100.0}, {time.Minute, 150.0}, {2 * time.Minute, 300.0}}, 200.0, true, true},
+		{"delta drops samples outside the window", "inErrors delta > 100 in 5m",
+			[]sample{{0, 0.0}, {6 * time.Minute, 1000.0}, {7 * time.Minute, 1050.0}}, 50.0, false, true},
+		{"delta after a counter reset", "inErrors delta > 100", []sample{{0, 1000.0}, {time.Minute, 40.0}}, 40.0, false, true},
+		{"rate per second", "inOctets rate > 5 in 2m", []sample{{0, 0.0}, {time.Minute, 600.0}}, 10.0, true, true},
+		{"rate over the retained window", "inOctets rate > 5 in 1m",
+			[]sample{{0, 0.0}, {2 * time.Minute, 1200.0}, {150 * time.Second, 1500.0}}, 10.0, true, true},
+		{"avg", "temperature avg > 40", []sample{{0, 30.0}, {time.Minute, 50.0}, {2 * time.Minute, 55.0}}, 45.0, true, true},
+		{"min", "fanSpeed min < 1000", []sample{{0, 3000.0}, {time.Minute, 800.0}, {2 * time.Minute, 2000.0}}, 800.0, true, true},
+		{"max", "memUsed max >= 90 in 1m", []sample{{0, 95.0}, {2 * time.Minute, 70.0}, {150 * time.Second, 80.0}}, 80.0, false, true},
+		{"latest value", "cpu > 90", []sample{{0, 95.0}, {time.Minute, int64(50)}}, 50.0, false, true},
+		{"text value", "peerState != Established", []sample{{0, "Active"}}, "Active", true, true},
+		{"non-numeric sample", "cpu > 90", []sample{{0, "n/a"}}, nil, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := ParseCondition(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			st := &state{}
+			var (
+				value  any
+				active bool
+				ok     bool
+			)
+			for _, s := range tt.samples {
+				value, active, ok = evaluate(cond, st, core.Sample{Path: cond.Metric, Value: s.value, Timestamp: base.Add(s.at)})
+			}
+			if value != tt.value || active != tt.active || ok != tt.ok {
+				t.Errorf("evaluate = %v, %v, %v; want %v, %v, %v", value, active, ok, tt.value, tt.active, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEngineDedup(t *testing.T) {
+	rule := core.AlertRule{ID: "r1", Name: "high cpu", Device: "leaf*", Expression: "cpu > 90", Severity: "warning", Enabled: true}
+	disabled := core.AlertRule{ID: "r2", Name: "any cpu", Expression: "cpu > 0", Enabled: false}
+	e, s := newTestEngine(t, rule, disabled)
+	rec := &recorder{}
+	e.AddNotifier(rec)
+
+	base := time.Now()
+	samples := []struct {
+		device string
+		value  float64
+	}{
+		{"leaf1", 50},
+		{"leaf1", 95}, // firing
+		{"leaf1", 97},
+		{"spine1", 99}, // not selected by the rule
+		{"leaf1", 99},
+		{"leaf1", 40}, // resolved
+		{"leaf1", 30},
+		{"leaf2", 91}, // firing on another device
+	}
+	for i, smp := range samples {
+		e.Observe(core.Sample{Device: smp.device, Path: "/system/cpu", Value: smp.value, Timestamp: base.Add(time.Duration(i) * time.Second)})
+	}
+
+	active := e.Active()
+	if len(active) != 1 || active[0].Device != "leaf2" {
+		t.Errorf("active alerts = %+v, want leaf2 only", active)
+	}
+
+	e.Close()
+	want := []string{"firing", "resolved", "firing"}
+	if got := rec.states(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("notified %v, want %v", got, want)
+	}
+	history, err := s.GetAlertHistory(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(want) {
+		t.Errorf("history holds %d events, want %d", len(history), len(want))
+	}
+}
+
+func TestEngineFlapSuppression(t *testing.T) {
+	rule := core.AlertRule{ID: "r1", Name: "link", Expression: "operStatus != UP", Severity: "critical", Enabled: true}
+	e, _ := newTestEngine(t, rule)
+	rec := &recorder{}
+	e.AddNotifier(rec)
+
+	base := time.Now()
+	at := base
+	observe := func(value string) {
+		e.Observe(core.Sample{Device: "leaf1", Path: "/interfaces/Ethernet1/operStatus", Value: value, Timestamp: at})
+	}
+
+	// Toggle faster than FlapThreshold transitions per FlapWindow
+	for i := 0; i < FlapThreshold+2; i++ {
+		if i%2 == 0 {
+			observe("DOWN")
+		} else {
+			observe("UP")
+		}
+		at = at.Add(10 * time.Second)
+	}
+	if active := e.Active(); len(active) != 0 {
+		t.Errorf("active alerts = %+v, want none after the last UP", active)
+	}
+
+	// Settled for less than a full window: still held
+	observe("DOWN")
+	last := at
+	at = last.Add(FlapWindow / 2)
+	observe("DOWN")
+	// Stable for a full window: the current state is reported once
+	at = last.Add(FlapWindow)
+	observe("DOWN")
+	at = at.Add(time.Minute)
+	observe("DOWN")
+
+	e.Close()
+	want := []string{
+		"firing", "resolved", "firing",
+		"resolved~", // transition FlapThreshold starts flapping
+		"firing",    // stopped flapping, currently firing
+	}
+	got := rec.states()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("notified %v, want %v", got, want)
+	}
+	if msg := rec.events[3].Message; !strings.Contains(msg, "is flapping") {
+		t.Errorf("flapping message = %q", msg)
+	}
+	if msg := rec.events[4].Message; !strings.Contains(msg, "stopped flapping") {
+		t.Errorf("settle message = %q", msg)
+	}
+}
@@ -0,0 +1,133 @@
+package alert
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultWindow is used for windowed functions when the expression has no "in" clause
+const defaultWindow = 5 * time.Minute
+
+// Condition is a parsed rule expression of the form
+//
+//	<metric> [delta|rate|avg|min|max] <op> <value> [in <duration>]
+//
+// for example "inErrors delta > 100 in 5m", "peerState != Established" or "state != ok".
+// The metric matches the last segment of a sample path, or the whole path when it
+// contains a '/'; glob patterns are allowed in both forms.
+type Condition struct {
+	Metric   string
+	Function string // empty for the latest value
+	Op       string
+	Number   float64
+	Text     string
+	IsNumber bool
+	Window   time.Duration
+}
+
+// ParseCondition parses a rule expression
+func ParseCondition(expr string) (*Condition, error) {
+	fields := strings.Fields(expr)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid expression %q: expected <metric> [function] <op> <value> [in <duration>]", expr)
+	}
+
+	cond := &Condition{Metric: fields[0], Window: defaultWindow}
+	rest := fields[1:]
+
+	switch rest[0] {
+	case "delta", "rate", "avg", "min", "max":
+		cond.Function = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("invalid expression %q: missing operator or value", expr)
+	}
+
+	switch rest[0] {
+	case ">", ">=", "<", "<=", "==", "!=":
+		cond.Op = rest[0]
+	default:
+		return nil, fmt.Errorf("invalid expression %q: unknown operator %q", expr, rest[0])
+	}
+	rest = rest[1:]
+
+	// The value runs until an optional "in <duration>" clause
+	valueEnd := len(rest)
+	for i, f := range rest {
+		if f == "in" && i > 0 {
+			valueEnd = i
+			break
+		}
+	}
+	value := strings.Trim(strings.Join(rest[:valueEnd], " "), `"'`)
+	if n, err := strconv.ParseFloat(value, 64); err == nil {
+		cond.Number = n
+		cond.IsNumber = true
+	} else {
+		cond.Text = value
+	}
+
+	if valueEnd < len(rest) {
+		if valueEnd+2 != len(rest) {
+			return nil, fmt.Errorf("invalid expression %q: expected a single duration after 'in'", expr)
+		}
+		window, err := time.ParseDuration(rest[valueEnd+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		cond.Window = window
+	}
+
+	if !cond.IsNumber {
+		if cond.Function != "" {
+			return nil, fmt.Errorf("invalid expression %q: %s requires a numeric threshold", expr, cond.Function)
+		}
+		if cond.Op != "==" && cond.Op != "!=" {
+			return nil, fmt.Errorf("invalid expression %q: text values only support == and !=", expr)
+		}
+	}
+
+	return cond, nil
+}
+
+// MatchesPath reports whether a sample path is selected by the condition's metric
+func (c *Condition) MatchesPath(p string) bool {
+	if strings.Contains(c.Metric, "/") {
+		ok, _ := path.Match(c.Metric, p)
+		return ok
+	}
+	ok, _ := path.Match(c.Metric, path.Base(p))
+	return ok
+}
+
+// compareNumber applies the operator to a numeric value
+func (c *Condition) compareNumber(v float64) bool {
+	switch c.Op {
+	case ">":
+		return v > c.Number
+	case ">=":
+		return v >= c.Number
+	case "<":
+		return v < c.Number
+	case "<=":
+		return v <= c.Number
+	case "==":
+		return v == c.Number
+	case "!=":
+		return v != c.Number
+	}
+	return false
+}
+
+// compareText applies the operator to a text value (case-insensitive)
+func (c *Condition) compareText(v string) bool {
+	equal := strings.EqualFold(v, c.Text)
+	if c.Op == "==" {
+		return equal
+	}
+	return !equal
+}
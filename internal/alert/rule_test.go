@@ -0,0 +1,109 @@
+package alert
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Condition
+	}{
+		{"cpu > 90", Condition{Metric: "cpu", Op: ">", Number: 90, IsNumber: true, Window: defaultWindow}},
+		{"inErrors delta > 100 in 5m", Condition{Metric: "inErrors", Function: "delta", Op: ">", Number: 100, IsNumber: true, Window: 5 * time.Minute}},
+		{"inOctets rate >= 1.5e6 in 30s", Condition{Metric: "inOctets", Function: "rate", Op: ">=", Number: 1.5e6, IsNumber: true, Window: 30 * time.Second}},
+		{"temperature avg <= 45", Condition{Metric: "temperature", Function: "avg", Op: "<=", Number: 45, IsNumber: true, Window: defaultWindow}},
+		{"fanSpeed min < 1000 in 1m", Condition{Metric: "fanSpeed", Function: "min", Op: "<", Number: 1000, IsNumber: true, Window: time.Minute}},
+		{"memUsed max == 100", Condition{Metric: "memUsed", Function: "max", Op: "==", Number: 100, IsNumber: true, Window: defaultWindow}},
+		{"peerState != Established", Condition{Metric: "peerState", Op: "!=", Text: "Established", Window: defaultWindow}},
+		{`operStatus == "link down" in 2m`, Condition{Metric: "operStatus", Op: "==", Text: "link down", Window: 2 * time.Minute}},
+		{"interfaces/*/state/oper-status != UP", Condition{Metric: "interfaces/*/state/oper-status", Op: "!=", Text: "UP", Window: defaultWindow}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseCondition(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{"", "expected <metric>"},
+		{"cpu >", "expected <metric>"},
+		{"cpu delta >", "missing operator or value"},
+		{"cpu => 90", "unknown operator"},
+		{"cpu > 90 in", "single duration"},
+		{"cpu > 90 in 5m 10m", "single duration"},
+		{"cpu > 90 in soon", "invalid duration"},
+		{"state delta > up", "requires a numeric threshold"},
+		{"state > up", "only support == and !="},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseCondition(tt.expr)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("got %v, want an error containing %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMatchesPath(t *testing.T) {
+	tests := []struct {
+		metric string
+		path   string
+		want   bool
+	}{
+		{"inErrors", "/interfaces/interface[name=Ethernet1]/state/counters/inErrors", true},
+		{"in*", "/interfaces/interface[name=Ethernet1]/state/counters/inErrors", true},
+		{"outErrors", "/interfaces/interface[name=Ethernet1]/state/counters/inErrors", false},
+		{"/system/*/cpu", "/system/state/cpu", true},
+		{"/system/*/cpu", "/system/state/memory", false},
+	}
+	for _, tt := range tests {
+		cond := &Condition{Metric: tt.metric}
+		if got := cond.MatchesPath(tt.path); got != tt.want {
+			t.Errorf("MatchesPath(%q, %q) = %v, want %v", tt.metric, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	numbers := []struct {
+		op    string
+		value float64
+		want  bool
+	}{
+		{">", 11, true}, {">", 10, false},
+		{">=", 10, true}, {">=", 9, false},
+		{"<", 9, true}, {"<", 10, false},
+		{"<=", 10, true}, {"<=", 11, false},
+		{"==", 10, true}, {"==", 11, false},
+		{"!=", 11, true}, {"!=", 10, false},
+	}
+	for _, tt := range numbers {
+		cond := &Condition{Op: tt.op, Number: 10, IsNumber: true}
+		if got := cond.compareNumber(tt.value); got != tt.want {
+			t.Errorf("%v %s 10 = %v, want %v", tt.value, tt.op, got, tt.want)
+		}
+	}
+
+	// Text comparison ignores case
+	eq := &Condition{Op: "==", Text: "Established"}
+	ne := &Condition{Op: "!=", Text: "Established"}
+	if !eq.compareText("established") || ne.compareText("ESTABLISHED") || !ne.compareText("Active") {
+		t.Error("text comparison should be case-insensitive")
+	}
+}
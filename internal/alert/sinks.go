@@ -0,0 +1,171 @@
+package alert
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// NewSink creates a notifier for a configured alert sink
+func NewSink(sink core.AlertSink) (Notifier, error) {
+	switch sink.Type {
+	case "webhook":
+		u, err := url.Parse(sink.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook URL: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("webhook URL must use http or https: %q", sink.Address)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("webhook URL has no host: %q", sink.Address)
+		}
+		return &WebhookSink{url: sink.Address, http: &http.Client{Timeout: 10 * time.Second}}, nil
+	case "syslog":
+		network := sink.Network
+		if network == "" {
+			network = "udp"
+		}
+		if network != "udp" && network != "tcp" {
+			return nil, fmt.Errorf("unsupported syslog network: %s", network)
+		}
+		return &SyslogSink{network: network, address: sink.Address}, nil
+	default:
+		return nil, fmt.Errorf("unsupported alert sink type: %s", sink.Type)
+	}
+}
+
+// SinkSet delivers alert events to the enabled configured sinks. The sinks are built
+// once by Set and reused until the configuration changes.
+type SinkSet struct {
+	mu    sync.RWMutex
+	sinks []configuredSink
+}
+
+// configuredSink pairs a sink configuration with its notifier
+type configuredSink struct {
+	config   core.AlertSink
+	notifier Notifier
+}
+
+// Set replaces the sinks with the enabled ones in configs. Invalid sinks are skipped
+// and reported in the returned error.
+func (s *SinkSet) Set(configs []core.AlertSink) error {
+	var sinks []configuredSink
+	var errs []error
+	for _, cfg := range configs {
+		if !cfg.Enabled {
+			continue
+		}
+		n, err := NewSink(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid alert sink %s: %w", cfg.ID, err))
+			continue
+		}
+		sinks = append(sinks, configuredSink{config: cfg, notifier: n})
+	}
+
+	s.mu.Lock()
+	s.sinks = sinks
+	s.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Notify sends the event to every sink, returning the delivery failures
+func (s *SinkSet) Notify(event core.AlertEvent) error {
+	s.mu.RLock()
+	sinks := s.sinks
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, sink := range sinks {
+		if err := sink.notifier.Notify(event); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deliver alert to %s sink %s: %w", sink.config.Type, sink.config.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WebhookSink posts alert events as JSON to an HTTP endpoint
+type WebhookSink struct {
+	url  string
+	http *http.Client
+}
+
+// Notify posts the event to the webhook URL
+func (w *WebhookSink) Notify(event core.AlertEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert event: %w", err)
+	}
+
+	resp, err := w.http.Post(w.url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SyslogSink sends alert events as RFC 5424 syslog messages over UDP or TCP
+type SyslogSink struct {
+	network string
+	address string
+}
+
+// Notify sends the event to the syslog server
+func (s *SyslogSink) Notify(event core.AlertEvent) error {
+	conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	// Facility local0 (16); TCP uses octet-counting framing (RFC 6587)
+	msg := fmt.Sprintf("<%d>1 %s %s arista_engine - %s - %s",
+		16*8+syslogSeverity(event.Severity, event.State),
+		event.Timestamp.UTC().Format(time.RFC3339),
+		hostname,
+		event.RuleID,
+		event.Message,
+	)
+	if s.network == "tcp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Write([]byte(msg))
+	return err
+}
+
+// syslogSeverity maps an alert severity to a syslog severity level
+func syslogSeverity(severity, state string) int {
+	if state == "resolved" {
+		return 5 // notice
+	}
+	switch severity {
+	case "critical":
+		return 2
+	case "warning":
+		return 4
+	default:
+		return 6 // informational
+	}
+}
@@ -0,0 +1,31 @@
+package alert
+
+import (
+	"arista_engine/internal/core"
+	"testing"
+)
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		sink  core.AlertSink
+		valid bool
+	}{
+		{core.AlertSink{Type: "webhook", Address: "https://hooks.example.net/alerts"}, true},
+		{core.AlertSink{Type: "webhook", Address: "http://10.0.0.5:8080/notify"}, true},
+		{core.AlertSink{Type: "webhook", Address: ""}, false},
+		{core.AlertSink{Type: "webhook", Address: "hooks.example.net/alerts"}, false},
+		{core.AlertSink{Type: "webhook", Address: "ftp://hooks.example.net/alerts"}, false},
+		{core.AlertSink{Type: "webhook", Address: "https:///alerts"}, false},
+		{core.AlertSink{Type: "webhook", Address: "http://[::1"}, false},
+		{core.AlertSink{Type: "syslog", Address: "10.0.0.5:514"}, true},
+		{core.AlertSink{Type: "syslog", Network: "tcp", Address: "10.0.0.5:514"}, true},
+		{core.AlertSink{Type: "syslog", Network: "unix", Address: "/dev/log"}, false},
+		{core.AlertSink{Type: "email", Address: "noc@example.net"}, false},
+	}
+	for _, tt := range tests {
+		_, err := NewSink(tt.sink)
+		if (err == nil) != tt.valid {
+			t.Errorf("NewSink(%s %q) error = %v, want valid %v", tt.sink.Type, tt.sink.Address, err, tt.valid)
+		}
+	}
+}
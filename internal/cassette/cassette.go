@@ -0,0 +1,157 @@
+// Package cassette records the HTTP traffic of the eAPI and CloudVision clients into
+// cassette files and replays it later without the device. Secrets are redacted before
+// anything is written: credential headers, and password, token and similar keys of
+// JSON bodies.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the cassette file format
+const FormatVersion = 1
+
+// Redacted replaces secret values in recorded traffic
+const Redacted = "[REDACTED]"
+
+// Cassette is a recorded session
+type Cassette struct {
+	Version      int           `json:"version"`
+	Name         string        `json:"name"`
+	RecordedAt   time.Time     `json:"recordedAt"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one request and the response or error it produced
+type Interaction struct {
+	Request    Request   `json:"request"`
+	Response   *Response `json:"response,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	ElapsedMs  int64     `json:"elapsedMs"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Request is a recorded request
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded message body. JSON documents are stored inline so cassettes stay
+// readable and can serve as fixtures; anything else, such as the newline-delimited
+// streams of the CloudVision resource APIs, is stored as a string.
+type Body []byte
+
+// MarshalJSON stores a JSON body inline and any other body as a string
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, b); err == nil {
+			return buf.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON reads an inline JSON body or a string
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Info summarizes a cassette file
+type Info struct {
+	Path         string    `json:"path"`
+	Name         string    `json:"name"`
+	RecordedAt   time.Time `json:"recordedAt"`
+	Interactions int       `json:"interactions"`
+}
+
+// Load reads a cassette file
+func Load(path string) (*Cassette, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+	if c.Version > FormatVersion {
+		return nil, fmt.Errorf("cassette %s has format version %d, newer than supported %d", path, c.Version, FormatVersion)
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating its directory
+func (c *Cassette) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	raw, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// List summarizes the cassettes in dir, newest first. A missing directory has none.
+func List(dir string) ([]Info, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cassettes: %w", err)
+	}
+	infos := make([]Info, 0, len(paths))
+	for _, path := range paths {
+		c, err := Load(path)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, Info{Path: path, Name: c.Name, RecordedAt: c.RecordedAt, Interactions: len(c.Interactions)})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].RecordedAt.After(infos[j].RecordedAt) })
+	return infos, nil
+}
+
+// FileName returns a file name for a cassette recorded at t, e.g. leaf1_20240102-150405.json
+func FileName(name string, t time.Time) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+	if clean == "" {
+		clean = "session"
+	}
+	return clean + "_" + t.Format("20060102-150405") + ".json"
+}
@@ -0,0 +1,177 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Player answers requests from a cassette instead of the network. Requests match a
+// recorded interaction by method, path, query and body; the host is ignored so a
+// session replays against any endpoint URL. Repeated requests replay their recordings
+// in order, and the last one again once they run out. It is safe for concurrent use.
+type Player struct {
+	cassette *Cassette
+	realtime bool
+
+	mu     sync.Mutex
+	queues map[string][]int // match key -> interaction indexes, in recorded order
+	next   map[string]int
+	missed int
+}
+
+// NewPlayer creates a player for a cassette. With realtime set, each response is
+// delayed by its recorded duration.
+func NewPlayer(c *Cassette, realtime bool) *Player {
+	p := &Player{
+		cassette: c,
+		realtime: realtime,
+		queues:   make(map[string][]int),
+		next:     make(map[string]int),
+	}
+	for i, in := range c.Interactions {
+		key := matchKey(in.Request.Method, in.Request.URL, in.Request.Body)
+		p.queues[key] = append(p.queues[key], i)
+	}
+	return p
+}
+
+// Name returns the name of the replayed session
+func (p *Player) Name() string {
+	return p.cassette.Name
+}
+
+// Len returns the number of recorded interactions
+func (p *Player) Len() int {
+	return len(p.cassette.Interactions)
+}
+
+// Missed returns how many requests had no recorded interaction
+func (p *Player) Missed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.missed
+}
+
+// Intercept answers the request from the cassette; next is never called
+func (p *Player) Intercept(req *http.Request, _ http.RoundTripper) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	in, ok := p.take(matchKey(req.Method, redactURL(req.URL), redactBody(body)))
+	if !ok {
+		return nil, fmt.Errorf("no recorded interaction in cassette %q for %s %s", p.cassette.Name, req.Method, req.URL.RequestURI())
+	}
+
+	if p.realtime && in.ElapsedMs > 0 {
+		select {
+		case <-time.After(time.Duration(in.ElapsedMs) * time.Millisecond):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if in.Response == nil {
+		return nil, fmt.Errorf("replayed error: %s", in.Error)
+	}
+
+	respBody := []byte(in.Response.Body)
+	// eAPI checks that a response answers its request; recorded IDs are from another session
+	if id, ok := jsonRPCID(body); ok {
+		respBody = withJSONRPCID(respBody, id)
+	}
+	header := in.Response.Headers.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+		StatusCode:    in.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+// take returns the next recorded interaction for a match key
+func (p *Player) take(key string) (Interaction, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	queue := p.queues[key]
+	if len(queue) == 0 {
+		p.missed++
+		return Interaction{}, false
+	}
+	n := p.next[key]
+	if n < len(queue)-1 {
+		p.next[key] = n + 1
+	}
+	return p.cassette.Interactions[queue[n]], true
+}
+
+// matchKey identifies a request by method, path, query and normalized body
+func matchKey(method, rawURL string, body []byte) string {
+	target := rawURL
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+		if j := strings.IndexByte(target, '/'); j >= 0 {
+			target = target[j:]
+		} else {
+			target = "/"
+		}
+	}
+	return method + " " + target + "\n" + normalizeBody(body)
+}
+
+// normalizeBody canonicalizes a JSON body, dropping the JSON-RPC request ID, which
+// differs between sessions. Other bodies are used as they are.
+func normalizeBody(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	if m, ok := v.(map[string]any); ok && m["jsonrpc"] != nil {
+		delete(m, "id")
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(out)
+}
+
+// jsonRPCID returns the ID of a JSON-RPC request body
+func jsonRPCID(body []byte) (json.RawMessage, bool) {
+	var msg struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(body, &msg) != nil || msg.JSONRPC == "" || msg.ID == nil {
+		return nil, false
+	}
+	return msg.ID, true
+}
+
+// withJSONRPCID sets the ID of a JSON-RPC response body
+func withJSONRPCID(body []byte, id json.RawMessage) []byte {
+	var msg map[string]json.RawMessage
+	if json.Unmarshal(body, &msg) != nil || msg["jsonrpc"] == nil {
+		return body
+	}
+	msg["id"] = id
+	out, err := json.Marshal(msg)
+	if err != nil {
+		return body
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxBodySize caps the recorded size of a body; larger bodies are passed through untouched
+const maxBodySize = 64 << 20
+
+// Recorder captures the traffic it sees into a cassette. It is safe for concurrent use.
+type Recorder struct {
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a recorder for a named session
+func NewRecorder(name string) *Recorder {
+	return &Recorder{cassette: Cassette{Version: FormatVersion, Name: name, RecordedAt: time.Now().UTC()}}
+}
+
+// Intercept sends the request over next and records it with its outcome
+func (r *Recorder) Intercept(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	in := Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     redactURL(req.URL),
+			Headers: redactHeaders(req.Header),
+			Body:    redactBody(reqBody),
+		},
+		RecordedAt: start.UTC(),
+	}
+	if err != nil {
+		in.ElapsedMs = time.Since(start).Milliseconds()
+		in.Error = err.Error()
+		r.add(in)
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	in.ElapsedMs = time.Since(start).Milliseconds()
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to read response for recording: %w", err)
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(respBody), resp.Body), resp.Body}
+
+	in.Response = &Response{
+		Status:  resp.StatusCode,
+		Headers: redactHeaders(resp.Header),
+		Body:    redactBody(respBody),
+	}
+	r.add(in)
+	return resp, nil
+}
+
+// add appends an interaction
+func (r *Recorder) add(in Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+}
+
+// Name returns the session name
+func (r *Recorder) Name() string {
+	return r.cassette.Name
+}
+
+// Len returns the number of recorded interactions
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.cassette.Interactions)
+}
+
+// Cassette returns a copy of the recording so far
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c := r.cassette
+	c.Interactions = append([]Interaction(nil), r.cassette.Interactions...)
+	return &c
+}
+
+// Save writes the recording so far to path
+func (r *Recorder) Save(path string) error {
+	return r.Cassette().Save(path)
+}
+
+// readRequestBody reads the request body and puts an unread copy back
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request for recording: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
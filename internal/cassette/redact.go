@@ -0,0 +1,220 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// secretHeaders carry credentials and are never recorded
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// secretKeys are JSON keys whose values are redacted, compared case-insensitively.
+// "input" carries the enable password of eAPI commands.
+var secretKeys = map[string]bool{
+	"password":      true,
+	"passwd":        true,
+	"secret":        true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"sessionid":     true,
+	"session_id":    true,
+	"input":         true,
+}
+
+// cliSecret matches a secret argument of an EOS configuration command, such as
+// "username admin secret 0 s3cret", "enable secret sha512 $6$...",
+// "tacacs-server key 7 0207165218" or "snmp-server community public ro". The optional
+// encryption type is kept. Arguments never span lines, so command output can be scrubbed too.
+var cliSecret = regexp.MustCompile(`(?i)(\b(?:secret|password|authentication-key)[ \t]+(?:(?:0|5|7|8a|sha512)[ \t]+)?|\bkey[ \t]+(?:0|7|8a)[ \t]+|\bsnmp-server[ \t]+community[ \t]+)\S+`)
+
+// redactHeaders copies headers with credential values replaced
+func redactHeaders(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := h.Clone()
+	for _, name := range secretHeaders {
+		values := out.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		redacted := make([]string, len(values))
+		for i, v := range values {
+			// Keep the scheme so a replay still shows how the client authenticated
+			if scheme, _, ok := strings.Cut(v, " "); ok && (strings.EqualFold(scheme, "Basic") || strings.EqualFold(scheme, "Bearer")) {
+				redacted[i] = scheme + " " + Redacted
+			} else {
+				redacted[i] = Redacted
+			}
+		}
+		out[http.CanonicalHeaderKey(name)] = redacted
+	}
+	return out
+}
+
+// redactURL removes user info and secret query parameters from a URL
+func redactURL(u *url.URL) string {
+	clean := *u
+	if clean.User != nil {
+		clean.User = url.User(Redacted)
+	}
+	if clean.RawQuery != "" {
+		q := clean.Query()
+		changed := false
+		for key := range q {
+			if secretKeys[strings.ToLower(key)] {
+				q.Set(key, Redacted)
+				changed = true
+			}
+		}
+		if changed {
+			clean.RawQuery = q.Encode()
+		}
+	}
+	return clean.String()
+}
+
+// redactBody replaces secret values in a JSON body, or in every line of a JSON stream.
+// Other bodies are returned unchanged.
+func redactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if doc, ok := redactDocument(body); ok {
+		return doc
+	}
+	lines := strings.Split(string(body), "\n")
+	changed := false
+	for i, line := range lines {
+		if doc, ok := redactDocument([]byte(line)); ok {
+			lines[i] = string(doc)
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// redactDocument redacts one JSON document, reporting false if it is not JSON
+func redactDocument(raw []byte) ([]byte, bool) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, false
+	}
+	if !redactValue(v) {
+		return raw, true
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, false
+	}
+	return out, true
+}
+
+// redactValue replaces secret values in place, reporting whether anything changed
+func redactValue(v any) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]any:
+		for key, val := range t {
+			if strings.EqualFold(key, "cmds") {
+				if redactCommands(val) {
+					changed = true
+				}
+				continue
+			}
+			// Text responses carry the CLI output, which may echo the configuration
+			if text, ok := val.(string); ok && strings.EqualFold(key, "output") {
+				if clean := redactCommand(text); clean != text {
+					t[key] = clean
+					changed = true
+				}
+				continue
+			}
+			if secretKeys[strings.ToLower(key)] {
+				if _, isString := val.(string); isString {
+					t[key] = Redacted
+					changed = true
+					continue
+				}
+			}
+			if redactValue(val) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, item := range t {
+			if redactValue(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactCommands scrubs secrets inside the eAPI commands of a runCmds request, given
+// either as strings or as {"cmd": ..., "input": ...} objects, and inside the command
+// maps of a JSON running-config response
+func redactCommands(v any) bool {
+	if config, ok := v.(map[string]any); ok {
+		return redactCommandKeys(config)
+	}
+	cmds, ok := v.([]any)
+	if !ok {
+		return redactValue(v)
+	}
+	changed := false
+	for i, item := range cmds {
+		switch cmd := item.(type) {
+		case string:
+			if clean := redactCommand(cmd); clean != cmd {
+				cmds[i] = clean
+				changed = true
+			}
+		case map[string]any:
+			if text, ok := cmd["cmd"].(string); ok {
+				if clean := redactCommand(text); clean != text {
+					cmd["cmd"] = clean
+					changed = true
+				}
+			}
+			if redactValue(cmd) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactCommandKeys scrubs secrets in the keys of a running-config command map such as
+// {"username admin secret sha512 $6$...": null, "interface Ethernet1": {"cmds": {...}}}
+func redactCommandKeys(config map[string]any) bool {
+	changed := false
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		val := config[key]
+		if clean := redactCommand(key); clean != key {
+			delete(config, key)
+			config[clean] = val
+			changed = true
+		}
+		if redactValue(val) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// redactCommand replaces the secret arguments of one CLI command
+func redactCommand(cmd string) string {
+	return cliSecret.ReplaceAllString(cmd, "${1}"+Redacted)
+}
@@ -0,0 +1,146 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestRedactCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want string
+	}{
+		{"show version", "show version"},
+		{"username admin secret s3cret", "username admin secret [REDACTED]"},
+		{"username admin privilege 15 secret 0 s3cret", "username admin privilege 15 secret 0 [REDACTED]"},
+		{"username ops role network-operator secret sha512 $6$salt$hash", "username ops role network-operator secret sha512 [REDACTED]"},
+		{"enable secret 5 $1$abc$def", "enable secret 5 [REDACTED]"},
+		{"enable password s3cret", "enable password [REDACTED]"},
+		{"neighbor 10.0.0.1 password 7 0207165218", "neighbor 10.0.0.1 password 7 [REDACTED]"},
+		{"ip ospf authentication-key 7 0207165218", "ip ospf authentication-key 7 [REDACTED]"},
+		{"tacacs-server host 10.1.1.1 key 7 0207165218", "tacacs-server host 10.1.1.1 key 7 [REDACTED]"},
+		{"snmp-server community public ro", "snmp-server community [REDACTED] ro"},
+		{"neighbor 10.0.0.1 send-community extended", "neighbor 10.0.0.1 send-community extended"},
+		{"crypto key generate rsa", "crypto key generate rsa"},
+		{"show running-config | include secret", "show running-config | include secret"},
+	}
+	for _, tt := range tests {
+		if got := redactCommand(tt.cmd); got != tt.want {
+			t.Errorf("redactCommand(%q) = %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := `{"jsonrpc":"2.0","method":"runCmds","id":"1","params":{"version":1,"format":"json","cmds":[` +
+		`{"cmd":"enable","input":"enablepw"},` +
+		`"configure",` +
+		`"username admin secret 0 s3cret",` +
+		`{"cmd":"enable secret sha512 $6$salt$hash"},` +
+		`"end"]}}`
+
+	var got map[string]any
+	if err := json.Unmarshal(redactBody([]byte(body)), &got); err != nil {
+		t.Fatal(err)
+	}
+	cmds := got["params"].(map[string]any)["cmds"]
+	want := []any{
+		map[string]any{"cmd": "enable", "input": Redacted},
+		"configure",
+		"username admin secret 0 " + Redacted,
+		map[string]any{"cmd": "enable secret sha512 " + Redacted},
+		"end",
+	}
+	if !reflect.DeepEqual(cmds, want) {
+		t.Errorf("cmds = %v, want %v", cmds, want)
+	}
+
+	// Nested secret keys and JSON streams
+	stream := "{\"token\":\"abc\"}\n{\"user\":{\"password\":\"pw\",\"name\":\"admin\"}}"
+	wantStream := "{\"token\":\"[REDACTED]\"}\n{\"user\":{\"name\":\"admin\",\"password\":\"[REDACTED]\"}}"
+	if got := string(redactBody([]byte(stream))); got != wantStream {
+		t.Errorf("stream = %q, want %q", got, wantStream)
+	}
+
+	// Other bodies pass through
+	if got := string(redactBody([]byte("plain text"))); got != "plain text" {
+		t.Errorf("text body changed: %q", got)
+	}
+}
+
+func TestRedactResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want any
+	}{
+		{
+			name: "text output",
+			body: `{"jsonrpc":"2.0","id":"1","result":[{"output":"hostname leaf1\nusername admin secret sha512 $6$salt$hash\nsnmp-server community public ro\n"}]}`,
+			want: []any{map[string]any{"output": "hostname leaf1\nusername admin secret sha512 " + Redacted + "\nsnmp-server community " + Redacted + " ro\n"}},
+		},
+		{
+			name: "running-config commands",
+			body: `{"jsonrpc":"2.0","id":"1","result":[{"header":["! device: leaf1"],"cmds":{` +
+				`"username admin privilege 15 secret 0 s3cret":null,` +
+				`"snmp-server community public ro":null,` +
+				`"router bgp 65001":{"cmds":{"neighbor 10.0.0.1 password 7 0207165218":null,"neighbor 10.0.0.1 send-community":null}},` +
+				`"hostname leaf1":null}}]}`,
+			want: []any{map[string]any{
+				"header": []any{"! device: leaf1"},
+				"cmds": map[string]any{
+					"username admin privilege 15 secret 0 " + Redacted: nil,
+					"snmp-server community " + Redacted + " ro":        nil,
+					"router bgp 65001": map[string]any{"cmds": map[string]any{
+						"neighbor 10.0.0.1 password 7 " + Redacted: nil,
+						"neighbor 10.0.0.1 send-community":         nil,
+					}},
+					"hostname leaf1": nil,
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			if err := json.Unmarshal(redactBody([]byte(tt.body)), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got["result"], tt.want) {
+				t.Errorf("result = %v, want %v", got["result"], tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "Basic YWRtaW46c2VjcmV0")
+	h.Set("Cookie", "session=abc")
+	h.Set("Content-Type", "application/json")
+
+	got := redactHeaders(h)
+	if v := got.Get("Authorization"); v != "Basic "+Redacted {
+		t.Errorf("Authorization = %q", v)
+	}
+	if v := got.Get("Cookie"); v != Redacted {
+		t.Errorf("Cookie = %q", v)
+	}
+	if v := got.Get("Content-Type"); v != "application/json" {
+		t.Errorf("Content-Type = %q", v)
+	}
+	if h.Get("Cookie") != "session=abc" {
+		t.Error("redactHeaders modified its input")
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://admin:pw@cvp.example.net/api/v1?token=abc&limit=10")
+	want := "https://%5BREDACTED%5D@cvp.example.net/api/v1?limit=10&token=%5BREDACTED%5D"
+	if got := redactURL(u); got != want {
+		t.Errorf("redactURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,348 @@
+// Package catalog keeps the active API catalog. It is built from the API reference and
+// from imported catalog files, persisted in the store, and rebuilt when the reference
+// changes.
+package catalog
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/enum"
+	"arista_engine/internal/store"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReferenceID identifies the API reference among the catalog sources
+const ReferenceID = "reference"
+
+// ProgressHandler receives progress of catalog rebuilds and imports
+type ProgressHandler func(core.CatalogProgress)
+
+// Manager builds the active catalog from its sources. Imported catalogs are merged
+// under the reference, which wins when both define the same key. Lookups go to a
+// parser that a build replaces rather than modifies, so they never see a partial catalog.
+type Manager struct {
+	store   *store.Store
+	docPath string
+
+	mu       sync.Mutex // serializes builds and imports
+	active   atomic.Pointer[enum.APIParser]
+	building atomic.Bool
+
+	hmu      sync.Mutex
+	handlers []ProgressHandler
+}
+
+// NewManager creates a catalog manager for the API reference at docPath
+func NewManager(store *store.Store, docPath string) *Manager {
+	m := &Manager{store: store, docPath: docPath}
+	m.active.Store(enum.NewAPIParser())
+	return m
+}
+
+// OnProgress registers a handler for rebuild and import progress
+func (m *Manager) OnProgress(h ProgressHandler) {
+	m.hmu.Lock()
+	defer m.hmu.Unlock()
+	m.handlers = append(m.handlers, h)
+}
+
+// emit hands progress to every registered handler
+func (m *Manager) emit(p core.CatalogProgress) {
+	m.hmu.Lock()
+	handlers := append([]ProgressHandler(nil), m.handlers...)
+	m.hmu.Unlock()
+
+	for _, h := range handlers {
+		h(p)
+	}
+}
+
+// fail reports a failed build of a source and returns err
+func (m *Manager) fail(source string, err error) error {
+	m.emit(core.CatalogProgress{Source: source, Stage: "failed", Error: err.Error()})
+	return err
+}
+
+// Parser returns a parser holding the active catalog, for lookups
+func (m *Manager) Parser() *enum.APIParser {
+	return m.active.Load()
+}
+
+// Catalog returns the active catalog
+func (m *Manager) Catalog() *core.APICatalog {
+	return m.Parser().GetCatalog()
+}
+
+// Building reports whether a rebuild is running
+func (m *Manager) Building() bool {
+	return m.building.Load()
+}
+
+// Load activates the catalog stored by the last build and reports whether it needs a
+// rebuild: there is none, or the API reference changed since
+func (m *Manager) Load() (bool, error) {
+	catalog, err := m.store.GetAPICatalog()
+	stale := err != nil
+	if err == nil {
+		parser := enum.NewAPIParser()
+		parser.SetCatalog(catalog)
+		m.active.Store(parser)
+	}
+
+	hash, err := fileHash(m.docPath)
+	if err != nil {
+		return false, err
+	}
+	sources, err := m.store.GetCatalogSources()
+	if err != nil {
+		return false, err
+	}
+	for _, source := range sources {
+		if source.ID == ReferenceID {
+			return stale || source.Hash != hash, nil
+		}
+	}
+	return true, nil
+}
+
+// Rebuild parses the API reference, stores its catalog and version, and activates the
+// merged catalog
+func (m *Manager) Rebuild() error {
+	if !m.building.CompareAndSwap(false, true) {
+		return errors.New("a catalog rebuild is already running")
+	}
+	defer m.building.Store(false)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hash, err := fileHash(m.docPath)
+	if err != nil {
+		return m.fail(ReferenceID, err)
+	}
+
+	parser := enum.NewAPIParser()
+	parser.SetProgress(func(line, total int) {
+		m.emit(core.CatalogProgress{Source: ReferenceID, Stage: "parsing", Line: line, TotalLines: total})
+	})
+	if err := parser.ParseEnumeratedAPI(m.docPath); err != nil {
+		return m.fail(ReferenceID, err)
+	}
+	catalog := parser.GetCatalog()
+	report := parser.Report()
+
+	m.emit(core.CatalogProgress{Source: ReferenceID, Stage: "saving", Endpoints: report.Endpoints})
+	source := core.CatalogSource{
+		ID:        ReferenceID,
+		Kind:      core.CatalogSourceReference,
+		Path:      m.docPath,
+		Hash:      hash,
+		Version:   catalog.SourceVersion,
+		Endpoints: report.Endpoints,
+		Built:     time.Now(),
+	}
+	if err := m.store.SaveCatalogSource(source, catalog); err != nil {
+		return m.fail(ReferenceID, err)
+	}
+	// Keep every catalog version for diffs between releases
+	if catalog.SourceVersion != "" {
+		if err := m.store.SaveCatalogVersion(catalog); err != nil {
+			return m.fail(ReferenceID, err)
+		}
+	}
+	if err := m.activate(); err != nil {
+		return m.fail(ReferenceID, err)
+	}
+
+	m.emit(core.CatalogProgress{Source: ReferenceID, Stage: "done", Endpoints: report.Endpoints})
+	return nil
+}
+
+// RebuildInBackground starts a rebuild; its outcome is reported as progress
+func (m *Manager) RebuildInBackground() {
+	go m.Rebuild()
+}
+
+// Import merges a catalog JSON file, such as configs/complete_api_catalog.json, into the
+// active catalog. Importing the same file again replaces its earlier import.
+func (m *Manager) Import(path string) (core.CatalogSource, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.importFile(path)
+}
+
+// ImportIfChanged imports a catalog file unless the same contents were imported before
+func (m *Manager) ImportIfChanged(path string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hash, err := fileHash(path)
+	if err != nil {
+		return false, err
+	}
+	sources, err := m.store.GetCatalogSources()
+	if err != nil {
+		return false, err
+	}
+	for _, source := range sources {
+		if source.ID == sourceID(path) && source.Hash == hash {
+			return false, nil
+		}
+	}
+	if _, err := m.importFile(path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// importFile imports a catalog file; the caller holds m.mu
+func (m *Manager) importFile(path string) (core.CatalogSource, error) {
+	id := sourceID(path)
+	if id == ReferenceID {
+		return core.CatalogSource{}, m.fail(id, fmt.Errorf("%q is reserved for the API reference", id))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return core.CatalogSource{}, m.fail(id, fmt.Errorf("failed to read catalog file: %w", err))
+	}
+	var catalog core.APICatalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return core.CatalogSource{}, m.fail(id, fmt.Errorf("failed to parse catalog file %s: %w", path, err))
+	}
+	endpoints := countEndpoints(&catalog)
+	if endpoints == 0 {
+		return core.CatalogSource{}, m.fail(id, fmt.Errorf("no endpoints in catalog file %s", path))
+	}
+
+	sum := sha256.Sum256(data)
+	source := core.CatalogSource{
+		ID:        id,
+		Kind:      core.CatalogSourceImport,
+		Path:      path,
+		Hash:      hex.EncodeToString(sum[:]),
+		Version:   catalog.SourceVersion,
+		Endpoints: endpoints,
+		Built:     time.Now(),
+	}
+	if err := m.store.SaveCatalogSource(source, &catalog); err != nil {
+		return core.CatalogSource{}, m.fail(id, err)
+	}
+	if err := m.activate(); err != nil {
+		return core.CatalogSource{}, m.fail(id, err)
+	}
+
+	m.emit(core.CatalogProgress{Source: id, Stage: "done", Endpoints: endpoints})
+	return source, nil
+}
+
+// RemoveSource drops an imported catalog from the active catalog
+func (m *Manager) RemoveSource(id string) error {
+	if id == ReferenceID {
+		return errors.New("the API reference cannot be removed")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.store.DeleteCatalogSource(id); err != nil {
+		return err
+	}
+	return m.activate()
+}
+
+// Sources lists the sources of the active catalog, the reference first
+func (m *Manager) Sources() ([]core.CatalogSource, error) {
+	sources, err := m.store.GetCatalogSources()
+	if err != nil {
+		return nil, err
+	}
+	sortSources(sources)
+	return sources, nil
+}
+
+// activate merges the stored source catalogs, saves the result as the active catalog and
+// publishes it; the caller holds m.mu
+func (m *Manager) activate() error {
+	sources, err := m.store.GetCatalogSources()
+	if err != nil {
+		return err
+	}
+	sortSources(sources)
+
+	merged := &core.APICatalog{
+		EAPI:        make(map[string]core.APIDefinition),
+		CloudVision: make(map[string]core.APIDefinition),
+		EOSREST:     make(map[string]core.APIDefinition),
+		Telemetry:   make(map[string]core.APIDefinition),
+		LastUpdated: time.Now(),
+	}
+	// Imports are merged first so the reference overrides them
+	for i := len(sources) - 1; i >= 0; i-- {
+		catalog, err := m.store.GetSourceCatalog(sources[i].ID)
+		if err != nil {
+			return err
+		}
+		mergeInto(merged.EAPI, catalog.EAPI)
+		mergeInto(merged.CloudVision, catalog.CloudVision)
+		mergeInto(merged.EOSREST, catalog.EOSREST)
+		mergeInto(merged.Telemetry, catalog.Telemetry)
+		if sources[i].ID == ReferenceID {
+			merged.Source, merged.SourceVersion = catalog.Source, catalog.SourceVersion
+		}
+	}
+
+	if err := m.store.SaveAPICatalog(merged); err != nil {
+		return err
+	}
+	parser := enum.NewAPIParser()
+	parser.SetCatalog(merged)
+	m.active.Store(parser)
+	return nil
+}
+
+// mergeInto copies definitions into dst, replacing existing keys
+func mergeInto(dst, src map[string]core.APIDefinition) {
+	for key, def := range src {
+		dst[key] = def
+	}
+}
+
+// countEndpoints counts the definitions of a catalog
+func countEndpoints(c *core.APICatalog) int {
+	return len(c.EAPI) + len(c.CloudVision) + len(c.EOSREST) + len(c.Telemetry)
+}
+
+// sortSources orders sources with the reference first, then imports by ID
+func sortSources(sources []core.CatalogSource) {
+	sort.Slice(sources, func(i, j int) bool {
+		if (sources[i].ID == ReferenceID) != (sources[j].ID == ReferenceID) {
+			return sources[i].ID == ReferenceID
+		}
+		return sources[i].ID < sources[j].ID
+	})
+}
+
+// sourceID derives a source ID from a catalog file name, e.g. complete_api_catalog
+func sourceID(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// fileHash returns the hex SHA-256 of a file
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
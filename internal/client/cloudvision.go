@@ -1,26 +1,51 @@
 package client
 
 import (
+	"arista_engine/internal/core"
 	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // CloudVisionClient handles communication with Arista CloudVision
 type CloudVisionClient struct {
-	http *http.Client
+	http       *http.Client
+	transports *TransportCache
 }
 
 // NewCloudVisionClient creates a new CloudVision client
 func NewCloudVisionClient(tlsVerify bool, timeout time.Duration) *CloudVisionClient {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: !tlsVerify},
+	tr := newPooledTransport(&tls.Config{InsecureSkipVerify: !tlsVerify}, DefaultPolicy.MaxConcurrent)
+	transports := NewTransportCache(timeout)
+	return &CloudVisionClient{
+		http:       &http.Client{Transport: transports.wrap(tr), Timeout: timeout},
+		transports: transports,
 	}
-	return &CloudVisionClient{http: &http.Client{Transport: tr, Timeout: timeout}}
+}
+
+// ForEndpoint returns a client that uses the endpoint's TLS settings
+func (c *CloudVisionClient) ForEndpoint(endpoint core.Endpoint) (*CloudVisionClient, error) {
+	httpClient, err := c.transports.Client(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &CloudVisionClient{http: httpClient, transports: c.transports}, nil
+}
+
+// ForgetEndpoint drops the cached transport of an endpoint
+func (c *CloudVisionClient) ForgetEndpoint(endpointID string) {
+	c.transports.Forget(endpointID)
+}
+
+// Transports returns the per-endpoint transport layer, for stats and circuit control
+func (c *CloudVisionClient) Transports() *TransportCache {
+	return c.transports
 }
 
 // DoREST performs a REST API call to CloudVision
@@ -48,22 +73,58 @@ func (c *CloudVisionClient) DoREST(ctx context.Context, method, url, bearer stri
 	return resp, time.Since(start), err
 }
 
-// TestConnection tests the connection to CloudVision
-func (c *CloudVisionClient) TestConnection(ctx context.Context, baseURL, token string) (bool, string, time.Duration, error) {
+// TestConnection tests the connection to CloudVision and reports the HTTP status.
+// Rejected tokens are told apart as expired (401 after exp), invalid (401) or
+// lacking permission (403).
+func (c *CloudVisionClient) TestConnection(ctx context.Context, baseURL, token string) (bool, string, int, time.Duration, error) {
+	if token == "" {
+		return false, "No API token configured", 0, 0, nil
+	}
+	info, _ := ParseToken(token, time.Now())
+
 	// Test with a simple API call
 	testURL := baseURL + "/api/resources/inventory/v1/Devices?limit=1"
 	
 	resp, elapsed, err := c.DoREST(ctx, "GET", testURL, token, nil)
 	if err != nil {
-		return false, err.Error(), elapsed, err
+		return false, err.Error(), 0, elapsed, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode == 200 {
-		return true, "Connection successful", elapsed, nil
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if info != nil && info.Warning != "" {
+			return true, "Connection successful, but " + info.Warning, resp.StatusCode, elapsed, nil
+		}
+		return true, "Connection successful", resp.StatusCode, elapsed, nil
+	case http.StatusUnauthorized:
+		if info != nil && info.Expired {
+			return false, "Token expired on " + info.ExpiresAt.Format(time.RFC3339), resp.StatusCode, elapsed, nil
+		}
+		return false, "Token rejected: invalid or revoked (HTTP 401)", resp.StatusCode, elapsed, nil
+	case http.StatusForbidden:
+		return false, "Token valid but not permitted to read the inventory (HTTP 403)", resp.StatusCode, elapsed, nil
 	}
 
-	return false, "Connection failed", elapsed, nil
+	return false, fmt.Sprintf("Connection failed (HTTP %d)", resp.StatusCode), resp.StatusCode, elapsed, nil
+}
+
+// GetVersion retrieves the CloudVision cluster version
+func (c *CloudVisionClient) GetVersion(ctx context.Context, baseURL, token string) (*core.DeviceMetadata, error) {
+	resp, _, err := c.DoREST(ctx, "GET", baseURL+"/cvpservice/cvpInfo/getCvpInfo.do", token, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	return &core.DeviceMetadata{Version: info.Version, Model: "CloudVision"}, nil
 }
 
 // GetModels attempts to discover available CloudVision models
@@ -129,6 +190,95 @@ func (c *CloudVisionClient) GetDevices(ctx context.Context, baseURL, token strin
 	return result, nil
 }
 
+// ListDevices retrieves the CloudVision device inventory. The resource API streams one
+// JSON object per device; management IPs come from the legacy inventory service when available.
+func (c *CloudVisionClient) ListDevices(ctx context.Context, baseURL, token string) ([]core.CVDevice, error) {
+	resp, _, err := c.DoREST(ctx, "GET", baseURL+"/api/resources/inventory/v1/Devices", token, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CloudVision returned HTTP %d for device inventory", resp.StatusCode)
+	}
+
+	var devices []core.CVDevice
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var item struct {
+			Result struct {
+				Value struct {
+					Key struct {
+						DeviceID string `json:"deviceId"`
+					} `json:"key"`
+					SoftwareVersion  string `json:"softwareVersion"`
+					ModelName        string `json:"modelName"`
+					Hostname         string `json:"hostname"`
+					FQDN             string `json:"fqdn"`
+					SystemMacAddress string `json:"systemMacAddress"`
+					StreamingStatus  string `json:"streamingStatus"`
+				} `json:"value"`
+			} `json:"result"`
+		}
+		if err := dec.Decode(&item); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode device inventory: %w", err)
+		}
+
+		v := item.Result.Value
+		if v.Key.DeviceID == "" {
+			continue
+		}
+		devices = append(devices, core.CVDevice{
+			Serial:          v.Key.DeviceID,
+			Hostname:        v.Hostname,
+			FQDN:            v.FQDN,
+			Model:           v.ModelName,
+			Version:         v.SoftwareVersion,
+			MAC:             v.SystemMacAddress,
+			StreamingStatus: strings.ToLower(strings.TrimPrefix(v.StreamingStatus, "STREAMING_STATUS_")),
+		})
+	}
+
+	// Management IPs are only exposed by the legacy inventory service
+	if ips, err := c.legacyDeviceIPs(ctx, baseURL, token); err == nil {
+		for i := range devices {
+			devices[i].ManagementIP = ips[devices[i].Serial]
+		}
+	}
+
+	return devices, nil
+}
+
+// legacyDeviceIPs maps device serial numbers to management IPs using /cvpservice/inventory/devices
+func (c *CloudVisionClient) legacyDeviceIPs(ctx context.Context, baseURL, token string) (map[string]string, error) {
+	resp, _, err := c.DoREST(ctx, "GET", baseURL+"/cvpservice/inventory/devices?provisioned=false", token, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CloudVision returned HTTP %d for legacy inventory", resp.StatusCode)
+	}
+
+	var devices []struct {
+		SerialNumber string `json:"serialNumber"`
+		IPAddress    string `json:"ipAddress"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&devices); err != nil {
+		return nil, err
+	}
+
+	ips := make(map[string]string)
+	for _, d := range devices {
+		ips[d.SerialNumber] = d.IPAddress
+	}
+	return ips, nil
+}
+
 // GetEvents retrieves events from CloudVision
 func (c *CloudVisionClient) GetEvents(ctx context.Context, baseURL, token string) (any, error) {
 	url := baseURL + "/api/resources/event/v1/Events"
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TokenWarnDays is how close to expiry a CloudVision token starts producing warnings
+const TokenWarnDays = 14
+
+// ParseToken decodes the claims of a CloudVision JWT without verifying its signature.
+// Opaque tokens that are not JWTs return an error.
+func ParseToken(token string, now time.Time) (*core.TokenInfo, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var claims struct {
+		ID      string `json:"jti"`
+		Session string `json:"sid"`
+		Subject string `json:"sub"`
+		Issuer  string `json:"iss"`
+		// Service account tokens carry the account name in "dsn" rather than "sub"
+		DeviceName string  `json:"dsn"`
+		IssuedAt   float64 `json:"iat"`
+		ExpiresAt  float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+
+	info := &core.TokenInfo{
+		ID:      claims.ID,
+		Subject: claims.Subject,
+		Issuer:  claims.Issuer,
+	}
+	if info.ID == "" {
+		info.ID = claims.Session
+	}
+	if info.Subject == "" {
+		info.Subject = claims.DeviceName
+	}
+	if claims.IssuedAt > 0 {
+		info.IssuedAt = time.Unix(int64(claims.IssuedAt), 0)
+	}
+	if claims.ExpiresAt <= 0 {
+		info.NoExpiry = true
+		return info, nil
+	}
+
+	info.ExpiresAt = time.Unix(int64(claims.ExpiresAt), 0)
+	remaining := info.ExpiresAt.Sub(now)
+	info.DaysRemaining = int(remaining.Hours() / 24)
+	switch {
+	case remaining <= 0:
+		info.Expired = true
+		info.Warning = fmt.Sprintf("token expired on %s", info.ExpiresAt.Format(time.RFC3339))
+	case info.DaysRemaining < TokenWarnDays:
+		info.Warning = fmt.Sprintf("token expires in %s on %s", formatRemaining(remaining), info.ExpiresAt.Format(time.RFC3339))
+	}
+	return info, nil
+}
+
+// formatRemaining renders a duration as days or hours
+func formatRemaining(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%d hours", int(d.Hours()))
+}
+
+// Login authenticates with a username and password and returns the session token
+func (c *CloudVisionClient) Login(ctx context.Context, baseURL, username, password string) (string, error) {
+	if username == "" || password == "" {
+		return "", errors.New("username and password are required")
+	}
+
+	body := map[string]string{"userId": username, "password": password}
+	resp, _, err := c.DoREST(ctx, "POST", baseURL+"/cvpservice/login/authenticate.do", "", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("login rejected for %s (HTTP %d)", username, resp.StatusCode)
+	default:
+		return "", fmt.Errorf("login failed with HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		SessionID string `json:"sessionId"`
+		ErrorCode string `json:"errorCode"`
+		ErrorMsg  string `json:"errorMessage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to decode login response: %w", err)
+	}
+	if result.ErrorCode != "" {
+		return "", fmt.Errorf("login failed: %s", result.ErrorMsg)
+	}
+	if result.SessionID != "" {
+		return result.SessionID, nil
+	}
+
+	// Newer clusters return the token only as a cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "access_token" && cookie.Value != "" {
+			return cookie.Value, nil
+		}
+	}
+	return "", errors.New("login response contained no token")
+}
+
+// CreateServiceAccountToken mints a token for a service account using serviceaccount.v1
+func (c *CloudVisionClient) CreateServiceAccountToken(ctx context.Context, baseURL, bearer, user, description string, validUntil time.Time) (core.CVServiceToken, error) {
+	body := map[string]any{
+		"user":        user,
+		"description": description,
+		"valid_until": validUntil.UTC().Format(time.RFC3339),
+	}
+	resp, _, err := c.DoREST(ctx, "POST", baseURL+"/api/resources/serviceaccount/v1/TokenConfig", bearer, body)
+	if err != nil {
+		return core.CVServiceToken{}, fmt.Errorf("failed to create service account token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return core.CVServiceToken{}, fmt.Errorf("CloudVision returned HTTP %d creating a token for %s%s", resp.StatusCode, user, responseMessage(resp.Body))
+	}
+
+	var result struct {
+		Value struct {
+			Key struct {
+				ID string `json:"id"`
+			} `json:"key"`
+			User        string `json:"user"`
+			Description string `json:"description"`
+			ValidUntil  string `json:"validUntil"`
+			Token       string `json:"token"`
+		} `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return core.CVServiceToken{}, fmt.Errorf("failed to decode service account token: %w", err)
+	}
+	if result.Value.Token == "" {
+		return core.CVServiceToken{}, errors.New("CloudVision returned no token")
+	}
+
+	token := core.CVServiceToken{
+		ID:          result.Value.Key.ID,
+		User:        result.Value.User,
+		Description: result.Value.Description,
+		ValidUntil:  validUntil,
+		Token:       result.Value.Token,
+	}
+	if t, err := time.Parse(time.RFC3339, result.Value.ValidUntil); err == nil {
+		token.ValidUntil = t
+	}
+	return token, nil
+}
+
+// DeleteServiceAccountToken revokes a service account token by its ID
+func (c *CloudVisionClient) DeleteServiceAccountToken(ctx context.Context, baseURL, bearer, id string) error {
+	u := baseURL + "/api/resources/serviceaccount/v1/TokenConfig?key.id=" + url.QueryEscape(id)
+	resp, _, err := c.DoREST(ctx, "DELETE", u, bearer, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete service account token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("CloudVision returned HTTP %d deleting token %s%s", resp.StatusCode, id, responseMessage(resp.Body))
+	}
+	return nil
+}
+
+// ListServiceAccountTokens lists the service account tokens visible to the bearer
+func (c *CloudVisionClient) ListServiceAccountTokens(ctx context.Context, baseURL, bearer string) ([]core.CVServiceToken, error) {
+	resp, _, err := c.DoREST(ctx, "GET", baseURL+"/api/resources/serviceaccount/v1/Token/all", bearer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service account tokens: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CloudVision returned HTTP %d listing tokens%s", resp.StatusCode, responseMessage(resp.Body))
+	}
+
+	var tokens []core.CVServiceToken
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var item struct {
+			Result struct {
+				Value struct {
+					Key struct {
+						ID string `json:"id"`
+					} `json:"key"`
+					User        string `json:"user"`
+					Description string `json:"description"`
+					ValidUntil  string `json:"validUntil"`
+				} `json:"value"`
+			} `json:"result"`
+		}
+		if err := dec.Decode(&item); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode service account tokens: %w", err)
+		}
+
+		v := item.Result.Value
+		token := core.CVServiceToken{ID: v.Key.ID, User: v.User, Description: v.Description}
+		if t, err := time.Parse(time.RFC3339, v.ValidUntil); err == nil {
+			token.ValidUntil = t
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+// responseMessage extracts the error message of a resource API response, prefixed for appending
+func responseMessage(body io.Reader) string {
+	var result struct {
+		Message string `json:"message"`
+	}
+	raw, _ := io.ReadAll(io.LimitReader(body, 4096))
+	if json.Unmarshal(raw, &result) == nil && result.Message != "" {
+		return ": " + result.Message
+	}
+	return ""
+}
@@ -0,0 +1,341 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
+)
+
+// DialFunc opens a connection to addr, e.g. through a proxy or jump host
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// proxyDialTimeout bounds connecting and authenticating to a proxy or jump host
+const proxyDialTimeout = 15 * time.Second
+
+// jumpHosts shares SSH connections to jump hosts between all clients, so endpoints
+// behind the same bastion reuse one SSH session
+var jumpHosts = &sshPool{clients: make(map[string]*ssh.Client)}
+
+// NewDialer returns the dial function for an endpoint's proxy settings, or nil for
+// a direct connection
+func NewDialer(cfg *core.ProxyConfig) (DialFunc, error) {
+	if cfg == nil || cfg.Type == "" {
+		return nil, nil
+	}
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("%s proxy needs an address", cfg.Type)
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("invalid proxy address %q: %w", cfg.Address, err)
+	}
+
+	switch strings.ToLower(cfg.Type) {
+	case core.ProxyHTTP, core.ProxyHTTPS:
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialConnect(ctx, cfg, addr)
+		}, nil
+	case core.ProxySOCKS5:
+		var auth *proxy.Auth
+		if cfg.Username != "" {
+			auth = &proxy.Auth{User: cfg.Username, Password: cfg.Password}
+		}
+		d, err := proxy.SOCKS5("tcp", cfg.Address, auth, &net.Dialer{Timeout: proxyDialTimeout})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure SOCKS5 proxy: %w", err)
+		}
+		return d.(proxy.ContextDialer).DialContext, nil
+	case core.ProxySSH:
+		if _, err := sshConfig(cfg); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return jumpHosts.dial(ctx, cfg, addr)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy type: %s", cfg.Type)
+	}
+}
+
+// describeProxy renders proxy settings for reports, e.g. "ssh://admin@bastion:22"
+func describeProxy(cfg *core.ProxyConfig) string {
+	if cfg == nil || cfg.Type == "" {
+		return ""
+	}
+	if cfg.Username != "" {
+		return fmt.Sprintf("%s://%s@%s", cfg.Type, cfg.Username, cfg.Address)
+	}
+	return fmt.Sprintf("%s://%s", cfg.Type, cfg.Address)
+}
+
+// dialConnect opens a tunnel to addr with an HTTP CONNECT request
+func dialConnect(ctx context.Context, cfg *core.ProxyConfig, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: proxyDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach proxy %s: %w", cfg.Address, err)
+	}
+	if strings.EqualFold(cfg.Type, core.ProxyHTTPS) {
+		host, _, _ := net.SplitHostPort(cfg.Address)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS handshake with proxy %s failed: %w", cfg.Address, err)
+		}
+		conn = tlsConn
+	}
+
+	// Abort the CONNECT exchange when the context ends
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if cfg.Username != "" {
+		creds := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+		req.Header.Set("Proxy-Authorization", "Basic "+creds)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to proxy: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused tunnel to %s: %s", addr, resp.Status)
+	}
+	if !stop() {
+		conn.Close()
+		return nil, ctx.Err()
+	}
+	conn.SetDeadline(time.Time{})
+
+	if br.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, r: br}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn is a connection whose first bytes were already read into a buffer
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Read reads from the buffer before the connection
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// sshPool holds one SSH client per jump host and identity
+type sshPool struct {
+	mu      sync.Mutex
+	clients map[string]*ssh.Client
+}
+
+// dial opens a forwarded connection to addr through the jump host, reconnecting once
+// when the cached SSH session has gone away
+func (p *sshPool) dial(ctx context.Context, cfg *core.ProxyConfig, addr string) (net.Conn, error) {
+	key := jumpHostKey(cfg)
+	for attempt := 0; attempt < 2; attempt++ {
+		client, err := p.client(ctx, key, cfg)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := client.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		// The target is unreachable from a live session; a dead session is replaced
+		if _, _, perr := client.SendRequest("keepalive@openssh.com", true, nil); perr == nil {
+			return nil, fmt.Errorf("jump host %s could not reach %s: %w", cfg.Address, addr, err)
+		}
+		p.drop(key, client)
+	}
+	return nil, fmt.Errorf("jump host %s connection lost", cfg.Address)
+}
+
+// client returns the cached SSH client for key, connecting if needed
+func (p *sshPool) client(ctx context.Context, key string, cfg *core.ProxyConfig) (*ssh.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[key]; ok {
+		return c, nil
+	}
+
+	config, err := sshConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: proxyDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach jump host %s: %w", cfg.Address, err)
+	}
+	conn.SetDeadline(time.Now().Add(proxyDialTimeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, cfg.Address, config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH login to jump host %s failed: %w", cfg.Address, err)
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := ssh.NewClient(sshConn, chans, reqs)
+	p.clients[key] = c
+
+	// Forget the session once the jump host closes it
+	go func() {
+		c.Wait()
+		p.drop(key, c)
+	}()
+	return c, nil
+}
+
+// drop closes and forgets a cached client if it is still the current one for key
+func (p *sshPool) drop(key string, c *ssh.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients[key] == c {
+		delete(p.clients, key)
+	}
+	c.Close()
+}
+
+// jumpHostKey identifies a jump host connection by address and credentials
+func jumpHostKey(cfg *core.ProxyConfig) string {
+	raw, _ := json.Marshal(cfg)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// sshConfig builds the SSH client configuration for a jump host
+func sshConfig(cfg *core.ProxyConfig) (*ssh.ClientConfig, error) {
+	if cfg.Username == "" {
+		return nil, errors.New("SSH jump host needs a username")
+	}
+
+	var auths []ssh.AuthMethod
+	if cfg.SSHKeyPEM != "" {
+		var signer ssh.Signer
+		var err error
+		if cfg.SSHKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(cfg.SSHKeyPEM), []byte(cfg.SSHKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey([]byte(cfg.SSHKeyPEM))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse SSH key: %w", err)
+		}
+		auths = append(auths, ssh.PublicKeys(signer))
+	}
+	if cfg.UseAgent {
+		auths = append(auths, ssh.PublicKeysCallback(agentSigners))
+	}
+	if cfg.Password != "" {
+		auths = append(auths, ssh.Password(cfg.Password))
+	}
+	if len(auths) == 0 {
+		return nil, errors.New("SSH jump host needs a key, the agent or a password")
+	}
+
+	hostKey, err := hostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{
+		User:            cfg.Username,
+		Auth:            auths,
+		HostKeyCallback: hostKey,
+		Timeout:         proxyDialTimeout,
+	}, nil
+}
+
+// agentSigners returns the keys held by the ssh-agent at SSH_AUTH_SOCK
+func agentSigners() ([]ssh.Signer, error) {
+	sock := os.Getenv("SSH_AUTH_SOCK")
+	if sock == "" {
+		return nil, errors.New("SSH_AUTH_SOCK is not set; no ssh-agent available")
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+	// The connection stays open for the signing requests made during authentication
+	return agent.NewClient(conn).Signers()
+}
+
+// hostKeyCallback verifies the jump host key against the pinned fingerprint, or
+// against ~/.ssh/known_hosts when none is pinned
+func hostKeyCallback(cfg *core.ProxyConfig) (ssh.HostKeyCallback, error) {
+	if cfg.InsecureHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if cfg.HostKeySHA256 != "" {
+		want := pinnedFingerprint(cfg.HostKeySHA256)
+		return func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if got := ssh.FingerprintSHA256(key); got != want {
+				return fmt.Errorf("jump host key %s does not match pinned %s", got, want)
+			}
+			return nil
+		}, nil
+	}
+
+	callback, err := knownHostsCallback()
+	if err != nil {
+		return nil, fmt.Errorf("no pinned jump host key and %w", err)
+	}
+	return callback, nil
+}
+
+// pinnedFingerprint normalizes a pinned key fingerprint to the form of ssh.FingerprintSHA256
+func pinnedFingerprint(pin string) string {
+	return "SHA256:" + strings.TrimRight(strings.TrimPrefix(pin, "SHA256:"), "=")
+}
+
+// knownHostsCallback verifies host keys against ~/.ssh/known_hosts
+func knownHostsCallback() (ssh.HostKeyCallback, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("no home directory: %w", err)
+	}
+	callback, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("known_hosts is unavailable: %w", err)
+	}
+	return callback, nil
+}
@@ -1,36 +1,63 @@
 package client
 
 import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/eos"
 	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"errors"
-	"io"
+	"fmt"
 	"net/http"
 	"time"
 )
 
 // EAPIClient handles communication with Arista EOS eAPI
 type EAPIClient struct {
-	http *http.Client
+	http       *http.Client
+	transports *TransportCache
 }
 
 // NewEAPIClient creates a new EAPI client
 func NewEAPIClient(tlsVerify bool, timeout time.Duration) *EAPIClient {
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: !tlsVerify},
+	tr := newPooledTransport(&tls.Config{InsecureSkipVerify: !tlsVerify}, DefaultPolicy.MaxConcurrent)
+	transports := NewTransportCache(timeout)
+	return &EAPIClient{
+		http:       &http.Client{Transport: transports.wrap(tr), Timeout: timeout},
+		transports: transports,
 	}
-	return &EAPIClient{http: &http.Client{Transport: tr, Timeout: timeout}}
+}
+
+// ForEndpoint returns a client that uses the endpoint's TLS settings
+func (c *EAPIClient) ForEndpoint(endpoint core.Endpoint) (*EAPIClient, error) {
+	httpClient, err := c.transports.Client(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &EAPIClient{http: httpClient, transports: c.transports}, nil
+}
+
+// ForgetEndpoint drops the cached transport of an endpoint
+func (c *EAPIClient) ForgetEndpoint(endpointID string) {
+	c.transports.Forget(endpointID)
+}
+
+// Transports returns the per-endpoint transport layer, for stats and circuit control
+func (c *EAPIClient) Transports() *TransportCache {
+	return c.transports
 }
 
 // RunCmdsParams represents the parameters for runCmds
 type RunCmdsParams struct {
-	Version       int      `json:"version"`
-	Cmds          []string `json:"cmds"`
-	Format        string   `json:"format,omitempty"`        // json|text
-	AutoComplete  bool     `json:"autoComplete,omitempty"`
-	ExpandAliases bool     `json:"expandAliases,omitempty"`
+	Version            Version   `json:"version"` // 1, a fixed version, or VersionLatest
+	Cmds               []Command `json:"cmds"`
+	Format             string    `json:"format,omitempty"`     // json|text
+	Timestamps         bool      `json:"timestamps,omitempty"` // add execution times to each output
+	AutoComplete       bool      `json:"autoComplete,omitempty"`
+	ExpandAliases      bool      `json:"expandAliases,omitempty"`
+	IncludeErrorDetail bool      `json:"includeErrorDetail,omitempty"`
+	Streaming          bool      `json:"streaming,omitempty"` // let EOS stream large outputs instead of buffering them
 }
 
 // JSONRPCRequest represents a JSON-RPC request
@@ -43,10 +70,10 @@ type JSONRPCRequest struct {
 
 // JSONRPCResponse represents a JSON-RPC response
 type JSONRPCResponse struct {
-	JSONRPC string           `json:"jsonrpc"`
-	Result  []any            `json:"result,omitempty"`
-	Error   *JSONRPCError    `json:"error,omitempty"`
-	ID      string           `json:"id"`
+	JSONRPC string        `json:"jsonrpc"`
+	Result  []any         `json:"result,omitempty"`
+	Error   *JSONRPCError `json:"error,omitempty"`
+	ID      string        `json:"id"`
 }
 
 // JSONRPCError represents a JSON-RPC error
@@ -56,17 +83,28 @@ type JSONRPCError struct {
 	Data    any    `json:"data,omitempty"`
 }
 
-// RunCmds executes commands on an EOS device via eAPI
+// RunCmds executes commands on an EOS device via eAPI. baseURL is an HTTP(S) URL, including
+// the on-box "protocol http localhost" listener (http://localhost:8080), or unix:///path for
+// the eAPI Unix socket; local transports need no credentials. The request is only retried after
+// reaching the device when ctx is marked with WithIdempotent, as commands may change config.
 func (c *EAPIClient) RunCmds(ctx context.Context, baseURL, user, pass string, params RunCmdsParams) (*JSONRPCResponse, *http.Response, time.Duration, error) {
 	rpc := JSONRPCRequest{
 		JSONRPC: "2.0",
 		Method:  "runCmds",
 		Params:  params,
-		ID:      "1",
+		ID:      nextRequestID(),
+	}
+	raw, err := json.Marshal(rpc)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to encode runCmds request: %w", err)
 	}
-	raw, _ := json.Marshal(rpc)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/command-api", bytes.NewReader(raw))
+	target, sock := commandTarget(baseURL)
+	if sock != "" {
+		ctx = context.WithValue(ctx, socketKey{}, sock)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(raw))
 	if err != nil {
 		return nil, nil, 0, err
 	}
@@ -77,42 +115,83 @@ func (c *EAPIClient) RunCmds(ctx context.Context, baseURL, user, pass string, pa
 
 	start := time.Now()
 	resp, err := c.http.Do(req)
-	elapsed := time.Since(start)
 	if err != nil {
-		return nil, resp, elapsed, err
+		return nil, resp, time.Since(start), err
 	}
 	defer resp.Body.Close()
 
-	b, _ := io.ReadAll(resp.Body)
+	// Decode while reading, so streamed outputs are not buffered twice
 	var out JSONRPCResponse
-	if err := json.Unmarshal(b, &out); err != nil {
+	err = json.NewDecoder(resp.Body).Decode(&out)
+	elapsed := time.Since(start)
+	if err != nil {
+		if resp.StatusCode != http.StatusOK {
+			// Authentication failures and proxies answer with HTML, not JSON-RPC
+			return nil, resp, elapsed, fmt.Errorf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		}
+		return nil, resp, elapsed, fmt.Errorf("failed to decode eAPI response: %w", err)
+	}
+	if err := checkResponseID(rpc, &out); err != nil {
 		return nil, resp, elapsed, err
 	}
 	if out.Error != nil {
-		return &out, resp, elapsed, errors.New(out.Error.Message)
+		return &out, resp, elapsed, out.Error
 	}
 	return &out, resp, elapsed, nil
 }
 
 // TestConnection tests the connection to an EOS device
 func (c *EAPIClient) TestConnection(ctx context.Context, baseURL, user, pass string) (bool, string, time.Duration, error) {
+	_, status, elapsed, err := c.ShowVersion(ctx, baseURL, user, pass)
+	if err != nil {
+		return false, err.Error(), elapsed, err
+	}
+
+	if status == 200 {
+		return true, "Connection successful", elapsed, nil
+	}
+
+	return false, "Connection failed", elapsed, errors.New("non-200 status code")
+}
+
+// ShowVersion runs "show version" and returns the device metadata and HTTP status code
+func (c *EAPIClient) ShowVersion(ctx context.Context, baseURL, user, pass string) (*core.DeviceMetadata, int, time.Duration, error) {
 	params := RunCmdsParams{
 		Version:      1,
-		Cmds:         []string{"show version"},
+		Cmds:         Commands("show version"),
 		Format:       "json",
 		AutoComplete: true,
 	}
 
-	_, resp, elapsed, err := c.RunCmds(ctx, baseURL, user, pass, params)
+	rpc, resp, elapsed, err := c.RunCmds(WithIdempotent(ctx), baseURL, user, pass, params)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
 	if err != nil {
-		return false, err.Error(), elapsed, err
+		return nil, status, elapsed, err
 	}
 
-	if resp.StatusCode == 200 {
-		return true, "Connection successful", elapsed, nil
+	if len(rpc.Result) == 0 {
+		return &core.DeviceMetadata{}, status, elapsed, nil
 	}
+	meta, err := versionMetadata(rpc.Result[0])
+	return meta, status, elapsed, err
+}
 
-	return false, "Connection failed", elapsed, errors.New("non-200 status code")
+// versionMetadata converts "show version" output to device metadata
+func versionMetadata(output any) (*core.DeviceMetadata, error) {
+	v, err := eos.As[eos.ShowVersion](output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode show version: %w", err)
+	}
+	return &core.DeviceMetadata{
+		Version:  v.Version,
+		Model:    v.ModelName,
+		Serial:   v.SerialNumber,
+		MAC:      v.SystemMacAddress,
+		Hardware: v.HardwareRevision,
+	}, nil
 }
 
 // EnumerateCommands attempts to discover available CLI commands
@@ -131,7 +210,7 @@ func (c *EAPIClient) EnumerateCommands(ctx context.Context, baseURL, user, pass
 	for _, cmd := range discoveryCommands {
 		params := RunCmdsParams{
 			Version:      1,
-			Cmds:         []string{cmd},
+			Cmds:         Commands(cmd),
 			Format:       "text", // Use text for help output
 			AutoComplete: true,
 		}
@@ -0,0 +1,671 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// GNMIClient handles communication with the EOS gNMI (OpenConfig) server
+type GNMIClient struct {
+	tlsConfig *tls.Config
+	timeout   time.Duration
+	dialer    DialFunc
+
+	mu        sync.Mutex
+	endpoints map[string]cachedGNMI       // per-endpoint clients built by ForEndpoint
+	conns     map[string]*grpc.ClientConn // open connections by target; nil dials per call
+}
+
+// cachedGNMI is an endpoint client together with the settings hash it was built from
+type cachedGNMI struct {
+	hash   string
+	client *GNMIClient
+}
+
+// NewGNMIClient creates a new gNMI client
+func NewGNMIClient(tlsVerify bool, timeout time.Duration) *GNMIClient {
+	return &GNMIClient{
+		tlsConfig: &tls.Config{InsecureSkipVerify: !tlsVerify},
+		timeout:   timeout,
+		endpoints: make(map[string]cachedGNMI),
+	}
+}
+
+// ForEndpoint returns a client that uses the endpoint's TLS and proxy settings. The
+// client and its connections are reused until those settings change.
+func (c *GNMIClient) ForEndpoint(endpoint core.Endpoint) (*GNMIClient, error) {
+	hash, err := settingsHash(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.endpoints[endpoint.ID]; ok && cached.hash == hash {
+		return cached.client, nil
+	}
+
+	tlsConfig, err := BuildTLSConfig(endpoint.TLSVerify, endpoint.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings for %s: %w", endpoint.Name, err)
+	}
+	dial, err := NewDialer(endpoint.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings for %s: %w", endpoint.Name, err)
+	}
+
+	// Replace a stale client and close its connections
+	if cached, ok := c.endpoints[endpoint.ID]; ok {
+		cached.client.closeConns()
+	}
+
+	endpointClient := &GNMIClient{
+		tlsConfig: tlsConfig,
+		timeout:   c.timeout,
+		dialer:    dial,
+		conns:     make(map[string]*grpc.ClientConn),
+	}
+	if c.endpoints == nil {
+		c.endpoints = make(map[string]cachedGNMI)
+	}
+	c.endpoints[endpoint.ID] = cachedGNMI{hash: hash, client: endpointClient}
+	return endpointClient, nil
+}
+
+// ForgetEndpoint drops the cached client for an endpoint and closes its connections
+func (c *GNMIClient) ForgetEndpoint(endpointID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.endpoints[endpointID]; ok {
+		cached.client.closeConns()
+		delete(c.endpoints, endpointID)
+	}
+}
+
+// closeConns closes the cached connections of an endpoint client
+func (c *GNMIClient) closeConns() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for target, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, target)
+	}
+}
+
+// conn returns a connection to the target and a function to call when done with it.
+// Endpoint clients keep their connections open for reuse; others dial per call.
+func (c *GNMIClient) conn(target string) (*grpc.ClientConn, func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conns == nil {
+		conn, err := c.dial(target)
+		if err != nil {
+			return nil, nil, err
+		}
+		return conn, func() { conn.Close() }, nil
+	}
+
+	if conn, ok := c.conns[target]; ok {
+		return conn, func() {}, nil
+	}
+	conn, err := c.dial(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.conns[target] = conn
+	return conn, func() {}, nil
+}
+
+// GNMIUpdate represents a single path/value update returned by Get or Subscribe
+type GNMIUpdate struct {
+	Path      string    `json:"path"`
+	Value     any       `json:"value,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Delete    bool      `json:"delete,omitempty"`
+}
+
+// GNMISubscribeParams represents the parameters for a Subscribe call
+type GNMISubscribeParams struct {
+	Paths          []string      `json:"paths"`
+	Mode           string        `json:"mode"`           // once|poll|stream
+	StreamMode     string        `json:"streamMode"`     // sample|on_change|target_defined
+	SampleInterval time.Duration `json:"sampleInterval"` // only used for sample subscriptions
+	Encoding       string        `json:"encoding,omitempty"`
+	Polls          int           `json:"polls,omitempty"`      // number of polls for poll mode
+	MaxUpdates     int           `json:"maxUpdates,omitempty"` // stop after this many updates (0 = unbounded)
+}
+
+// Default gNMI port on EOS (management api gnmi / transport grpc default)
+const defaultGNMIPort = "6030"
+
+// dial opens a gRPC connection to the gNMI target
+func (c *GNMIClient) dial(target string) (*grpc.ClientConn, error) {
+	addr, useTLS, err := parseGNMITarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		creds = credentials.NewTLS(c.tlsConfig.Clone())
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if c.dialer != nil {
+		// Pass the name through unresolved; it is resolved beyond the proxy or jump host
+		addr = "passthrough:///" + addr
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return c.dialer(ctx, "tcp", addr)
+		}))
+	}
+
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial gNMI target: %w", err)
+	}
+	return conn, nil
+}
+
+// parseGNMITarget converts an endpoint URL into a host:port and TLS flag.
+// Accepted forms: host, host:port, grpc://host:port (plaintext), grpcs://host:port or https://host:port (TLS).
+func parseGNMITarget(target string) (string, bool, error) {
+	if target == "" {
+		return "", false, errors.New("gNMI target is empty")
+	}
+
+	useTLS := false
+	host := target
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", false, fmt.Errorf("invalid gNMI target: %w", err)
+		}
+		switch u.Scheme {
+		case "grpcs", "https":
+			useTLS = true
+		case "grpc", "http":
+		default:
+			return "", false, fmt.Errorf("unsupported gNMI scheme: %s", u.Scheme)
+		}
+		host = u.Host
+	}
+
+	if !strings.Contains(host, ":") || strings.HasSuffix(host, "]") {
+		host = host + ":" + defaultGNMIPort
+	}
+	return host, useTLS, nil
+}
+
+// withCredentials attaches the gNMI username/password metadata to the context
+func withCredentials(ctx context.Context, user, pass string) context.Context {
+	if user == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "username", user, "password", pass)
+}
+
+// Capabilities retrieves the models and encodings supported by the target
+func (c *GNMIClient) Capabilities(ctx context.Context, target, user, pass string) (*gpb.CapabilityResponse, time.Duration, error) {
+	conn, release, err := c.conn(target)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer release()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := gpb.NewGNMIClient(conn).Capabilities(withCredentials(ctx, user, pass), &gpb.CapabilityRequest{})
+	return resp, time.Since(start), err
+}
+
+// Get retrieves a snapshot of the given paths
+func (c *GNMIClient) Get(ctx context.Context, target, user, pass string, paths []string, encoding string) ([]GNMIUpdate, time.Duration, error) {
+	req := &gpb.GetRequest{Type: gpb.GetRequest_ALL}
+	for _, p := range paths {
+		gp, err := ParseGNMIPath(p)
+		if err != nil {
+			return nil, 0, err
+		}
+		req.Path = append(req.Path, gp)
+	}
+	enc, err := parseEncoding(encoding)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Encoding = enc
+
+	conn, release, err := c.conn(target)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer release()
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := gpb.NewGNMIClient(conn).Get(withCredentials(ctx, user, pass), req)
+	elapsed := time.Since(start)
+	if err != nil {
+		return nil, elapsed, err
+	}
+
+	var updates []GNMIUpdate
+	for _, n := range resp.GetNotification() {
+		updates = append(updates, notificationUpdates(n)...)
+	}
+	return updates, elapsed, nil
+}
+
+// Subscribe runs a ONCE, POLL or STREAM subscription and hands every update to handle.
+// ONCE and POLL subscriptions return after the final sync response; STREAM subscriptions
+// run until the context is cancelled or MaxUpdates is reached.
+func (c *GNMIClient) Subscribe(ctx context.Context, target, user, pass string, params GNMISubscribeParams, handle func(GNMIUpdate) error) (time.Duration, error) {
+	req, err := buildSubscribeRequest(params)
+	if err != nil {
+		return 0, err
+	}
+
+	conn, release, err := c.conn(target)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := time.Now()
+	stream, err := gpb.NewGNMIClient(conn).Subscribe(withCredentials(ctx, user, pass))
+	if err != nil {
+		return time.Since(start), err
+	}
+	if err := stream.Send(req); err != nil {
+		return time.Since(start), fmt.Errorf("failed to send subscription: %w", err)
+	}
+
+	mode := req.GetSubscribe().GetMode()
+	polls := params.Polls
+	if polls < 1 {
+		polls = 1
+	}
+
+	count := 0
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return time.Since(start), nil
+		}
+		if err != nil {
+			if ctx.Err() != nil && mode == gpb.SubscriptionList_STREAM {
+				// Stream subscriptions end when the caller's context is done
+				return time.Since(start), nil
+			}
+			return time.Since(start), err
+		}
+
+		switch r := resp.Response.(type) {
+		case *gpb.SubscribeResponse_Update:
+			for _, u := range notificationUpdates(r.Update) {
+				if err := handle(u); err != nil {
+					return time.Since(start), err
+				}
+				count++
+				if params.MaxUpdates > 0 && count >= params.MaxUpdates {
+					return time.Since(start), nil
+				}
+			}
+		case *gpb.SubscribeResponse_SyncResponse:
+			switch mode {
+			case gpb.SubscriptionList_ONCE:
+				return time.Since(start), nil
+			case gpb.SubscriptionList_POLL:
+				polls--
+				if polls <= 0 {
+					return time.Since(start), nil
+				}
+				poll := &gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Poll{Poll: &gpb.Poll{}}}
+				if err := stream.Send(poll); err != nil {
+					return time.Since(start), fmt.Errorf("failed to send poll: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// TestConnection tests the connection to a gNMI target using a Capabilities request
+func (c *GNMIClient) TestConnection(ctx context.Context, target, user, pass string) (bool, string, time.Duration, error) {
+	resp, elapsed, err := c.Capabilities(ctx, target, user, pass)
+	if err != nil {
+		return false, err.Error(), elapsed, err
+	}
+
+	return true, fmt.Sprintf("Connection successful (gNMI %s, %d models)", resp.GetGNMIVersion(), len(resp.GetSupportedModels())), elapsed, nil
+}
+
+// withTimeout applies the client timeout to unary calls
+func (c *GNMIClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// buildSubscribeRequest converts subscribe params into a gNMI SubscribeRequest
+func buildSubscribeRequest(params GNMISubscribeParams) (*gpb.SubscribeRequest, error) {
+	if len(params.Paths) == 0 {
+		return nil, errors.New("at least one path is required")
+	}
+
+	list := &gpb.SubscriptionList{}
+	switch strings.ToLower(params.Mode) {
+	case "", "once":
+		list.Mode = gpb.SubscriptionList_ONCE
+	case "poll":
+		list.Mode = gpb.SubscriptionList_POLL
+	case "stream":
+		list.Mode = gpb.SubscriptionList_STREAM
+	default:
+		return nil, fmt.Errorf("unsupported subscription mode: %s", params.Mode)
+	}
+
+	enc, err := parseEncoding(params.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	list.Encoding = enc
+
+	var subMode gpb.SubscriptionMode
+	switch strings.ToLower(strings.ReplaceAll(params.StreamMode, "-", "_")) {
+	case "", "target_defined":
+		subMode = gpb.SubscriptionMode_TARGET_DEFINED
+	case "sample":
+		subMode = gpb.SubscriptionMode_SAMPLE
+	case "on_change":
+		subMode = gpb.SubscriptionMode_ON_CHANGE
+	default:
+		return nil, fmt.Errorf("unsupported stream mode: %s", params.StreamMode)
+	}
+
+	for _, p := range params.Paths {
+		gp, err := ParseGNMIPath(p)
+		if err != nil {
+			return nil, err
+		}
+		sub := &gpb.Subscription{Path: gp}
+		if list.Mode == gpb.SubscriptionList_STREAM {
+			sub.Mode = subMode
+			if subMode == gpb.SubscriptionMode_SAMPLE {
+				interval := params.SampleInterval
+				if interval <= 0 {
+					interval = 10 * time.Second
+				}
+				sub.SampleInterval = uint64(interval.Nanoseconds())
+			}
+		}
+		list.Subscription = append(list.Subscription, sub)
+	}
+
+	return &gpb.SubscribeRequest{Request: &gpb.SubscribeRequest_Subscribe{Subscribe: list}}, nil
+}
+
+// parseEncoding maps an encoding name to the gNMI enum (defaults to JSON)
+func parseEncoding(encoding string) (gpb.Encoding, error) {
+	if encoding == "" {
+		return gpb.Encoding_JSON, nil
+	}
+	name := strings.ToUpper(strings.ReplaceAll(encoding, "-", "_"))
+	v, ok := gpb.Encoding_value[name]
+	if !ok {
+		return 0, fmt.Errorf("unsupported encoding: %s", encoding)
+	}
+	return gpb.Encoding(v), nil
+}
+
+// ParseGNMIPath parses a path string into a gNMI Path. An optional origin prefix
+// selects the schema, e.g. "openconfig:/interfaces/interface[name=Ethernet1]/state"
+// or "eos_native:/Sysdb/environment/archer/power/status". Paths without a prefix
+// use the target's default (OpenConfig) origin.
+func ParseGNMIPath(p string) (*gpb.Path, error) {
+	path := &gpb.Path{}
+	p = strings.TrimSpace(p)
+	if i := strings.Index(p, ":/"); i > 0 && !strings.Contains(p[:i], "/") {
+		path.Origin = p[:i]
+		p = p[i+1:]
+	}
+
+	elems, err := splitPath(p)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range elems {
+		pe, err := parsePathElem(e)
+		if err != nil {
+			return nil, err
+		}
+		path.Elem = append(path.Elem, pe)
+	}
+	return path, nil
+}
+
+// splitPath splits a path on '/' while keeping bracketed keys and escaped
+// characters intact
+func splitPath(p string) ([]string, error) {
+	var elems []string
+	var cur strings.Builder
+	depth := 0
+	escaped := false
+	for _, r := range p {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced brackets in path %q", p)
+			}
+		case r == '/' && depth == 0:
+			if cur.Len() > 0 {
+				elems = append(elems, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing escape in path %q", p)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced brackets in path %q", p)
+	}
+	if cur.Len() > 0 {
+		elems = append(elems, cur.String())
+	}
+	return elems, nil
+}
+
+// parsePathElem parses "name[key=value][key2=value2]" into a PathElem. A backslash
+// escapes the next character, e.g. "[name=a\]b]" or "[description=x\\y]".
+func parsePathElem(e string) (*gpb.PathElem, error) {
+	name, rest, _ := scanEscaped(e, '[')
+	pe := &gpb.PathElem{Name: name}
+	for rest != "" {
+		if rest[0] != '[' {
+			return nil, fmt.Errorf("invalid path element %q", e)
+		}
+		key, value, ok := scanEscaped(rest[1:], '=')
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid key in path element %q", e)
+		}
+		val, next, ok := scanEscaped(value[1:], ']')
+		if !ok {
+			return nil, fmt.Errorf("invalid key in path element %q", e)
+		}
+		if pe.Key == nil {
+			pe.Key = map[string]string{}
+		}
+		pe.Key[key] = val
+		rest = next[1:]
+	}
+	return pe, nil
+}
+
+// scanEscaped reads s up to the first unescaped stop character, returning the
+// unescaped text, the remainder starting at stop, and whether stop was found
+func scanEscaped(s string, stop byte) (string, string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+		case s[i] == stop:
+			return b.String(), s[i:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), "", false
+}
+
+// escapePathName escapes the characters that end a path element name
+var escapePathName = strings.NewReplacer(`\`, `\\`, `/`, `\/`, `[`, `\[`)
+
+// escapeKeyValue escapes the characters that end a path key or value
+var escapeKeyValue = strings.NewReplacer(`\`, `\\`, `]`, `\]`, `=`, `\=`)
+
+// GNMIPathString renders a gNMI path back into its string form
+func GNMIPathString(prefix, p *gpb.Path) string {
+	var b strings.Builder
+	for _, pp := range []*gpb.Path{prefix, p} {
+		for _, e := range pp.GetElem() {
+			b.WriteString("/")
+			b.WriteString(escapePathName.Replace(e.GetName()))
+			for _, k := range sortedKeys(e.GetKey()) {
+				fmt.Fprintf(&b, "[%s=%s]", escapeKeyValue.Replace(k), escapeKeyValue.Replace(e.GetKey()[k]))
+			}
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString("/")
+	}
+
+	origin := p.GetOrigin()
+	if origin == "" {
+		origin = prefix.GetOrigin()
+	}
+	if origin != "" {
+		return origin + ":" + b.String()
+	}
+	return b.String()
+}
+
+// sortedKeys returns map keys in a stable order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// notificationUpdates flattens a notification into path/value updates
+func notificationUpdates(n *gpb.Notification) []GNMIUpdate {
+	ts := time.Unix(0, n.GetTimestamp())
+	var updates []GNMIUpdate
+	for _, u := range n.GetUpdate() {
+		updates = append(updates, GNMIUpdate{
+			Path:      GNMIPathString(n.GetPrefix(), u.GetPath()),
+			Value:     decodeTypedValue(u.GetVal()),
+			Timestamp: ts,
+		})
+	}
+	for _, d := range n.GetDelete() {
+		updates = append(updates, GNMIUpdate{
+			Path:      GNMIPathString(n.GetPrefix(), d),
+			Timestamp: ts,
+			Delete:    true,
+		})
+	}
+	return updates
+}
+
+// decodeTypedValue converts a gNMI TypedValue into a plain Go value
+func decodeTypedValue(v *gpb.TypedValue) any {
+	if v == nil {
+		return nil
+	}
+	switch val := v.Value.(type) {
+	case *gpb.TypedValue_StringVal:
+		return val.StringVal
+	case *gpb.TypedValue_IntVal:
+		return val.IntVal
+	case *gpb.TypedValue_UintVal:
+		return val.UintVal
+	case *gpb.TypedValue_BoolVal:
+		return val.BoolVal
+	case *gpb.TypedValue_BytesVal:
+		return val.BytesVal
+	case *gpb.TypedValue_FloatVal:
+		return float64(val.FloatVal)
+	case *gpb.TypedValue_DoubleVal:
+		return val.DoubleVal
+	case *gpb.TypedValue_DecimalVal:
+		return float64(val.DecimalVal.GetDigits()) / math.Pow10(int(val.DecimalVal.GetPrecision()))
+	case *gpb.TypedValue_LeaflistVal:
+		var out []any
+		for _, e := range val.LeaflistVal.GetElement() {
+			out = append(out, decodeTypedValue(e))
+		}
+		return out
+	case *gpb.TypedValue_JsonVal:
+		return decodeJSONValue(val.JsonVal)
+	case *gpb.TypedValue_JsonIetfVal:
+		return decodeJSONValue(val.JsonIetfVal)
+	case *gpb.TypedValue_AsciiVal:
+		return val.AsciiVal
+	case *gpb.TypedValue_ProtoBytes:
+		return val.ProtoBytes
+	case *gpb.TypedValue_AnyVal:
+		return val.AnyVal.String()
+	default:
+		return nil
+	}
+}
+
+// decodeJSONValue unmarshals a JSON-encoded value, falling back to the raw string
+func decodeJSONValue(b []byte) any {
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return string(b)
+	}
+	return out
+}
@@ -0,0 +1,577 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeGNMIServer is an in-process gNMI target serving a fixed set of leaves
+type fakeGNMIServer struct {
+	gpb.UnimplementedGNMIServer
+
+	mu       sync.Mutex
+	requests []*gpb.SubscriptionList
+	polls    int
+	users    []string
+}
+
+// fakeLeaves are the values the fake target reports for every requested path
+var fakeLeaves = map[string]*gpb.TypedValue{
+	"in-octets":   {Value: &gpb.TypedValue_UintVal{UintVal: 1024}},
+	"oper-status": {Value: &gpb.TypedValue_StringVal{StringVal: "UP"}},
+}
+
+func (s *fakeGNMIServer) recordUser(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	user, pass := md.Get("username"), md.Get("password")
+	if len(user) != 1 || len(pass) != 1 || pass[0] != "secret" {
+		return status.Error(codes.Unauthenticated, "bad credentials")
+	}
+	s.mu.Lock()
+	s.users = append(s.users, user[0])
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *fakeGNMIServer) Capabilities(ctx context.Context, _ *gpb.CapabilityRequest) (*gpb.CapabilityResponse, error) {
+	if err := s.recordUser(ctx); err != nil {
+		return nil, err
+	}
+	return &gpb.CapabilityResponse{
+		GNMIVersion: "0.7.0",
+		SupportedModels: []*gpb.ModelData{
+			{Name: "openconfig-interfaces", Organization: "OpenConfig working group", Version: "2.4.3"},
+			{Name: "arista-exp-eos", Organization: "Arista Networks", Version: "1.0.0"},
+		},
+		SupportedEncodings: []gpb.Encoding{gpb.Encoding_JSON, gpb.Encoding_JSON_IETF},
+	}, nil
+}
+
+func (s *fakeGNMIServer) Get(ctx context.Context, req *gpb.GetRequest) (*gpb.GetResponse, error) {
+	if err := s.recordUser(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetEncoding() != gpb.Encoding_JSON_IETF {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported encoding %v", req.GetEncoding())
+	}
+	resp := &gpb.GetResponse{}
+	for _, p := range req.GetPath() {
+		resp.Notification = append(resp.Notification, &gpb.Notification{
+			Timestamp: 1700000000000000000,
+			Update: []*gpb.Update{{
+				Path: p,
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`{"openconfig-interfaces:mtu":9214}`)}},
+			}},
+		})
+	}
+	return resp, nil
+}
+
+func (s *fakeGNMIServer) Subscribe(stream gpb.GNMI_SubscribeServer) error {
+	if err := s.recordUser(stream.Context()); err != nil {
+		return err
+	}
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	list := req.GetSubscribe()
+	if list == nil {
+		return status.Error(codes.InvalidArgument, "first request must be a subscription")
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, list)
+	s.mu.Unlock()
+
+	if err := s.sendAll(stream, list); err != nil {
+		return err
+	}
+
+	switch list.GetMode() {
+	case gpb.SubscriptionList_POLL:
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if req.GetPoll() == nil {
+				return status.Error(codes.InvalidArgument, "expected a poll request")
+			}
+			s.mu.Lock()
+			s.polls++
+			s.mu.Unlock()
+			if err := s.sendAll(stream, list); err != nil {
+				return err
+			}
+		}
+	case gpb.SubscriptionList_STREAM:
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stream.Context().Done():
+				return nil
+			case <-ticker.C:
+				if err := s.sendUpdates(stream, list); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		// ONCE: keep the stream open so the client has to stop on the sync response
+		<-stream.Context().Done()
+		return nil
+	}
+}
+
+// sendAll sends one update per subscribed leaf followed by a sync response
+func (s *fakeGNMIServer) sendAll(stream gpb.GNMI_SubscribeServer, list *gpb.SubscriptionList) error {
+	if err := s.sendUpdates(stream, list); err != nil {
+		return err
+	}
+	return stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
+
+func (s *fakeGNMIServer) sendUpdates(stream gpb.GNMI_SubscribeServer, list *gpb.SubscriptionList) error {
+	for _, sub := range list.GetSubscription() {
+		elems := sub.GetPath().GetElem()
+		val, ok := fakeLeaves[elems[len(elems)-1].GetName()]
+		if !ok {
+			continue
+		}
+		n := &gpb.Notification{
+			Timestamp: time.Now().UnixNano(),
+			Prefix:    &gpb.Path{Origin: "openconfig"},
+			Update:    []*gpb.Update{{Path: sub.GetPath(), Val: val}},
+		}
+		if err := stream.Send(&gpb.SubscribeResponse{Response: &gpb.SubscribeResponse_Update{Update: n}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFakeGNMI starts the fake target on a loopback listener and returns a client and
+// the target URL to reach it
+func newFakeGNMI(t *testing.T) (*GNMIClient, *fakeGNMIServer, string) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeGNMIServer{}
+	srv := grpc.NewServer()
+	gpb.RegisterGNMIServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	return NewGNMIClient(false, 5*time.Second), fake, "grpc://" + lis.Addr().String()
+}
+
+const (
+	inOctets    = "/interfaces/interface[name=Ethernet1]/state/counters/in-octets"
+	operStatus  = "/interfaces/interface[name=Ethernet1]/state/oper-status"
+	counterPath = "openconfig:/interfaces/interface[name=Ethernet1]/state/counters/in-octets"
+	statusPath  = "openconfig:/interfaces/interface[name=Ethernet1]/state/oper-status"
+)
+
+func TestGNMICapabilities(t *testing.T) {
+	c, fake, target := newFakeGNMI(t)
+
+	resp, _, err := c.Capabilities(context.Background(), target, "admin", "secret")
+	if err != nil {
+		t.Fatalf("Capabilities: %v", err)
+	}
+	if resp.GetGNMIVersion() != "0.7.0" || len(resp.GetSupportedModels()) != 2 {
+		t.Errorf("unexpected capabilities: %v", resp)
+	}
+	if !reflect.DeepEqual(fake.users, []string{"admin"}) {
+		t.Errorf("server saw users %v, want [admin]", fake.users)
+	}
+
+	ok, msg, _, err := c.TestConnection(context.Background(), target, "admin", "secret")
+	if !ok || err != nil || msg != "Connection successful (gNMI 0.7.0, 2 models)" {
+		t.Errorf("TestConnection = %v, %q, %v", ok, msg, err)
+	}
+
+	if _, _, err := c.Capabilities(context.Background(), target, "admin", "wrong"); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Capabilities with bad password: got %v, want Unauthenticated", err)
+	}
+}
+
+func TestGNMIGet(t *testing.T) {
+	c, _, target := newFakeGNMI(t)
+
+	updates, _, err := c.Get(context.Background(), target, "admin", "secret", []string{"/interfaces/interface[name=Ethernet1]/config/mtu"}, "json_ietf")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(updates))
+	}
+	u := updates[0]
+	if u.Path != "/interfaces/interface[name=Ethernet1]/config/mtu" {
+		t.Errorf("path = %q", u.Path)
+	}
+	if want := map[string]any{"openconfig-interfaces:mtu": float64(9214)}; !reflect.DeepEqual(u.Value, want) {
+		t.Errorf("value = %#v, want %#v", u.Value, want)
+	}
+	if !u.Timestamp.Equal(time.Unix(0, 1700000000000000000)) {
+		t.Errorf("timestamp = %v", u.Timestamp)
+	}
+
+	if _, _, err := c.Get(context.Background(), target, "admin", "secret", []string{"/system"}, "proto"); status.Code(err) != codes.Unimplemented {
+		t.Errorf("Get with proto encoding: got %v, want Unimplemented", err)
+	}
+	if _, _, err := c.Get(context.Background(), target, "admin", "secret", []string{"/system"}, "xml"); err == nil {
+		t.Error("Get with unknown encoding: expected an error")
+	}
+}
+
+// collect subscribes and returns every update handed to the callback
+func collect(t *testing.T, ctx context.Context, c *GNMIClient, target string, params GNMISubscribeParams) ([]GNMIUpdate, error) {
+	t.Helper()
+	var updates []GNMIUpdate
+	_, err := c.Subscribe(ctx, target, "admin", "secret", params, func(u GNMIUpdate) error {
+		updates = append(updates, u)
+		return nil
+	})
+	return updates, err
+}
+
+func paths(updates []GNMIUpdate) []string {
+	var out []string
+	for _, u := range updates {
+		out = append(out, u.Path)
+	}
+	return out
+}
+
+func TestGNMISubscribeOnce(t *testing.T) {
+	c, fake, target := newFakeGNMI(t)
+
+	// The fake keeps ONCE streams open, so this only returns by honouring the sync response
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	updates, err := collect(t, ctx, c, target, GNMISubscribeParams{Paths: []string{inOctets, operStatus}, Mode: "once"})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("ONCE subscription did not stop at the sync response")
+	}
+	if want := []string{counterPath, statusPath}; !reflect.DeepEqual(paths(updates), want) {
+		t.Errorf("paths = %v, want %v", paths(updates), want)
+	}
+	if updates[0].Value != uint64(1024) || updates[1].Value != "UP" {
+		t.Errorf("values = %v, %v", updates[0].Value, updates[1].Value)
+	}
+
+	list := fake.requests[0]
+	if list.GetMode() != gpb.SubscriptionList_ONCE || list.GetEncoding() != gpb.Encoding_JSON {
+		t.Errorf("request mode/encoding = %v/%v", list.GetMode(), list.GetEncoding())
+	}
+}
+
+func TestGNMISubscribePoll(t *testing.T) {
+	c, fake, target := newFakeGNMI(t)
+
+	updates, err := collect(t, context.Background(), c, target, GNMISubscribeParams{Paths: []string{operStatus}, Mode: "poll", Polls: 3})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	// The initial sync plus two polls, each answered with one update
+	if len(updates) != 3 {
+		t.Errorf("got %d updates, want 3", len(updates))
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if fake.polls != 2 {
+		t.Errorf("server received %d polls, want 2", fake.polls)
+	}
+	if fake.requests[0].GetMode() != gpb.SubscriptionList_POLL {
+		t.Errorf("request mode = %v", fake.requests[0].GetMode())
+	}
+}
+
+func TestGNMISubscribeStream(t *testing.T) {
+	c, fake, target := newFakeGNMI(t)
+
+	params := GNMISubscribeParams{
+		Paths:          []string{inOctets},
+		Mode:           "stream",
+		StreamMode:     "sample",
+		SampleInterval: 2 * time.Second,
+		MaxUpdates:     5,
+	}
+	updates, err := collect(t, context.Background(), c, target, params)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	// Updates keep arriving after the sync response until MaxUpdates is reached
+	if len(updates) != 5 {
+		t.Errorf("got %d updates, want 5", len(updates))
+	}
+
+	sub := fake.requests[0].GetSubscription()[0]
+	if fake.requests[0].GetMode() != gpb.SubscriptionList_STREAM || sub.GetMode() != gpb.SubscriptionMode_SAMPLE {
+		t.Errorf("request modes = %v/%v", fake.requests[0].GetMode(), sub.GetMode())
+	}
+	if sub.GetSampleInterval() != uint64(2*time.Second) {
+		t.Errorf("sample interval = %d", sub.GetSampleInterval())
+	}
+
+	// Cancelling the context ends a stream subscription without an error
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	updates, err = collect(t, ctx, c, target, GNMISubscribeParams{Paths: []string{operStatus}, Mode: "stream", StreamMode: "on-change"})
+	if err != nil {
+		t.Fatalf("Subscribe after cancel: %v", err)
+	}
+	if len(updates) == 0 {
+		t.Error("stream subscription received no updates")
+	}
+
+	// A handler error stops the subscription and is returned
+	stop := errors.New("stop")
+	_, err = c.Subscribe(context.Background(), target, "admin", "secret", GNMISubscribeParams{Paths: []string{operStatus}, Mode: "stream"}, func(GNMIUpdate) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("handler error: got %v, want %v", err, stop)
+	}
+}
+
+func TestGNMIForEndpoint(t *testing.T) {
+	base, _, target := newFakeGNMI(t)
+	endpoint := core.Endpoint{ID: "ep1", Name: "leaf1", Type: core.EndpointTelemetry}
+
+	c, err := base.ForEndpoint(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dials := 0
+	c.dialer = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dials++
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}
+
+	// The connection is kept open between calls
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Capabilities(context.Background(), target, "admin", "secret"); err != nil {
+			t.Fatalf("Capabilities: %v", err)
+		}
+	}
+	if dials != 1 {
+		t.Errorf("dialed %d times, want 1", dials)
+	}
+	conn := c.conns[target]
+
+	if again, _ := base.ForEndpoint(endpoint); again != c {
+		t.Error("ForEndpoint with unchanged settings built a new client")
+	}
+
+	// Changed settings replace the client and close the old connection
+	endpoint.TLS = &core.TLSSettings{ServerName: "leaf1.example.net"}
+	changed, err := base.ForEndpoint(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed == c {
+		t.Error("ForEndpoint with changed settings reused the client")
+	}
+	if conn.GetState() != connectivity.Shutdown {
+		t.Errorf("stale connection state = %v, want SHUTDOWN", conn.GetState())
+	}
+
+	base.ForgetEndpoint(endpoint.ID)
+	if _, ok := base.endpoints[endpoint.ID]; ok {
+		t.Error("ForgetEndpoint kept the cached client")
+	}
+}
+
+func TestBuildSubscribeRequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params GNMISubscribeParams
+	}{
+		{"no paths", GNMISubscribeParams{Mode: "once"}},
+		{"bad mode", GNMISubscribeParams{Paths: []string{"/system"}, Mode: "forever"}},
+		{"bad stream mode", GNMISubscribeParams{Paths: []string{"/system"}, Mode: "stream", StreamMode: "sometimes"}},
+		{"bad encoding", GNMISubscribeParams{Paths: []string{"/system"}, Encoding: "xml"}},
+		{"bad path", GNMISubscribeParams{Paths: []string{"/interfaces/interface[name=Ethernet1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildSubscribeRequest(tt.params); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseGNMIPath(t *testing.T) {
+	elem := func(name string, keys ...string) *gpb.PathElem {
+		pe := &gpb.PathElem{Name: name}
+		for i := 0; i+1 < len(keys); i += 2 {
+			if pe.Key == nil {
+				pe.Key = map[string]string{}
+			}
+			pe.Key[keys[i]] = keys[i+1]
+		}
+		return pe
+	}
+
+	tests := []struct {
+		name   string
+		in     string
+		origin string
+		elems  []*gpb.PathElem
+		render string
+	}{
+		{
+			name:  "plain",
+			in:    "/system/state/hostname",
+			elems: []*gpb.PathElem{elem("system"), elem("state"), elem("hostname")},
+		},
+		{
+			name:  "root",
+			in:    "/",
+			elems: nil,
+		},
+		{
+			name:  "single key",
+			in:    "/interfaces/interface[name=Ethernet1]/state",
+			elems: []*gpb.PathElem{elem("interfaces"), elem("interface", "name", "Ethernet1"), elem("state")},
+		},
+		{
+			name:   "multiple keys",
+			in:     "/network-instances/network-instance[name=default]/protocols/protocol[name=BGP][identifier=BGP]",
+			elems:  []*gpb.PathElem{elem("network-instances"), elem("network-instance", "name", "default"), elem("protocols"), elem("protocol", "name", "BGP", "identifier", "BGP")},
+			render: "/network-instances/network-instance[name=default]/protocols/protocol[identifier=BGP][name=BGP]",
+		},
+		{
+			name:  "slash in key",
+			in:    "/interfaces/interface[name=Ethernet1/1]/state",
+			elems: []*gpb.PathElem{elem("interfaces"), elem("interface", "name", "Ethernet1/1"), elem("state")},
+		},
+		{
+			name:  "escaped bracket in key",
+			in:    `/interfaces/interface[name=Ethernet1]/config/description[text=uplink \] core]`,
+			elems: []*gpb.PathElem{elem("interfaces"), elem("interface", "name", "Ethernet1"), elem("config"), elem("description", "text", "uplink ] core")},
+		},
+		{
+			name:  "escaped backslash and equals",
+			in:    `/acl/entry[match=a\=b\\c]`,
+			elems: []*gpb.PathElem{elem("acl"), elem("entry", "match", `a=b\c`)},
+		},
+		{
+			name:  "escaped slash in name",
+			in:    `/Sysdb/cell\/1/state`,
+			elems: []*gpb.PathElem{elem("Sysdb"), elem("cell/1"), elem("state")},
+		},
+		{
+			name:   "openconfig origin",
+			in:     "openconfig:/interfaces/interface[name=Ethernet1]",
+			origin: "openconfig",
+			elems:  []*gpb.PathElem{elem("interfaces"), elem("interface", "name", "Ethernet1")},
+		},
+		{
+			name:   "eos_native origin",
+			in:     "eos_native:/Sysdb/environment/archer/power/status",
+			origin: "eos_native",
+			elems:  []*gpb.PathElem{elem("Sysdb"), elem("environment"), elem("archer"), elem("power"), elem("status")},
+		},
+		{
+			name:  "colon in key is not an origin",
+			in:    "/interfaces/interface[name=fe80::1/64]",
+			elems: []*gpb.PathElem{elem("interfaces"), elem("interface", "name", "fe80::1/64")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGNMIPath(tt.in)
+			if err != nil {
+				t.Fatalf("ParseGNMIPath(%q): %v", tt.in, err)
+			}
+			if got.GetOrigin() != tt.origin {
+				t.Errorf("origin = %q, want %q", got.GetOrigin(), tt.origin)
+			}
+			if len(got.GetElem()) != len(tt.elems) {
+				t.Fatalf("got %d elems, want %d: %v", len(got.GetElem()), len(tt.elems), got.GetElem())
+			}
+			for i, e := range got.GetElem() {
+				if e.GetName() != tt.elems[i].GetName() || !reflect.DeepEqual(e.GetKey(), tt.elems[i].GetKey()) {
+					t.Errorf("elem %d = %v, want %v", i, e, tt.elems[i])
+				}
+			}
+
+			// Rendering the parsed path gives back an equivalent string
+			want := tt.render
+			if want == "" {
+				want = tt.in
+			}
+			if s := GNMIPathString(nil, got); s != want {
+				t.Errorf("GNMIPathString = %q, want %q", s, want)
+			}
+		})
+	}
+}
+
+func TestParseGNMIPathErrors(t *testing.T) {
+	for _, in := range []string{
+		"/interfaces/interface[name=Ethernet1",
+		"/interfaces/interface]name=Ethernet1[",
+		"/interfaces/interface[Ethernet1]",
+		"/interfaces/interface[=Ethernet1]",
+		"/interfaces/interface[name=Ethernet1]x",
+		`/interfaces/interface\`,
+	} {
+		if _, err := ParseGNMIPath(in); err == nil {
+			t.Errorf("ParseGNMIPath(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseGNMITarget(t *testing.T) {
+	tests := []struct {
+		in   string
+		addr string
+		tls  bool
+	}{
+		{"leaf1", "leaf1:6030", false},
+		{"leaf1:57400", "leaf1:57400", false},
+		{"grpc://10.0.0.1:6030", "10.0.0.1:6030", false},
+		{"grpcs://leaf1", "leaf1:6030", true},
+		{"https://[2001:db8::1]", "[2001:db8::1]:6030", true},
+	}
+	for _, tt := range tests {
+		addr, useTLS, err := parseGNMITarget(tt.in)
+		if err != nil || addr != tt.addr || useTLS != tt.tls {
+			t.Errorf("parseGNMITarget(%q) = %q, %v, %v; want %q, %v", tt.in, addr, useTLS, err, tt.addr, tt.tls)
+		}
+	}
+	for _, in := range []string{"", "ftp://leaf1"} {
+		if _, _, err := parseGNMITarget(in); err == nil {
+			t.Errorf("parseGNMITarget(%q): expected an error", in)
+		}
+	}
+}
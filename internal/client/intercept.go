@@ -0,0 +1,46 @@
+package client
+
+import (
+	"net/http"
+)
+
+// Interceptor sees every request of a client before it reaches the network, e.g. to
+// record or replay a session. next sends the request over the real transport; an
+// interceptor that replays traffic never calls it.
+type Interceptor interface {
+	Intercept(req *http.Request, next http.RoundTripper) (*http.Response, error)
+}
+
+// interceptTransport hands requests to the cache's interceptor, if one is set
+type interceptTransport struct {
+	base  http.RoundTripper
+	cache *TransportCache
+}
+
+// RoundTrip sends a request through the current interceptor or straight to the base transport
+func (t *interceptTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if i := t.cache.Interceptor(); i != nil {
+		return i.Intercept(req, t.base)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// SetInterceptor routes the traffic of every client built by the cache, and of the
+// default clients sharing it, through i. nil restores direct traffic.
+func (t *TransportCache) SetInterceptor(i Interceptor) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.interceptor = i
+}
+
+// Interceptor returns the current interceptor, or nil
+func (t *TransportCache) Interceptor() Interceptor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.interceptor
+}
+
+// wrap puts a base transport behind the cache's interceptor
+func (t *TransportCache) wrap(base http.RoundTripper) http.RoundTripper {
+	return &interceptTransport{base: base, cache: t}
+}
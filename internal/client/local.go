@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// DefaultEAPISocket is where EOS serves eAPI with "protocol unix-socket" enabled
+const DefaultEAPISocket = "/var/run/command-api.sock"
+
+// socketKey carries the Unix socket a request is sent over
+type socketKey struct{}
+
+// commandTarget returns the URL runCmds requests are posted to and, for unix://
+// endpoints, the socket path they travel over. Endpoint URLs are base URLs without
+// the /command-api path; "unix://" alone selects DefaultEAPISocket.
+func commandTarget(baseURL string) (string, string) {
+	if rest, ok := strings.CutPrefix(baseURL, "unix://"); ok {
+		sock := rest
+		if sock == "" || sock == "/" {
+			sock = DefaultEAPISocket
+		}
+		return "http://localhost/command-api", sock
+	}
+	return strings.TrimRight(baseURL, "/") + "/command-api", ""
+}
+
+// IsUnixSocketURL reports whether an endpoint URL addresses a local Unix socket
+func IsUnixSocketURL(u string) bool {
+	return strings.HasPrefix(u, "unix://")
+}
+
+// IsLocalURL reports whether an endpoint URL reaches eAPI on the same box, over the
+// Unix socket or the loopback listener, where no credentials are required
+func IsLocalURL(raw string) bool {
+	if IsUnixSocketURL(raw) {
+		return true
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	if u.Hostname() == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && ip.IsLoopback()
+}
+
+// dialSocketOr returns a dial function that connects to the Unix socket named in the
+// request context and uses next (or a plain TCP dial) for every other request
+func dialSocketOr(next DialFunc) DialFunc {
+	var direct net.Dialer
+	if next == nil {
+		next = direct.DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if sock, ok := ctx.Value(socketKey{}).(string); ok {
+			return direct.DialContext(ctx, "unix", sock)
+		}
+		return next(ctx, network, addr)
+	}
+}
@@ -0,0 +1,226 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProbeEndpoint inspects the network and TLS posture of an endpoint URL: DNS
+// resolution, TCP connect time, the negotiated TLS session and the certificate
+// chain. The chain is always captured, even when it fails verification.
+func ProbeEndpoint(ctx context.Context, endpoint core.Endpoint) core.ConnectionReport {
+	var report core.ConnectionReport
+
+	// On-box eAPI over a Unix socket has no DNS, TCP or TLS stage
+	if IsUnixSocketURL(endpoint.URL) {
+		_, sock := commandTarget(endpoint.URL)
+		report.Host = sock
+		start := time.Now()
+		conn, err := (&net.Dialer{}).DialContext(ctx, "unix", sock)
+		report.TCPConnectMs = time.Since(start).Milliseconds()
+		if err != nil {
+			report.Error = fmt.Sprintf("Unix socket connect failed: %v", err)
+			return report
+		}
+		conn.Close()
+		return report
+	}
+
+	host, port, useTLS, err := probeTarget(endpoint)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Host = host
+	report.Port = port
+
+	dial, err := NewDialer(endpoint.Proxy)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	var start time.Time
+	var conn net.Conn
+	if dial != nil {
+		// The name is resolved on the far side of the proxy or jump host
+		report.Via = describeProxy(endpoint.Proxy)
+		start = time.Now()
+		conn, err = dial(ctx, "tcp", net.JoinHostPort(host, port))
+		report.TCPConnectMs = time.Since(start).Milliseconds()
+		if err != nil {
+			report.Error = fmt.Sprintf("Connect via %s failed: %v", report.Via, err)
+			return report
+		}
+	} else {
+		// DNS resolution
+		start = time.Now()
+		addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+		report.DNSMs = time.Since(start).Milliseconds()
+		if err != nil {
+			report.Error = fmt.Sprintf("DNS resolution failed: %v", err)
+			return report
+		}
+		report.ResolvedAddrs = addrs
+
+		// TCP connect
+		dialer := &net.Dialer{}
+		start = time.Now()
+		conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], port))
+		report.TCPConnectMs = time.Since(start).Milliseconds()
+		if err != nil {
+			report.Error = fmt.Sprintf("TCP connect failed: %v", err)
+			return report
+		}
+	}
+	defer conn.Close()
+
+	if !useTLS {
+		return report
+	}
+
+	// TLS handshake without verification so the chain can be inspected either way
+	cfg, err := BuildTLSConfig(endpoint.TLSVerify, endpoint.TLS)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	serverName := host
+	if cfg.ServerName != "" {
+		serverName = cfg.ServerName
+	}
+	probeCfg := &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         serverName,
+		Certificates:       cfg.Certificates,
+		MinVersion:         cfg.MinVersion,
+	}
+
+	tlsConn := tls.Client(conn, probeCfg)
+	start = time.Now()
+	err = tlsConn.HandshakeContext(ctx)
+	handshake := time.Since(start).Milliseconds()
+	if err != nil {
+		report.Error = fmt.Sprintf("TLS handshake failed: %v", err)
+		return report
+	}
+
+	state := tlsConn.ConnectionState()
+	report.TLS = inspectTLS(state, cfg, serverName)
+	report.TLS.HandshakeMs = handshake
+	return report
+}
+
+// probeTarget extracts host, port and whether TLS is expected from an endpoint URL
+func probeTarget(endpoint core.Endpoint) (string, string, bool, error) {
+	if endpoint.Type == core.EndpointSSH {
+		addr, _, err := SSHTarget(endpoint.URL)
+		if err != nil {
+			return "", "", false, err
+		}
+		host, port, err := net.SplitHostPort(addr)
+		return host, port, false, err
+	}
+	if endpoint.Type == core.EndpointTelemetry {
+		addr, useTLS, err := parseGNMITarget(endpoint.URL)
+		if err != nil {
+			return "", "", false, err
+		}
+		host, port, err := net.SplitHostPort(addr)
+		return host, port, useTLS, err
+	}
+
+	u, err := url.Parse(endpoint.URL)
+	if err != nil {
+		return "", "", false, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Hostname() == "" {
+		return "", "", false, fmt.Errorf("URL has no host: %s", endpoint.URL)
+	}
+
+	useTLS := u.Scheme == "https"
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if useTLS {
+			port = "443"
+		}
+	}
+	return u.Hostname(), port, useTLS, nil
+}
+
+// inspectTLS builds the TLS report from the negotiated session
+func inspectTLS(state tls.ConnectionState, cfg *tls.Config, serverName string) *core.TLSReport {
+	report := &core.TLSReport{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  serverName,
+	}
+
+	now := time.Now()
+	for _, cert := range state.PeerCertificates {
+		sum := sha256.Sum256(cert.Raw)
+		sans := append([]string(nil), cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		report.Chain = append(report.Chain, core.CertificateInfo{
+			Subject:       cert.Subject.String(),
+			Issuer:        cert.Issuer.String(),
+			SANs:          sans,
+			NotBefore:     cert.NotBefore,
+			NotAfter:      cert.NotAfter,
+			DaysRemaining: int(cert.NotAfter.Sub(now).Hours() / 24),
+			SelfSigned:    isSelfSigned(cert),
+			SHA256:        hex.EncodeToString(sum[:]),
+		})
+	}
+	if len(state.PeerCertificates) == 0 {
+		report.VerifyError = "server presented no certificate"
+		return report
+	}
+
+	leaf := state.PeerCertificates[0]
+	report.HostnameMatch = leaf.VerifyHostname(serverName) == nil
+
+	// Verify the chain against the configured CA bundle (or system roots)
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         cfg.RootCAs,
+		Intermediates: intermediates,
+	})
+	if err == nil && cfg.VerifyPeerCertificate != nil {
+		// Pinned fingerprint check
+		var raw [][]byte
+		for _, cert := range state.PeerCertificates {
+			raw = append(raw, cert.Raw)
+		}
+		err = cfg.VerifyPeerCertificate(raw, nil)
+	}
+	report.Verified = err == nil
+	if err != nil {
+		report.VerifyError = err.Error()
+	}
+	return report
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key
+func isSelfSigned(cert *x509.Certificate) bool {
+	if !strings.EqualFold(cert.Subject.String(), cert.Issuer.String()) {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
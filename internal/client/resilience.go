@@ -0,0 +1,334 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPolicy applies to endpoints without their own connection policy. The limits
+// are deliberately low: eAPI requests are served by the switch control plane.
+var DefaultPolicy = core.ConnPolicy{
+	TimeoutSec:       30,
+	QPS:              10,
+	Burst:            5,
+	MaxConcurrent:    4,
+	MaxRetries:       2,
+	RetryBaseMs:      250,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30,
+}
+
+// maxBackoff caps the delay between retries
+const maxBackoff = 10 * time.Second
+
+// ErrCircuitOpen is returned while an endpoint's circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit open: endpoint keeps failing, requests are paused")
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// idempotentKey marks a request context as safe to retry
+type idempotentKey struct{}
+
+// WithIdempotent marks requests made with ctx as safe to retry even when their HTTP
+// method is not, e.g. eAPI runCmds calls that only run show commands
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// isIdempotent reports whether a request may be repeated after it reached the server
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// resolvePolicy fills the unset fields of a policy from the defaults
+func resolvePolicy(p *core.ConnPolicy) core.ConnPolicy {
+	policy := DefaultPolicy
+	if p == nil {
+		return policy
+	}
+	if p.TimeoutSec > 0 {
+		policy.TimeoutSec = p.TimeoutSec
+	}
+	if p.QPS > 0 {
+		policy.QPS = p.QPS
+	}
+	if p.Burst > 0 {
+		policy.Burst = p.Burst
+	}
+	if p.MaxConcurrent > 0 {
+		policy.MaxConcurrent = p.MaxConcurrent
+	}
+	if p.MaxRetries != 0 {
+		policy.MaxRetries = max(p.MaxRetries, 0)
+	}
+	if p.RetryBaseMs > 0 {
+		policy.RetryBaseMs = p.RetryBaseMs
+	}
+	if p.BreakerThreshold > 0 {
+		policy.BreakerThreshold = p.BreakerThreshold
+	}
+	if p.BreakerCooldown > 0 {
+		policy.BreakerCooldown = p.BreakerCooldown
+	}
+	return policy
+}
+
+// guard enforces the rate limit, concurrency limit and circuit breaker of one endpoint
+type guard struct {
+	policy core.ConnPolicy
+	sem    chan struct{}
+
+	mu        sync.Mutex
+	tokens    float64
+	refilled  time.Time
+	failures  int
+	openUntil time.Time
+	trial     bool // a half-open trial request is in flight
+	stats     core.TransportStats
+}
+
+// newGuard creates a guard for a resolved policy
+func newGuard(endpointID string, policy core.ConnPolicy) *guard {
+	return &guard{
+		policy:   policy,
+		sem:      make(chan struct{}, policy.MaxConcurrent),
+		tokens:   float64(policy.Burst),
+		refilled: time.Now(),
+		stats:    core.TransportStats{EndpointID: endpointID},
+	}
+}
+
+// admit checks the circuit breaker. Once the cooldown has passed a single trial
+// request is let through; its outcome closes or reopens the circuit.
+func (g *guard) admit() (trial bool, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.failures < g.policy.BreakerThreshold {
+		return false, nil
+	}
+	if time.Now().Before(g.openUntil) || g.trial {
+		g.stats.Rejected++
+		return false, ErrCircuitOpen
+	}
+	g.trial = true
+	return true, nil
+}
+
+// wait blocks until the rate limiter grants a request
+func (g *guard) wait(ctx context.Context) error {
+	for {
+		g.mu.Lock()
+		now := time.Now()
+		g.tokens = min(float64(g.policy.Burst), g.tokens+now.Sub(g.refilled).Seconds()*g.policy.QPS)
+		g.refilled = now
+		if g.tokens >= 1 {
+			g.tokens--
+			g.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - g.tokens) / g.policy.QPS * float64(time.Second))
+		g.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// record updates the breaker and counters with the outcome of a request
+func (g *guard) record(failed bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if !failed {
+		g.failures = 0
+		return
+	}
+	g.stats.Failures++
+	g.failures++
+	if g.failures >= g.policy.BreakerThreshold {
+		g.openUntil = time.Now().Add(time.Duration(g.policy.BreakerCooldown) * time.Second)
+	}
+}
+
+// endTrial allows the next half-open trial request
+func (g *guard) endTrial() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.trial = false
+}
+
+// snapshot returns the current counters and circuit state
+func (g *guard) snapshot() core.TransportStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	stats := g.stats
+	stats.ConsecutiveFailures = g.failures
+	stats.InFlight = len(g.sem)
+	stats.Circuit = CircuitClosed
+	if g.failures >= g.policy.BreakerThreshold {
+		stats.Circuit = CircuitHalfOpen
+		if time.Now().Before(g.openUntil) {
+			stats.Circuit = CircuitOpen
+			stats.OpenUntil = g.openUntil
+		}
+	}
+	return stats
+}
+
+// reset closes the circuit
+func (g *guard) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.failures = 0
+	g.openUntil = time.Time{}
+}
+
+// guardedTransport applies an endpoint's guard and retry policy around a base transport
+type guardedTransport struct {
+	base  http.RoundTripper
+	guard *guard
+}
+
+// RoundTrip sends a request, retrying connection errors and retryable responses with
+// exponential backoff
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	g := t.guard
+	ctx := req.Context()
+
+	trial, err := g.admit()
+	if err != nil {
+		return nil, err
+	}
+	if trial {
+		// A trial abandoned before it produced an outcome frees the slot for the next one
+		defer g.endTrial()
+	}
+
+	select {
+	case g.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-g.sem }()
+
+	for attempt := 0; ; attempt++ {
+		if err := g.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if attempt > 0 {
+			r = req.Clone(ctx)
+			if req.GetBody != nil {
+				if r.Body, err = req.GetBody(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		g.mu.Lock()
+		g.stats.Requests++
+		g.mu.Unlock()
+
+		resp, err := t.base.RoundTrip(r)
+		failed := requestFailed(ctx, resp, err)
+		if attempt >= g.policy.MaxRetries || !shouldRetry(req, resp, err) {
+			g.record(failed)
+			return resp, err
+		}
+
+		delay := backoff(g.policy.RetryBaseMs, attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			g.record(failed)
+			return nil, err
+		}
+
+		g.mu.Lock()
+		g.stats.Retries++
+		g.mu.Unlock()
+	}
+}
+
+// requestFailed reports whether an outcome counts against the circuit breaker.
+// Authentication errors and other 4xx answers mean the endpoint is up.
+func requestFailed(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// shouldRetry reports whether a request may be sent again. Requests that were never
+// sent because the connection could not be established are always safe to retry.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if err != nil {
+		if req.Context().Err() != nil {
+			return false
+		}
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return isIdempotent(req)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	}
+	return false
+}
+
+// backoff returns the delay before a retry: exponential with jitter, or the server's
+// Retry-After when it asks for one
+func backoff(baseMs, attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, maxBackoff)
+		}
+	}
+
+	d := time.Duration(baseMs) * time.Millisecond << attempt
+	d += time.Duration(rand.Int63n(int64(d)/5 + 1))
+	return min(d, maxBackoff)
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
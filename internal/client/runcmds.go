@@ -0,0 +1,198 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Version is the eAPI output version of a runCmds request
+type Version int
+
+// VersionLatest asks for the newest model revision of every command
+const VersionLatest Version = -1
+
+// MarshalJSON encodes VersionLatest as "latest"
+func (v Version) MarshalJSON() ([]byte, error) {
+	if v == VersionLatest {
+		return []byte(`"latest"`), nil
+	}
+	return strconv.AppendInt(nil, int64(v), 10), nil
+}
+
+// UnmarshalJSON accepts a number or "latest"
+func (v *Version) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		if s != "latest" {
+			return fmt.Errorf("invalid eAPI version %q", s)
+		}
+		*v = VersionLatest
+		return nil
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("invalid eAPI version: %w", err)
+	}
+	*v = Version(n)
+	return nil
+}
+
+// Command is one entry of a runCmds batch. Plain commands are sent as strings; a
+// revision pins the output model of a show command, and input answers a prompt
+// such as the enable password.
+type Command struct {
+	Cmd      string `json:"cmd"`
+	Revision int    `json:"revision,omitempty"`
+	Input    string `json:"input,omitempty"`
+}
+
+// Commands wraps plain command strings
+func Commands(cmds ...string) []Command {
+	out := make([]Command, len(cmds))
+	for i, cmd := range cmds {
+		out[i] = Command{Cmd: cmd}
+	}
+	return out
+}
+
+// MarshalJSON encodes a command as a string unless it carries a revision or input
+func (c Command) MarshalJSON() ([]byte, error) {
+	if c.Revision == 0 && c.Input == "" {
+		return json.Marshal(c.Cmd)
+	}
+	type command Command
+	return json.Marshal(command(c))
+}
+
+// UnmarshalJSON accepts a command string or a {cmd, revision, input} object
+func (c *Command) UnmarshalJSON(b []byte) error {
+	if json.Unmarshal(b, &c.Cmd) == nil {
+		return nil
+	}
+	type command Command
+	var obj command
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return fmt.Errorf("invalid eAPI command: %w", err)
+	}
+	*c = Command(obj)
+	return nil
+}
+
+// JSON-RPC request IDs are numbered per process; the random prefix keeps them unique
+// across restarts
+var (
+	requestPrefix = newRequestPrefix()
+	requestSeq    atomic.Uint64
+)
+
+// newRequestPrefix returns a random per-process request ID prefix
+func newRequestPrefix() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// nextRequestID returns a JSON-RPC request ID not used before by this process
+func nextRequestID() string {
+	return fmt.Sprintf("ae-%s-%d", requestPrefix, requestSeq.Add(1))
+}
+
+// Error returns the eAPI error message, so a JSONRPCError can be returned as an error
+func (e *JSONRPCError) Error() string {
+	return e.Message
+}
+
+// Commands maps every command of the request to its output or error. On a failed
+// batch eAPI returns the outputs of the commands that ran in the error data, the
+// failing command's entry carrying its errors; the commands after it did not run.
+// The second value is the index of the failing command, or -1.
+func (r *JSONRPCResponse) Commands(params RunCmdsParams) ([]core.CommandResult, int) {
+	outputs := r.Result
+	if r.Error != nil {
+		outputs, _ = r.Error.Data.([]any)
+	}
+
+	failed := -1
+	results := make([]core.CommandResult, len(params.Cmds))
+	for i, cmd := range params.Cmds {
+		res := core.CommandResult{
+			Index:    i,
+			Command:  cmd.Cmd,
+			Revision: cmd.Revision,
+			Status:   core.CommandOK,
+		}
+		switch {
+		case i < len(outputs):
+			res.Output = outputs[i]
+			if out, ok := outputs[i].(map[string]any); ok {
+				res.Errors = stringList(out["errors"])
+				res.Warnings = stringList(out["warnings"])
+				res.StartedAt, res.DurationMs = commandTiming(out)
+			}
+			if len(res.Errors) > 0 {
+				res.Status = core.CommandFailed
+				res.Output = nil
+				failed = i
+			}
+		case r.Error != nil && failed < 0:
+			// The error data was cut short before the failing command
+			res.Status = core.CommandFailed
+			res.Errors = []string{r.Error.Message}
+			failed = i
+		case r.Error != nil:
+			res.Status = core.CommandSkipped
+		}
+		results[i] = res
+	}
+	if r.Error != nil && failed < 0 && len(results) > 0 {
+		// Every command produced output, so the batch itself was refused
+		failed = len(results) - 1
+		results[failed].Status = core.CommandFailed
+		results[failed].Errors = append(results[failed].Errors, r.Error.Message)
+	}
+	return results, failed
+}
+
+// commandTiming reads the execution timestamps eAPI adds to an output with the
+// timestamps option
+func commandTiming(out map[string]any) (*time.Time, float64) {
+	meta, ok := out["_meta"].(map[string]any)
+	if !ok {
+		return nil, 0
+	}
+	var started *time.Time
+	if secs, ok := meta["execStartTime"].(float64); ok {
+		t := time.Unix(0, int64(secs*float64(time.Second)))
+		started = &t
+	}
+	duration, _ := meta["execDuration"].(float64)
+	return started, duration * 1000
+}
+
+// stringList converts a decoded JSON array of strings
+func stringList(v any) []string {
+	items, _ := v.([]any)
+	var out []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// checkResponseID verifies a response answers the request it was read for
+func checkResponseID(req JSONRPCRequest, resp *JSONRPCResponse) error {
+	if resp.ID != "" && resp.ID != req.ID {
+		return fmt.Errorf("eAPI response ID %q does not match request %q", resp.ID, req.ID)
+	}
+	return nil
+}
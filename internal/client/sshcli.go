@@ -0,0 +1,500 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Default SSH port for CLI endpoints
+const defaultSSHPort = "22"
+
+// eAPI error codes reproduced by the SSH transport
+const (
+	errCodeInvalidCommand = 1002
+	errCodeCommandFailed  = 1000
+)
+
+var (
+	// cliPrompt matches an EOS prompt such as "leaf1>", "leaf1#" or "leaf1(config-if-Et1)#"
+	cliPrompt = regexp.MustCompile(`(?:^|\n)([A-Za-z0-9][\w.\-]*(?:\([^)\n]*\))?[>#]) ?$`)
+	// cliMore matches the pager marker printed when terminal length is not zero
+	cliMore = regexp.MustCompile(` ?--More-- ?$`)
+	// cliPassword matches the password prompt of "enable"
+	cliPassword = regexp.MustCompile(`(?i)password: ?$`)
+	// ansiEscape matches terminal control sequences
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// SSHClient runs EOS CLI commands over an interactive SSH session, for devices with
+// "management api http-commands" disabled. Results are shaped like eAPI runCmds
+// responses: JSON output via "| json" where the command supports it, text otherwise.
+type SSHClient struct {
+	timeout time.Duration
+	verify  bool
+	hostKey string // pinned device key fingerprint
+	dialer  DialFunc
+
+	mu        sync.Mutex
+	endpoints map[string]cachedSSH // per-endpoint clients built by ForEndpoint
+}
+
+// cachedSSH is an endpoint client together with the settings hash it was built from
+type cachedSSH struct {
+	hash   string
+	client *SSHClient
+}
+
+// NewSSHClient creates a new SSH CLI client. With verifyHostKeys set, device keys are
+// checked against ~/.ssh/known_hosts.
+func NewSSHClient(verifyHostKeys bool, timeout time.Duration) *SSHClient {
+	return &SSHClient{timeout: timeout, verify: verifyHostKeys, endpoints: make(map[string]cachedSSH)}
+}
+
+// ForEndpoint returns a client that uses the endpoint's pinned device key or host key
+// opt-out and its proxy or jump host. The client is reused until those settings change.
+func (c *SSHClient) ForEndpoint(endpoint core.Endpoint) (*SSHClient, error) {
+	hash, err := settingsHash(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.endpoints[endpoint.ID]; ok && cached.hash == hash {
+		return cached.client, nil
+	}
+
+	dial, err := NewDialer(endpoint.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings for %s: %w", endpoint.Name, err)
+	}
+	endpointClient := &SSHClient{
+		timeout: c.timeout,
+		verify:  c.verify && !endpoint.InsecureHostKey,
+		hostKey: endpoint.HostKeySHA256,
+		dialer:  dial,
+	}
+	if c.endpoints == nil {
+		c.endpoints = make(map[string]cachedSSH)
+	}
+	c.endpoints[endpoint.ID] = cachedSSH{hash: hash, client: endpointClient}
+	return endpointClient, nil
+}
+
+// ForgetEndpoint drops the cached client for an endpoint
+func (c *SSHClient) ForgetEndpoint(endpointID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.endpoints, endpointID)
+}
+
+// RunCmds opens a CLI session, runs the commands in order and returns their results
+// as a JSON-RPC response. Like eAPI, execution stops at the first failing command.
+func (c *SSHClient) RunCmds(ctx context.Context, target, user, pass string, params RunCmdsParams) (*JSONRPCResponse, time.Duration, error) {
+	start := time.Now()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	session, err := c.open(ctx, target, user, pass)
+	if err != nil {
+		return nil, time.Since(start), err
+	}
+	defer session.close()
+
+	out := &JSONRPCResponse{JSONRPC: "2.0", ID: nextRequestID(), Result: []any{}}
+	for i, cmd := range params.Cmds {
+		// A command's input answers its own password prompt, e.g. for "enable"
+		session.pass = pass
+		if cmd.Input != "" {
+			session.pass = cmd.Input
+		}
+		result, cmdErr, err := session.run(ctx, cmd.Cmd, params.Format)
+		if err != nil {
+			return nil, time.Since(start), err
+		}
+		if cmdErr != "" {
+			code := errCodeCommandFailed
+			if strings.Contains(cmdErr, "Invalid input") || strings.Contains(cmdErr, "Incomplete command") {
+				code = errCodeInvalidCommand
+			}
+			msg := fmt.Sprintf("CLI command %d of %d '%s' failed: %s", i+1, len(params.Cmds), cmd.Cmd, cmdErr)
+			data := append(out.Result, map[string]any{"errors": []any{cmdErr}})
+			out.Error = &JSONRPCError{Code: code, Message: msg, Data: data}
+			out.Result = nil
+			return out, time.Since(start), out.Error
+		}
+		out.Result = append(out.Result, result)
+	}
+	return out, time.Since(start), nil
+}
+
+// ShowVersion runs "show version" and returns the device metadata
+func (c *SSHClient) ShowVersion(ctx context.Context, target, user, pass string) (*core.DeviceMetadata, time.Duration, error) {
+	rpc, elapsed, err := c.RunCmds(ctx, target, user, pass, RunCmdsParams{Version: 1, Cmds: Commands("show version"), Format: "json"})
+	if err != nil {
+		return nil, elapsed, err
+	}
+
+	meta, err := versionMetadata(rpc.Result[0])
+	return meta, elapsed, err
+}
+
+// TestConnection logs in and runs "show version"
+func (c *SSHClient) TestConnection(ctx context.Context, target, user, pass string) (bool, string, time.Duration, error) {
+	_, elapsed, err := c.ShowVersion(ctx, target, user, pass)
+	if err != nil {
+		return false, err.Error(), elapsed, err
+	}
+	return true, "Connection successful", elapsed, nil
+}
+
+// SSHTarget converts an endpoint URL into host:port and the user it names, if any.
+// Accepted forms: host, host:port and ssh://[user@]host[:port].
+func SSHTarget(target string) (string, string, error) {
+	if target == "" {
+		return "", "", errors.New("SSH target is empty")
+	}
+	host, user := target, ""
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid SSH URL: %w", err)
+		}
+		if u.Scheme != "ssh" {
+			return "", "", fmt.Errorf("unsupported SSH URL scheme: %s", u.Scheme)
+		}
+		host = u.Host
+		if u.User != nil {
+			user = u.User.Username()
+		}
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), defaultSSHPort)
+	}
+	return host, user, nil
+}
+
+// deviceHostKeyCallback verifies a device key against the pinned fingerprint, or against
+// ~/.ssh/known_hosts when none is pinned and verification is on
+func deviceHostKeyCallback(verify bool, pinned string) (ssh.HostKeyCallback, error) {
+	if pinned != "" {
+		want := pinnedFingerprint(pinned)
+		return func(host string, _ net.Addr, key ssh.PublicKey) error {
+			if got := ssh.FingerprintSHA256(key); got != want {
+				return fmt.Errorf("device host key %s of %s does not match pinned %s", got, host, want)
+			}
+			return nil
+		}, nil
+	}
+	if !verify {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	callback, err := knownHostsCallback()
+	if err != nil {
+		return nil, fmt.Errorf("no pinned device host key and %w", err)
+	}
+	return func(host string, remote net.Addr, key ssh.PublicKey) error {
+		if err := callback(host, remote, key); err != nil {
+			return fmt.Errorf("device host key of %s rejected: %w", host, err)
+		}
+		return nil
+	}, nil
+}
+
+// open connects, logs in and starts a shell with paging disabled. The user named in
+// an ssh:// URL is used when no user is given.
+func (c *SSHClient) open(ctx context.Context, target, user, pass string) (*cliSession, error) {
+	addr, urlUser, err := SSHTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if user == "" {
+		user = urlUser
+	}
+
+	var conn net.Conn
+	if c.dialer != nil {
+		conn, err = c.dialer(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	hostKey, err := deviceHostKeyCallback(c.verify, c.hostKey)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	auths := []ssh.AuthMethod{
+		ssh.Password(pass),
+		// EOS with AAA often asks for the password through keyboard-interactive
+		ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+			answers := make([]string, len(questions))
+			for i := range answers {
+				answers[i] = pass
+			}
+			return answers, nil
+		}),
+	}
+	if os.Getenv("SSH_AUTH_SOCK") != "" {
+		auths = append(auths, ssh.PublicKeysCallback(agentSigners))
+	}
+
+	// The handshake and login honor the context deadline
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            user,
+		Auth:            auths,
+		HostKeyCallback: hostKey,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SSH login to %s failed: %w", addr, err)
+	}
+	conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	sess, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	modes := ssh.TerminalModes{ssh.ECHO: 1, ssh.TTY_OP_ISPEED: 38400, ssh.TTY_OP_OSPEED: 38400}
+	if err := sess.RequestPty("vt100", 0, 511, modes); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to request terminal: %w", err)
+	}
+	if err := sess.Shell(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to start shell: %w", err)
+	}
+
+	s := newCLISession(stdin, stdout, pass)
+	s.closer = client
+	if err := s.start(ctx); err != nil {
+		s.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// cliSession drives an interactive EOS CLI over a terminal stream
+type cliSession struct {
+	in     io.Writer
+	chunks chan []byte
+	done   chan error
+	stop   chan struct{}
+	pass   string
+	closer io.Closer
+
+	buf    bytes.Buffer
+	config bool // the last prompt was in configuration mode
+}
+
+// newCLISession starts reading the terminal output of a CLI
+func newCLISession(in io.Writer, out io.Reader, pass string) *cliSession {
+	s := &cliSession{
+		in:     in,
+		chunks: make(chan []byte, 16),
+		done:   make(chan error, 1),
+		stop:   make(chan struct{}),
+		pass:   pass,
+	}
+	go func() {
+		for {
+			b := make([]byte, 4096)
+			n, err := out.Read(b)
+			if n > 0 {
+				select {
+				case s.chunks <- b[:n]:
+				case <-s.stop:
+					return
+				}
+			}
+			if err != nil {
+				s.done <- err
+				close(s.chunks)
+				return
+			}
+		}
+	}()
+	return s
+}
+
+// start waits for the first prompt and disables paging and line wrapping
+func (s *cliSession) start(ctx context.Context) error {
+	if _, err := s.readPrompt(ctx); err != nil {
+		return fmt.Errorf("no CLI prompt: %w", err)
+	}
+	for _, cmd := range []string{"terminal length 0", "terminal width 32767"} {
+		if _, err := s.exec(ctx, cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run executes one command and returns its result shaped like eAPI output, or the
+// CLI error message when the command was rejected
+func (s *cliSession) run(ctx context.Context, cmd, format string) (any, string, error) {
+	// Only show commands have JSON models; other commands yield {} or their messages
+	line := cmd
+	fields := strings.Fields(strings.ToLower(cmd))
+	isShow := len(fields) > 0 && (fields[0] == "show" || fields[0] == "sho" || fields[0] == "sh")
+	wantJSON := format != "text" && isShow && !s.config && !strings.Contains(cmd, "|")
+	if wantJSON {
+		line += " | json"
+	}
+
+	output, err := s.exec(ctx, line)
+	if err != nil {
+		return nil, "", err
+	}
+	if format != "text" && !wantJSON && !isShow {
+		if msg := cliError(output); msg != "" {
+			return nil, msg, nil
+		}
+		if text := strings.TrimSpace(output); text != "" {
+			return map[string]any{"messages": []string{text}}, "", nil
+		}
+		return map[string]any{}, "", nil
+	}
+	if msg := cliError(output); msg != "" {
+		if !wantJSON || !strings.Contains(msg, "unconverted command") {
+			return nil, msg, nil
+		}
+		// Commands without a JSON model fall back to text output
+		if output, err = s.exec(ctx, cmd); err != nil {
+			return nil, "", err
+		}
+		if msg := cliError(output); msg != "" {
+			return nil, msg, nil
+		}
+		return map[string]any{"output": output}, "", nil
+	}
+
+	if !wantJSON {
+		return map[string]any{"output": output}, "", nil
+	}
+	if strings.TrimSpace(output) == "" {
+		return map[string]any{}, "", nil
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err != nil {
+		return map[string]any{"output": output}, "", nil
+	}
+	return result, "", nil
+}
+
+// exec sends a command line and returns its output without the echo and prompt
+func (s *cliSession) exec(ctx context.Context, line string) (string, error) {
+	if _, err := io.WriteString(s.in, line+"\n"); err != nil {
+		return "", fmt.Errorf("failed to send command: %w", err)
+	}
+	output, err := s.readPrompt(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", line, err)
+	}
+
+	// Drop the echoed command line
+	if i := strings.IndexByte(output, '\n'); i >= 0 && strings.Contains(output[:i], strings.TrimSpace(line)) {
+		output = output[i+1:]
+	} else if strings.TrimSpace(output) == strings.TrimSpace(line) {
+		output = ""
+	}
+	return output, nil
+}
+
+// readPrompt reads until the CLI shows a prompt, answering pager and password
+// prompts on the way, and returns the output before the prompt
+func (s *cliSession) readPrompt(ctx context.Context) (string, error) {
+	answeredPassword := false
+	for {
+		text := ansiEscape.ReplaceAllString(strings.ReplaceAll(s.buf.String(), "\r", ""), "")
+		if m := cliPrompt.FindStringSubmatchIndex(text); m != nil {
+			prompt := text[m[2]:m[3]]
+			s.config = strings.Contains(prompt, "(config")
+			s.buf.Reset()
+			return text[:m[0]], nil
+		}
+		if cliMore.MatchString(text) {
+			s.buf.Reset()
+			s.buf.WriteString(cliMore.ReplaceAllString(text, ""))
+			io.WriteString(s.in, " ")
+		} else if cliPassword.MatchString(text) && !answeredPassword {
+			// Answer the prompt and keep it out of the command output
+			answeredPassword = true
+			s.buf.Reset()
+			s.buf.WriteString(cliPassword.ReplaceAllString(text, ""))
+			io.WriteString(s.in, s.pass+"\n")
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case chunk, ok := <-s.chunks:
+			if !ok {
+				err := <-s.done
+				if err == io.EOF {
+					err = errors.New("session closed by device")
+				}
+				return "", err
+			}
+			s.buf.Write(chunk)
+		}
+	}
+}
+
+// close ends the session
+func (s *cliSession) close() {
+	close(s.stop)
+	io.WriteString(s.in, "exit\n")
+	if s.closer != nil {
+		s.closer.Close()
+	}
+}
+
+// cliError returns the error message of rejected command output, e.g. "% Invalid input"
+func cliError(output string) string {
+	var msgs []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "% ") {
+			msgs = append(msgs, strings.TrimPrefix(line, "% "))
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
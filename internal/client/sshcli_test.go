@@ -0,0 +1,613 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// fakeEOS is an SSH stub server that emulates an interactive EOS CLI
+type fakeEOS struct {
+	addr   string
+	key    ssh.Signer
+	enable string // enable password
+
+	// ignoreLength keeps the pager on after "terminal length 0", like a device
+	// whose AAA session overrides it
+	ignoreLength bool
+
+	mu    sync.Mutex
+	lines []string // command lines received, in order
+	ptys  int
+}
+
+// fakeAuth selects how the stub server authenticates users
+type fakeAuth int
+
+const (
+	authPassword fakeAuth = iota
+	authKeyboardInteractive
+)
+
+// newFakeEOS starts the stub server on a loopback port for user admin with password secret
+func newFakeEOS(t *testing.T, auth fakeAuth) *fakeEOS {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ssh.ServerConfig{}
+	switch auth {
+	case authPassword:
+		config.PasswordCallback = func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "admin" && string(pass) == "secret" {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		}
+	case authKeyboardInteractive:
+		config.KeyboardInteractiveCallback = func(c ssh.ConnMetadata, challenge ssh.KeyboardInteractiveChallenge) (*ssh.Permissions, error) {
+			answers, err := challenge("", "", []string{"Password: "}, []bool{false})
+			if err != nil {
+				return nil, err
+			}
+			if c.User() == "admin" && len(answers) == 1 && answers[0] == "secret" {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		}
+	}
+	config.AddHostKey(signer)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	d := &fakeEOS{addr: lis.Addr().String(), key: signer, enable: "enablepw"}
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go d.serve(conn, config)
+		}
+	}()
+	return d
+}
+
+func (d *fakeEOS) serve(nConn net.Conn, config *ssh.ServerConfig) {
+	defer nConn.Close()
+	conn, chans, reqs, err := ssh.NewServerConn(nConn, config)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		ch, requests, err := nc.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				switch req.Type {
+				case "pty-req":
+					d.mu.Lock()
+					d.ptys++
+					d.mu.Unlock()
+					req.Reply(true, nil)
+				case "shell":
+					req.Reply(true, nil)
+					go func() {
+						d.shell(ch)
+						ch.Close()
+					}()
+				default:
+					req.Reply(false, nil)
+				}
+			}
+		}()
+	}
+}
+
+// cliTerm is the terminal state of one stub CLI session
+type cliTerm struct {
+	rw      ssh.Channel
+	r       *bufio.Reader
+	length  int
+	enabled bool
+	mode    string
+}
+
+func (t *cliTerm) write(s string) { t.rw.Write([]byte(s)) }
+
+// prompt renders the EOS prompt with a colour reset, as some AAA banners leave behind
+func (t *cliTerm) prompt() string {
+	end := ">"
+	if t.enabled {
+		end = "#"
+	}
+	return "\x1b[0mleaf1" + t.mode + end
+}
+
+// readLine reads a line, echoing it back like a terminal with ECHO set
+func (t *cliTerm) readLine(echo bool) (string, error) {
+	line, err := t.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if echo {
+		t.write(line)
+	}
+	t.write("\r\n")
+	return line, nil
+}
+
+// page writes output lines, stopping at the pager every length-1 lines
+func (t *cliTerm) page(lines []string) error {
+	for i, line := range lines {
+		if t.length > 0 && i > 0 && i%(t.length-1) == 0 {
+			t.write(" --More-- ")
+			b, err := t.r.ReadByte()
+			if err != nil {
+				return err
+			}
+			t.write("\r\x1b[K")
+			if b == 'q' {
+				return nil
+			}
+		}
+		t.write(line + "\r\n")
+	}
+	return nil
+}
+
+func (d *fakeEOS) shell(ch ssh.Channel) {
+	t := &cliTerm{rw: ch, r: bufio.NewReader(ch), length: 24}
+	t.write("Last login: Sun Oct 18 09:12:44 2026 from 10.0.0.10\r\n")
+	for {
+		t.write(t.prompt())
+		line, err := t.readLine(true)
+		if err != nil {
+			return
+		}
+		d.mu.Lock()
+		d.lines = append(d.lines, line)
+		d.mu.Unlock()
+		if line == "exit" {
+			return
+		}
+		if err := d.handle(t, line); err != nil {
+			return
+		}
+	}
+}
+
+// fakeVersion is the "show version | json" output of the stub device
+const fakeVersion = `{"modelName":"DCS-7050SX3-48YC8","version":"4.32.2F","serialNumber":"JPE21000001","systemMacAddress":"00:1c:73:00:00:01","hardwareRevision":"11.00","uptime":86400.5}`
+
+func (d *fakeEOS) handle(t *cliTerm, line string) error {
+	switch {
+	case line == "":
+	case line == "terminal length 0":
+		if !d.ignoreLength {
+			t.length = 0
+		}
+	case strings.HasPrefix(line, "terminal width "):
+	case line == "enable":
+		t.write("Password: ")
+		pass, err := t.readLine(false)
+		if err != nil {
+			return err
+		}
+		if pass != d.enable {
+			t.write("% Access denied\r\n")
+			return nil
+		}
+		t.enabled = true
+	case line == "configure" || line == "configure terminal":
+		if !t.enabled {
+			t.write("% Invalid input (privileged mode required)\r\n")
+			return nil
+		}
+		t.mode = "(config)"
+	case t.mode != "" && strings.HasPrefix(line, "interface "):
+		t.mode = "(config-if-Et1)"
+	case t.mode != "" && strings.HasPrefix(line, "description "):
+	case t.mode != "" && line == "end":
+		t.mode = ""
+	case line == "show version | json":
+		t.write(fakeVersion + "\r\n")
+	case line == "show version":
+		t.write("Arista DCS-7050SX3-48YC8\r\nSoftware image version: 4.32.2F\r\n")
+	case line == "show running-config | json":
+		t.write("% This is an unconverted command\r\n")
+	case line == "show running-config":
+		t.write("! Command: show running-config\r\nhostname leaf1\r\nend\r\n")
+	case line == "show logging":
+		var lines []string
+		for i := 1; i <= 60; i++ {
+			lines = append(lines, fmt.Sprintf("Oct 18 09:%02d:00 leaf1 Ebra: event %d", i%60, i))
+		}
+		return t.page(lines)
+	default:
+		t.write("% Invalid input (at token 1: '" + strings.Fields(line)[len(strings.Fields(line))-1] + "')\r\n")
+	}
+	return nil
+}
+
+// received returns the command lines the stub server has seen
+func (d *fakeEOS) received() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.lines...)
+}
+
+// newTestSSHClient returns a client that does not offer keys from an ssh-agent
+func newTestSSHClient(t *testing.T, verify bool) *SSHClient {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+	return NewSSHClient(verify, 5*time.Second)
+}
+
+func TestCLIPrompt(t *testing.T) {
+	tests := []struct {
+		text   string
+		prompt string
+	}{
+		{"leaf1>", "leaf1>"},
+		{"leaf1#", "leaf1#"},
+		{"leaf1# ", "leaf1#"},
+		{"output\nleaf1(config)#", "leaf1(config)#"},
+		{"output\nleaf1(config-if-Et1/1)#", "leaf1(config-if-Et1/1)#"},
+		{"spine-01.lab.example.com>", "spine-01.lab.example.com>"},
+		{"Password: ", ""},
+		{"line ending in # but more text", ""},
+		{"leaf1#show version", ""},
+		{" --More-- ", ""},
+	}
+	for _, tt := range tests {
+		m := cliPrompt.FindStringSubmatch(tt.text)
+		got := ""
+		if m != nil {
+			got = m[1]
+		}
+		if got != tt.prompt {
+			t.Errorf("prompt in %q = %q, want %q", tt.text, got, tt.prompt)
+		}
+	}
+}
+
+func TestSSHRunCmds(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	c := newTestSSHClient(t, false)
+
+	params := RunCmdsParams{
+		Version: 1,
+		Cmds: []Command{
+			{Cmd: "show version"},
+			{Cmd: "enable", Input: "enablepw"},
+			{Cmd: "configure"},
+			{Cmd: "interface Ethernet1"},
+			{Cmd: "description uplink"},
+			{Cmd: "end"},
+		},
+		Format: "json",
+	}
+	rpc, _, err := c.RunCmds(context.Background(), "ssh://"+d.addr, "admin", "secret", params)
+	if err != nil {
+		t.Fatalf("RunCmds: %v", err)
+	}
+	if len(rpc.Result) != len(params.Cmds) {
+		t.Fatalf("got %d results, want %d", len(rpc.Result), len(params.Cmds))
+	}
+
+	// "| json" output is decoded into the result
+	version, ok := rpc.Result[0].(map[string]any)
+	if !ok || version["version"] != "4.32.2F" || version["modelName"] != "DCS-7050SX3-48YC8" || version["uptime"] != 86400.5 {
+		t.Errorf("show version result = %#v", rpc.Result[0])
+	}
+	// Configuration commands yield empty results, and the enable password prompt is not output
+	for i, r := range rpc.Result[1:] {
+		if !reflect.DeepEqual(r, map[string]any{}) {
+			t.Errorf("result %d = %#v, want {}", i+1, r)
+		}
+	}
+
+	want := []string{
+		"terminal length 0",
+		"terminal width 32767",
+		"show version | json",
+		"enable",
+		"configure",
+		"interface Ethernet1",
+		"description uplink",
+		"end",
+		"exit",
+	}
+	// The session is closed asynchronously; wait for its exit
+	deadline := time.Now().Add(2 * time.Second)
+	for len(d.received()) < len(want) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := d.received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("commands sent =\n%q\nwant\n%q", got, want)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ptys != 1 {
+		t.Errorf("pty requests = %d, want 1", d.ptys)
+	}
+}
+
+func TestSSHShowVersion(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	c := newTestSSHClient(t, false)
+
+	meta, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret")
+	if err != nil {
+		t.Fatalf("ShowVersion: %v", err)
+	}
+	if meta.Version != "4.32.2F" || meta.Model != "DCS-7050SX3-48YC8" || meta.Serial != "JPE21000001" {
+		t.Errorf("metadata = %+v", meta)
+	}
+}
+
+func TestSSHTextAndFallback(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	c := newTestSSHClient(t, false)
+
+	// Text format sends the command unchanged
+	rpc, _, err := c.RunCmds(context.Background(), d.addr, "admin", "secret", RunCmdsParams{Version: 1, Cmds: Commands("show version"), Format: "text"})
+	if err != nil {
+		t.Fatalf("RunCmds text: %v", err)
+	}
+	if out := rpc.Result[0].(map[string]any)["output"]; out != "Arista DCS-7050SX3-48YC8\nSoftware image version: 4.32.2F" {
+		t.Errorf("text output = %q", out)
+	}
+
+	// Commands without a JSON model are retried as text
+	rpc, _, err = c.RunCmds(context.Background(), d.addr, "admin", "secret", RunCmdsParams{Version: 1, Cmds: Commands("show running-config"), Format: "json"})
+	if err != nil {
+		t.Fatalf("RunCmds unconverted: %v", err)
+	}
+	if out, _ := rpc.Result[0].(map[string]any)["output"].(string); !strings.Contains(out, "hostname leaf1") {
+		t.Errorf("fallback output = %q", out)
+	}
+}
+
+func TestSSHCommandError(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	c := newTestSSHClient(t, false)
+
+	rpc, _, err := c.RunCmds(context.Background(), d.addr, "admin", "secret", RunCmdsParams{Version: 1, Cmds: Commands("show version", "show bogus", "show running-config"), Format: "json"})
+	var rpcErr *JSONRPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("RunCmds error = %v, want a JSONRPCError", err)
+	}
+	if rpcErr.Code != errCodeInvalidCommand {
+		t.Errorf("error code = %d, want %d", rpcErr.Code, errCodeInvalidCommand)
+	}
+	if !strings.Contains(rpcErr.Message, "CLI command 2 of 3 'show bogus' failed: Invalid input") {
+		t.Errorf("error message = %q", rpcErr.Message)
+	}
+	// Like eAPI, the data holds the earlier results followed by the error
+	data, ok := rpcErr.Data.([]any)
+	if !ok || len(data) != 2 {
+		t.Fatalf("error data = %#v", rpcErr.Data)
+	}
+	if rpc.Result != nil {
+		t.Errorf("result = %#v, want nil", rpc.Result)
+	}
+	for _, line := range d.received() {
+		if strings.HasPrefix(line, "show running-config") {
+			t.Error("commands after the failing one were run")
+		}
+	}
+}
+
+func TestSSHPagination(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	d.ignoreLength = true
+	c := newTestSSHClient(t, false)
+
+	rpc, _, err := c.RunCmds(context.Background(), d.addr, "admin", "secret", RunCmdsParams{Version: 1, Cmds: Commands("show logging"), Format: "text"})
+	if err != nil {
+		t.Fatalf("RunCmds: %v", err)
+	}
+	out := rpc.Result[0].(map[string]any)["output"].(string)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 60 {
+		t.Fatalf("got %d lines, want 60:\n%s", len(lines), out)
+	}
+	for i, line := range lines {
+		if !strings.HasSuffix(line, fmt.Sprintf("event %d", i+1)) {
+			t.Errorf("line %d = %q", i+1, line)
+		}
+		if strings.Contains(line, "--More--") {
+			t.Errorf("pager marker left in line %d: %q", i+1, line)
+		}
+	}
+}
+
+func TestSSHKeyboardInteractive(t *testing.T) {
+	d := newFakeEOS(t, authKeyboardInteractive)
+	c := newTestSSHClient(t, false)
+
+	if ok, msg, _, err := c.TestConnection(context.Background(), d.addr, "admin", "secret"); !ok || err != nil {
+		t.Errorf("TestConnection = %v, %q, %v", ok, msg, err)
+	}
+	if _, _, _, err := c.TestConnection(context.Background(), d.addr, "admin", "wrong"); err == nil || !strings.Contains(err.Error(), "SSH login") {
+		t.Errorf("wrong password: got %v, want a login error", err)
+	}
+}
+
+func TestSSHHostKeyVerification(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+
+	writeKnownHosts := func(t *testing.T, key ssh.PublicKey) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		if key == nil {
+			return
+		}
+		dir := filepath.Join(home, ".ssh")
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			t.Fatal(err)
+		}
+		line := knownhosts.Line([]string{knownhosts.Normalize(d.addr)}, key)
+		if err := os.WriteFile(filepath.Join(dir, "known_hosts"), []byte(line+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("known key", func(t *testing.T) {
+		writeKnownHosts(t, d.key.PublicKey())
+		c := newTestSSHClient(t, true)
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err != nil {
+			t.Errorf("ShowVersion: %v", err)
+		}
+	})
+
+	t.Run("mismatched key", func(t *testing.T) {
+		pub, _, _ := ed25519.GenerateKey(rand.Reader)
+		other, _ := ssh.NewPublicKey(pub)
+		writeKnownHosts(t, other)
+		c := newTestSSHClient(t, true)
+		_, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret")
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) || len(keyErr.Want) == 0 {
+			t.Errorf("got %v, want a key mismatch", err)
+		}
+	})
+
+	t.Run("unknown host", func(t *testing.T) {
+		writeKnownHosts(t, nil)
+		c := newTestSSHClient(t, true)
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err == nil || !strings.Contains(err.Error(), "known_hosts") {
+			t.Errorf("got %v, want a known_hosts error", err)
+		}
+	})
+
+	t.Run("verification off", func(t *testing.T) {
+		writeKnownHosts(t, nil)
+		c := newTestSSHClient(t, false)
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err != nil {
+			t.Errorf("ShowVersion: %v", err)
+		}
+	})
+
+	// Per-endpoint settings: a pinned key replaces known_hosts, and verification
+	// can be skipped for one endpoint without touching TLSVerify
+	forEndpoint := func(t *testing.T, endpoint core.Endpoint) *SSHClient {
+		t.Helper()
+		c, err := newTestSSHClient(t, true).ForEndpoint(endpoint)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	t.Run("pinned key", func(t *testing.T) {
+		writeKnownHosts(t, nil)
+		pin := strings.TrimPrefix(ssh.FingerprintSHA256(d.key.PublicKey()), "SHA256:")
+		c := forEndpoint(t, core.Endpoint{ID: "leaf1", TLSVerify: true, HostKeySHA256: pin})
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err != nil {
+			t.Errorf("ShowVersion: %v", err)
+		}
+	})
+
+	t.Run("pinned key mismatch", func(t *testing.T) {
+		writeKnownHosts(t, d.key.PublicKey())
+		pub, _, _ := ed25519.GenerateKey(rand.Reader)
+		other, _ := ssh.NewPublicKey(pub)
+		c := forEndpoint(t, core.Endpoint{ID: "leaf1", HostKeySHA256: ssh.FingerprintSHA256(other)})
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err == nil || !strings.Contains(err.Error(), "device host key") {
+			t.Errorf("got %v, want a device host key mismatch", err)
+		}
+	})
+
+	t.Run("endpoint opts out", func(t *testing.T) {
+		writeKnownHosts(t, nil)
+		c := forEndpoint(t, core.Endpoint{ID: "leaf1", InsecureHostKey: true})
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err != nil {
+			t.Errorf("ShowVersion: %v", err)
+		}
+	})
+
+	t.Run("TLSVerify off still verifies", func(t *testing.T) {
+		writeKnownHosts(t, nil)
+		c := forEndpoint(t, core.Endpoint{ID: "leaf1", TLSVerify: false})
+		if _, _, err := c.ShowVersion(context.Background(), d.addr, "admin", "secret"); err == nil || !strings.Contains(err.Error(), "device host key") {
+			t.Errorf("got %v, want a device host key error", err)
+		}
+	})
+}
+
+func TestSSHUserFromURL(t *testing.T) {
+	d := newFakeEOS(t, authPassword)
+	c := newTestSSHClient(t, false)
+
+	if _, _, err := c.ShowVersion(context.Background(), "ssh://admin@"+d.addr, "", "secret"); err != nil {
+		t.Errorf("ShowVersion with the user in the URL: %v", err)
+	}
+	if _, _, err := c.ShowVersion(context.Background(), "ssh://nobody@"+d.addr, "admin", "secret"); err != nil {
+		t.Errorf("ShowVersion with an explicit user: %v", err)
+	}
+}
+
+func TestSSHTarget(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		user string
+	}{
+		{"leaf1", "leaf1:22", ""},
+		{"leaf1:2222", "leaf1:2222", ""},
+		{"10.0.0.1", "10.0.0.1:22", ""},
+		{"ssh://admin@leaf1", "leaf1:22", "admin"},
+		{"ssh://leaf1:2222", "leaf1:2222", ""},
+		{"ssh://[2001:db8::1]", "[2001:db8::1]:22", ""},
+		{"2001:db8::1", "[2001:db8::1]:22", ""},
+	}
+	for _, tt := range tests {
+		if got, user, err := SSHTarget(tt.in); err != nil || got != tt.want || user != tt.user {
+			t.Errorf("SSHTarget(%q) = %q, %q, %v; want %q, %q", tt.in, got, user, err, tt.want, tt.user)
+		}
+	}
+	for _, in := range []string{"", "https://leaf1"} {
+		if _, _, err := SSHTarget(in); err == nil {
+			t.Errorf("SSHTarget(%q): expected an error", in)
+		}
+	}
+}
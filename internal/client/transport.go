@@ -0,0 +1,247 @@
+package client
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TransportCache builds HTTP clients from per-endpoint transport settings and
+// caches them by endpoint ID. A cached client is rebuilt when the endpoint's
+// settings change. Each client keeps its connections alive for reuse and applies
+// the endpoint's connection policy: rate and concurrency limits, retries and a
+// circuit breaker.
+type TransportCache struct {
+	timeout time.Duration
+
+	mu          sync.Mutex
+	clients     map[string]cachedClient
+	interceptor Interceptor
+}
+
+// cachedClient is an HTTP client together with the settings hash it was built from
+type cachedClient struct {
+	hash   string
+	client *http.Client
+	guard  *guard
+}
+
+// NewTransportCache creates a new transport cache. timeout applies to endpoints
+// whose policy does not set one.
+func NewTransportCache(timeout time.Duration) *TransportCache {
+	return &TransportCache{
+		timeout: timeout,
+		clients: make(map[string]cachedClient),
+	}
+}
+
+// newPooledTransport creates a transport that keeps up to maxConns connections per host alive
+func newPooledTransport(tlsConfig *tls.Config, maxConns int) *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		TLSClientConfig:       tlsConfig,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   maxConns,
+		MaxConnsPerHost:       maxConns,
+		DialContext:           dialSocketOr(nil),
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// Client returns the HTTP client for an endpoint, building it if needed
+func (t *TransportCache) Client(endpoint core.Endpoint) (*http.Client, error) {
+	hash, err := settingsHash(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cached, ok := t.clients[endpoint.ID]; ok && cached.hash == hash {
+		return cached.client, nil
+	}
+
+	tlsConfig, err := BuildTLSConfig(endpoint.TLSVerify, endpoint.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS settings for %s: %w", endpoint.Name, err)
+	}
+
+	// Replace a stale client and release its idle connections
+	if cached, ok := t.clients[endpoint.ID]; ok {
+		cached.client.CloseIdleConnections()
+	}
+
+	dial, err := NewDialer(endpoint.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy settings for %s: %w", endpoint.Name, err)
+	}
+
+	policy := resolvePolicy(endpoint.Policy)
+	timeout := time.Duration(policy.TimeoutSec) * time.Second
+	if endpoint.Policy == nil || endpoint.Policy.TimeoutSec == 0 {
+		timeout = t.timeout
+	}
+
+	base := newPooledTransport(tlsConfig, policy.MaxConcurrent)
+	if dial != nil {
+		// The tunnel replaces both direct dialing and the environment proxy
+		base.Proxy = nil
+		base.DialContext = dialSocketOr(dial)
+	}
+
+	g := newGuard(endpoint.ID, policy)
+	httpClient := &http.Client{
+		Transport: &guardedTransport{base: t.wrap(base), guard: g},
+		Timeout:   timeout,
+	}
+	t.clients[endpoint.ID] = cachedClient{hash: hash, client: httpClient, guard: g}
+	return httpClient, nil
+}
+
+// Stats returns the request counters and circuit state of every cached endpoint
+func (t *TransportCache) Stats() []core.TransportStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]core.TransportStats, 0, len(t.clients))
+	for _, cached := range t.clients {
+		stats = append(stats, cached.guard.snapshot())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].EndpointID < stats[j].EndpointID })
+	return stats
+}
+
+// ResetCircuit closes the circuit breaker of an endpoint so requests resume immediately
+func (t *TransportCache) ResetCircuit(endpointID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cached, ok := t.clients[endpointID]
+	if ok {
+		cached.guard.reset()
+	}
+	return ok
+}
+
+// Forget drops the cached client for an endpoint
+func (t *TransportCache) Forget(endpointID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if cached, ok := t.clients[endpointID]; ok {
+		cached.client.CloseIdleConnections()
+		delete(t.clients, endpointID)
+	}
+}
+
+// settingsHash fingerprints the transport-relevant settings of an endpoint
+func settingsHash(endpoint core.Endpoint) (string, error) {
+	raw, err := json.Marshal(struct {
+		Verify bool              `json:"verify"`
+		TLS    *core.TLSSettings `json:"tls"`
+		Policy *core.ConnPolicy  `json:"policy"`
+		Proxy  *core.ProxyConfig `json:"proxy"`
+		// SSH device host key settings
+		HostKey  string `json:"hostKey"`
+		Insecure bool   `json:"insecureHostKey"`
+	}{endpoint.TLSVerify, endpoint.TLS, endpoint.Policy, endpoint.Proxy, endpoint.HostKeySHA256, endpoint.InsecureHostKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal transport settings: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BuildTLSConfig builds a TLS configuration from the verify flag and optional settings
+func BuildTLSConfig(verify bool, settings *core.TLSSettings) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: !verify}
+	if settings == nil {
+		return cfg, nil
+	}
+
+	if settings.CACertPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(settings.CACertPEM)) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+
+	if settings.ClientCertPEM != "" || settings.ClientKeyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(settings.ClientCertPEM), []byte(settings.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if settings.MinVersion != "" {
+		version, err := parseTLSVersion(settings.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = version
+	}
+
+	cfg.ServerName = settings.ServerName
+
+	if settings.PinnedSHA256 != "" {
+		pin, err := parseFingerprint(settings.PinnedSHA256)
+		if err != nil {
+			return nil, err
+		}
+		// The pin is checked on top of (or, with verification off, instead of) chain validation
+		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return errors.New("server presented no certificate")
+			}
+			sum := sha256.Sum256(rawCerts[0])
+			if !bytes.Equal(sum[:], pin) {
+				return fmt.Errorf("certificate fingerprint %s does not match pinned fingerprint", hex.EncodeToString(sum[:]))
+			}
+			return nil
+		}
+	}
+
+	return cfg, nil
+}
+
+// parseTLSVersion maps a version string such as "1.2" to its tls constant
+func parseTLSVersion(v string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToLower(v), "tls") {
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version: %s", v)
+	}
+}
+
+// parseFingerprint decodes a hex SHA-256 fingerprint, with or without colons
+func parseFingerprint(fp string) ([]byte, error) {
+	clean := strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fp))
+	clean = strings.TrimPrefix(clean, "sha256/")
+	pin, err := hex.DecodeString(clean)
+	if err != nil || len(pin) != sha256.Size {
+		return nil, fmt.Errorf("invalid SHA-256 fingerprint: %s", fp)
+	}
+	return pin, nil
+}
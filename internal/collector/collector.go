@@ -0,0 +1,405 @@
+package collector
+
+import (
+	"arista_engine/internal/client"
+	"arista_engine/internal/core"
+	"arista_engine/internal/credentials"
+	"arista_engine/internal/store"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SampleHandler receives every sample produced by the collector
+type SampleHandler func(core.Sample)
+
+// SubscriptionErrorHandler is told when a gNMI subscription fails and when it will
+// be retried
+type SubscriptionErrorHandler func(endpointID string, err error, retryIn time.Duration)
+
+// Reconnect delays of gNMI subscriptions; the delay doubles after every failed
+// attempt and resets once a subscription has delivered updates
+const (
+	subscribeBackoffMin = time.Second
+	subscribeBackoffMax = 2 * time.Minute
+)
+
+// PollCommand describes an eAPI command whose output is collected.
+// Root is the top-level key of the JSON output holding a map of entries; every numeric
+// field below it becomes a sample at Prefix/<entry>/<field>. When Values is set,
+// text fields (states, statuses) are emitted as well.
+type PollCommand struct {
+	Command string `json:"command"`
+	Root    string `json:"root"`
+	Prefix  string `json:"prefix"`
+	Values  bool   `json:"values,omitempty"`
+}
+
+// DefaultPollCommands collects interface rates and error counters, BGP peer state and PSU status
+var DefaultPollCommands = []PollCommand{
+	{Command: "show interfaces counters rates", Root: "interfaces", Prefix: "interfaces"},
+	{Command: "show interfaces counters errors", Root: "interfaceErrorCounters", Prefix: "interfaces"},
+	{Command: "show ip bgp summary", Root: "vrfs", Prefix: "bgp", Values: true},
+	{Command: "show system environment power", Root: "powerSupplies", Prefix: "power", Values: true},
+}
+
+// Collector feeds samples from periodic eAPI polls and gNMI subscriptions to its handlers
+type Collector struct {
+	store      *store.Store
+	eapiClient *client.EAPIClient
+	gnmiClient *client.GNMIClient
+	creds      *credentials.Manager
+	commands   []PollCommand
+
+	mu            sync.Mutex
+	handlers      []SampleHandler
+	factsHandlers []FactsHandler
+	subErrors     SubscriptionErrorHandler
+	pollCancel    context.CancelFunc
+	factsCancel   context.CancelFunc
+	subs          map[string]context.CancelFunc
+}
+
+// NewCollector creates a new collector
+func NewCollector(store *store.Store, eapiClient *client.EAPIClient, gnmiClient *client.GNMIClient, creds *credentials.Manager) *Collector {
+	return &Collector{
+		store:      store,
+		eapiClient: eapiClient,
+		gnmiClient: gnmiClient,
+		creds:      creds,
+		commands:   DefaultPollCommands,
+		subs:       make(map[string]context.CancelFunc),
+		subErrors:  func(string, error, time.Duration) {},
+	}
+}
+
+// OnSubscriptionError sets a callback for failed gNMI subscription attempts
+func (c *Collector) OnSubscriptionError(fn SubscriptionErrorHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.subErrors = fn
+}
+
+// AddHandler registers a handler for collected samples
+func (c *Collector) AddHandler(h SampleHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, h)
+}
+
+// emit hands a sample to every registered handler
+func (c *Collector) emit(sample core.Sample) {
+	c.mu.Lock()
+	handlers := append([]SampleHandler(nil), c.handlers...)
+	c.mu.Unlock()
+
+	for _, h := range handlers {
+		h(sample)
+	}
+}
+
+// StartPolling polls every eAPI endpoint at the given interval until StopPolling is called
+func (c *Collector) StartPolling(interval time.Duration) error {
+	if interval < time.Second {
+		return errors.New("poll interval must be at least one second")
+	}
+
+	c.mu.Lock()
+	if c.pollCancel != nil {
+		c.mu.Unlock()
+		return errors.New("polling already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.pollCancel = cancel
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.PollOnce(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// StopPolling stops periodic polling
+func (c *Collector) StopPolling() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pollCancel != nil {
+		c.pollCancel()
+		c.pollCancel = nil
+	}
+}
+
+// PollOnce runs the poll commands against every eAPI endpoint and returns the
+// number of samples collected
+func (c *Collector) PollOnce(ctx context.Context) (int, error) {
+	endpoints, err := c.store.GetEndpoints()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+
+	total := 0
+	var errs []error
+	for _, endpoint := range endpoints {
+		if endpoint.Type != core.EndpointEAPI {
+			continue
+		}
+		n, err := c.pollEndpoint(ctx, endpoint)
+		total += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.Name, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// pollEndpoint runs each poll command in its own runCmds call, since eAPI aborts a
+// batch at the first failing command (e.g. BGP not configured)
+func (c *Collector) pollEndpoint(ctx context.Context, endpoint core.Endpoint) (int, error) {
+	endpoint, err := c.creds.Resolve(endpoint)
+	if err != nil {
+		return 0, err
+	}
+	eapiClient, err := c.eapiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	count := 0
+	var errs []error
+	for _, cmd := range c.commands {
+		params := client.RunCmdsParams{
+			Version: 1,
+			Cmds:    client.Commands(cmd.Command),
+			Format:  "json",
+		}
+		rpc, _, _, err := eapiClient.RunCmds(client.WithIdempotent(ctx), endpoint.URL, endpoint.Username, endpoint.Password, params)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cmd.Command, err))
+			continue
+		}
+		if len(rpc.Result) == 0 {
+			continue
+		}
+
+		output, ok := rpc.Result[0].(map[string]any)
+		if !ok {
+			continue
+		}
+		entries, ok := output[cmd.Root].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		now := time.Now()
+		for name, entry := range entries {
+			walk(cmd.Prefix+"/"+name, entry, cmd.Values, func(path string, value any) {
+				c.emit(core.Sample{Device: endpoint.ID, Path: path, Value: value, Timestamp: now})
+				count++
+			})
+		}
+	}
+	return count, errors.Join(errs...)
+}
+
+// StartSubscription opens a gNMI stream subscription for a telemetry endpoint and
+// feeds its updates to the handlers. The subscription is re-established with
+// exponential backoff after errors until StopSubscription is called; the endpoint and
+// its credentials are reloaded before every attempt.
+func (c *Collector) StartSubscription(endpoint core.Endpoint, paths []string, sampleInterval time.Duration) error {
+	if endpoint.Type != core.EndpointTelemetry {
+		return fmt.Errorf("endpoint %s is not a telemetry endpoint", endpoint.Name)
+	}
+	if len(paths) == 0 {
+		return errors.New("at least one path is required")
+	}
+
+	// Fail early on settings that cannot work; reconnects resolve them again
+	if _, _, err := c.subscriptionClient(endpoint); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if _, ok := c.subs[endpoint.ID]; ok {
+		c.mu.Unlock()
+		return fmt.Errorf("subscription already running for %s", endpoint.Name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.subs[endpoint.ID] = cancel
+	c.mu.Unlock()
+
+	params := client.GNMISubscribeParams{
+		Paths:          paths,
+		Mode:           "stream",
+		StreamMode:     "sample",
+		SampleInterval: sampleInterval,
+		Encoding:       "json",
+	}
+
+	go c.runSubscription(ctx, endpoint.ID, params)
+	return nil
+}
+
+// runSubscription keeps a subscription running until ctx is cancelled
+func (c *Collector) runSubscription(ctx context.Context, endpointID string, params client.GNMISubscribeParams) {
+	delay := subscribeBackoffMin
+	for ctx.Err() == nil {
+		received, err := c.subscribeOnce(ctx, endpointID, params)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			delay = subscribeBackoffMin
+		}
+		if err == nil {
+			err = errors.New("subscription closed by the target")
+		}
+
+		c.mu.Lock()
+		report := c.subErrors
+		c.mu.Unlock()
+		report(endpointID, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, subscribeBackoffMax)
+	}
+}
+
+// subscribeOnce loads the endpoint and runs one subscription attempt, reporting
+// whether any update was received
+func (c *Collector) subscribeOnce(ctx context.Context, endpointID string, params client.GNMISubscribeParams) (bool, error) {
+	endpoint, err := c.store.GetEndpoint(endpointID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load endpoint: %w", err)
+	}
+	endpoint, gnmiClient, err := c.subscriptionClient(endpoint)
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	_, err = gnmiClient.Subscribe(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params, func(u client.GNMIUpdate) error {
+		received = true
+		if u.Delete {
+			return nil
+		}
+		emitUpdate(endpoint.ID, u, c.emit)
+		return nil
+	})
+	return received, err
+}
+
+// subscriptionClient resolves the endpoint's credentials and returns its gNMI client
+func (c *Collector) subscriptionClient(endpoint core.Endpoint) (core.Endpoint, *client.GNMIClient, error) {
+	endpoint, err := c.creds.Resolve(endpoint)
+	if err != nil {
+		return endpoint, nil, err
+	}
+	gnmiClient, err := c.gnmiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return endpoint, nil, err
+	}
+	return endpoint, gnmiClient, nil
+}
+
+// StopSubscription stops the gNMI subscription for an endpoint
+func (c *Collector) StopSubscription(endpointID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cancel, ok := c.subs[endpointID]; ok {
+		cancel()
+		delete(c.subs, endpointID)
+	}
+}
+
+// Subscriptions returns the IDs of endpoints with an active subscription
+func (c *Collector) Subscriptions() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ids := make([]string, 0, len(c.subs))
+	for id := range c.subs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stop stops polling, the facts refresh and all subscriptions
+func (c *Collector) Stop() {
+	c.StopPolling()
+	c.StopFactsRefresh()
+	for _, id := range c.Subscriptions() {
+		c.StopSubscription(id)
+	}
+}
+
+// emitUpdate converts a gNMI update into samples. Scalar values are emitted as-is;
+// JSON containers are flattened into one sample per leaf.
+func emitUpdate(device string, u client.GNMIUpdate, emit func(core.Sample)) {
+	if m, ok := u.Value.(map[string]any); ok {
+		walk(strings.TrimSuffix(u.Path, "/"), m, true, func(path string, value any) {
+			emit(core.Sample{Device: device, Path: path, Value: value, Timestamp: u.Timestamp})
+		})
+		return
+	}
+	if s, ok := u.Value.(string); ok {
+		// OpenConfig encodes 64-bit counters as strings in JSON
+		if f, ok := parseNumber(s); ok {
+			emit(core.Sample{Device: device, Path: u.Path, Value: f, Timestamp: u.Timestamp})
+			return
+		}
+	}
+	emit(core.Sample{Device: device, Path: u.Path, Value: u.Value, Timestamp: u.Timestamp})
+}
+
+// walk visits a JSON value and reports every numeric leaf with its path. Numeric
+// strings are converted; other text leaves are reported only when values is set.
+func walk(prefix string, v any, values bool, fn func(path string, value any)) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			walk(prefix+"/"+k, child, values, fn)
+		}
+	case string:
+		if f, ok := parseNumber(val); ok {
+			fn(prefix, f)
+		} else if values {
+			fn(prefix, val)
+		}
+	case []any:
+		// Lists carry no stable key to build a path from
+	default:
+		if f, ok := core.SampleFloat(val); ok {
+			fn(prefix, f)
+		}
+	}
+}
+
+// parseNumber parses a numeric string
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
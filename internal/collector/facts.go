@@ -0,0 +1,221 @@
+package collector
+
+import (
+	"arista_engine/internal/client"
+	"arista_engine/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FactsHandler receives the facts collected from an endpoint
+type FactsHandler func(endpointID string, facts core.DeviceFacts)
+
+// FactsCommands are the show commands whose output makes up the device facts
+var FactsCommands = []string{"show version", "show hostname", "show inventory", "show lldp local-info"}
+
+// AddFactsHandler registers a handler for collected device facts
+func (c *Collector) AddFactsHandler(h FactsHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.factsHandlers = append(c.factsHandlers, h)
+}
+
+// StartFactsRefresh refreshes the facts of every eAPI endpoint at the given interval
+// until StopFactsRefresh is called
+func (c *Collector) StartFactsRefresh(interval time.Duration) error {
+	if interval < time.Minute {
+		return errors.New("facts refresh interval must be at least one minute")
+	}
+
+	c.mu.Lock()
+	if c.factsCancel != nil {
+		c.mu.Unlock()
+		return errors.New("facts refresh already running")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.factsCancel = cancel
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.RefreshFacts(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// StopFactsRefresh stops the scheduled facts refresh
+func (c *Collector) StopFactsRefresh() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.factsCancel != nil {
+		c.factsCancel()
+		c.factsCancel = nil
+	}
+}
+
+// RefreshFacts collects facts from every eAPI endpoint and returns the number of
+// endpoints refreshed
+func (c *Collector) RefreshFacts(ctx context.Context) (int, error) {
+	endpoints, err := c.store.GetEndpoints()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+
+	count := 0
+	var errs []error
+	for _, endpoint := range endpoints {
+		if endpoint.Type != core.EndpointEAPI {
+			continue
+		}
+		if _, err := c.RefreshEndpointFacts(ctx, endpoint); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.Name, err))
+			continue
+		}
+		count++
+	}
+	return count, errors.Join(errs...)
+}
+
+// RefreshEndpointFacts collects the facts of one endpoint and hands them to the facts handlers
+func (c *Collector) RefreshEndpointFacts(ctx context.Context, endpoint core.Endpoint) (core.DeviceFacts, error) {
+	facts, err := c.CollectFacts(ctx, endpoint)
+	if err != nil {
+		return facts, err
+	}
+
+	c.mu.Lock()
+	handlers := append([]FactsHandler(nil), c.factsHandlers...)
+	c.mu.Unlock()
+
+	for _, h := range handlers {
+		h(endpoint.ID, facts)
+	}
+	return facts, nil
+}
+
+// CollectFacts runs the facts commands against an eAPI endpoint. Only show version is
+// required; the other commands fill in what they can.
+func (c *Collector) CollectFacts(ctx context.Context, endpoint core.Endpoint) (core.DeviceFacts, error) {
+	if endpoint.Type != core.EndpointEAPI {
+		return core.DeviceFacts{}, fmt.Errorf("endpoint %s is not an eAPI endpoint", endpoint.Name)
+	}
+
+	endpoint, err := c.creds.Resolve(endpoint)
+	if err != nil {
+		return core.DeviceFacts{}, err
+	}
+	eapiClient, err := c.eapiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.DeviceFacts{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	facts := core.DeviceFacts{CollectedAt: time.Now()}
+	for _, cmd := range FactsCommands {
+		params := client.RunCmdsParams{
+			Version: 1,
+			Cmds:    client.Commands(cmd),
+			Format:  "json",
+		}
+		rpc, _, _, err := eapiClient.RunCmds(client.WithIdempotent(ctx), endpoint.URL, endpoint.Username, endpoint.Password, params)
+		if err != nil || len(rpc.Result) == 0 {
+			if cmd == "show version" {
+				if err == nil {
+					err = errors.New("empty result")
+				}
+				return facts, fmt.Errorf("%s: %w", cmd, err)
+			}
+			continue
+		}
+
+		output, ok := rpc.Result[0].(map[string]any)
+		if !ok {
+			continue
+		}
+		applyFactsOutput(&facts, cmd, output)
+	}
+	return facts, nil
+}
+
+// applyFactsOutput copies the fields of one command's JSON output onto the facts
+func applyFactsOutput(facts *core.DeviceFacts, cmd string, output map[string]any) {
+	switch cmd {
+	case "show version":
+		facts.Model = stringField(output, "modelName")
+		facts.Serial = stringField(output, "serialNumber")
+		facts.Version = stringField(output, "version")
+		facts.MAC = stringField(output, "systemMacAddress")
+		facts.UptimeSeconds = int64(numberField(output, "uptime"))
+		facts.MemTotalKB = int64(numberField(output, "memTotal"))
+		facts.MemFreeKB = int64(numberField(output, "memFree"))
+	case "show hostname":
+		facts.Hostname = stringField(output, "hostname")
+		facts.FQDN = stringField(output, "fqdn")
+	case "show inventory":
+		slots, _ := output["xcvrSlots"].(map[string]any)
+		for slot, raw := range slots {
+			x, ok := raw.(map[string]any)
+			if !ok || stringField(x, "modelName") == "" {
+				// Empty slot
+				continue
+			}
+			facts.Transceivers = append(facts.Transceivers, core.Transceiver{
+				Slot:        slot,
+				Vendor:      strings.TrimSpace(stringField(x, "mfgName")),
+				Model:       strings.TrimSpace(stringField(x, "modelName")),
+				Serial:      strings.TrimSpace(stringField(x, "serialNum")),
+				HardwareRev: strings.TrimSpace(stringField(x, "hardwareRev")),
+			})
+		}
+		sort.Slice(facts.Transceivers, func(i, j int) bool {
+			return slotLess(facts.Transceivers[i].Slot, facts.Transceivers[j].Slot)
+		})
+	case "show lldp local-info":
+		// Newer releases nest the output per VRF under localInfo
+		info := output
+		if nested, ok := output["localInfo"].(map[string]any); ok {
+			info = nested
+			for _, v := range nested {
+				if vrf, ok := v.(map[string]any); ok && stringField(vrf, "chassisId") != "" {
+					info = vrf
+					break
+				}
+			}
+		}
+		facts.ChassisID = stringField(info, "chassisId")
+	}
+}
+
+// stringField returns a string field of a JSON object, or ""
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// numberField returns a numeric field of a JSON object, or 0
+func numberField(m map[string]any, key string) float64 {
+	f, _ := m[key].(float64)
+	return f
+}
+
+// slotLess orders transceiver slots numerically ("2" before "10")
+func slotLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
@@ -6,10 +6,11 @@ import "time"
 type EndpointType string
 
 const (
-	EndpointEAPI     EndpointType = "eapi"
-	EndpointCV       EndpointType = "cloudvision"
-	EndpointEOSREST  EndpointType = "eos_rest"
+	EndpointEAPI      EndpointType = "eapi"
+	EndpointCV        EndpointType = "cloudvision"
+	EndpointEOSREST   EndpointType = "eos_rest"
 	EndpointTelemetry EndpointType = "telemetry"
+	EndpointSSH       EndpointType = "ssh" // EOS CLI over SSH, for devices without eAPI
 )
 
 // Endpoint represents an Arista device or CloudVision controller
@@ -24,54 +25,166 @@ type Endpoint struct {
 	Created   time.Time    `json:"created"`
 	Tags      []string     `json:"tags"`
 	TLSVerify bool         `json:"tlsVerify"`
+	TLS       *TLSSettings `json:"tls,omitempty"`
+	Policy    *ConnPolicy  `json:"policy,omitempty"`
+	Proxy     *ProxyConfig `json:"proxy,omitempty"`
 	Status    string       `json:"status,omitempty"` // Connected, Warning, Failed
+
+	// SSH endpoints only: device host key verification
+	HostKeySHA256   string `json:"hostKeySha256,omitempty"`   // expected device key as printed by ssh-keygen -l
+	InsecureHostKey bool   `json:"insecureHostKey,omitempty"` // skip device host key verification
+
+	CredentialRef string `json:"credentialRef,omitempty"` // named credential used instead of an inline password
+}
+
+// TLSSettings holds per-endpoint transport security options
+type TLSSettings struct {
+	CACertPEM     string `json:"caCertPem,omitempty"`     // custom CA bundle used instead of the system roots
+	PinnedSHA256  string `json:"pinnedSha256,omitempty"`  // hex SHA-256 fingerprint of the server certificate
+	ClientCertPEM string `json:"clientCertPem,omitempty"` // client certificate for mutual TLS
+	ClientKeyPEM  string `json:"clientKeyPem,omitempty"`  // PEM key or a secret reference such as "vault:kv/net/leaf1#key"
+	MinVersion    string `json:"minVersion,omitempty"`    // 1.0, 1.1, 1.2, 1.3
+	ServerName    string `json:"serverName,omitempty"`    // SNI / verification name override
+}
+
+// Proxy types for reaching endpoints that are not directly routable
+const (
+	ProxyHTTP   = "http"   // HTTP CONNECT proxy
+	ProxyHTTPS  = "https"  // HTTP CONNECT proxy reached over TLS
+	ProxySOCKS5 = "socks5" // SOCKS5 proxy
+	ProxySSH    = "ssh"    // SSH jump host (direct-tcpip forwarding)
+)
+
+// ProxyConfig routes an endpoint's connections through a proxy or SSH jump host
+type ProxyConfig struct {
+	Type             string `json:"type"`    // http, https, socks5, ssh
+	Address          string `json:"address"` // host:port of the proxy or jump host
+	Username         string `json:"username,omitempty"`
+	Password         string `json:"password,omitempty"`         // stored encrypted unless it is a secret reference
+	SSHKeyPEM        string `json:"sshKeyPem,omitempty"`        // stored encrypted unless it is a secret reference
+	SSHKeyPassphrase string `json:"sshKeyPassphrase,omitempty"` // stored encrypted unless it is a secret reference
+	HasPassword      bool   `json:"hasPassword,omitempty"`      // set when a stored password was removed from the response
+	HasSSHKey        bool   `json:"hasSshKey,omitempty"`        // set when a stored key was removed from the response
+	UseAgent         bool   `json:"useAgent,omitempty"`         // authenticate with the running ssh-agent
+	HostKeySHA256    string `json:"hostKeySha256,omitempty"`    // expected jump host key as printed by ssh-keygen -l
+	InsecureHostKey  bool   `json:"insecureHostKey,omitempty"`  // skip jump host key verification
+}
+
+// ConnPolicy holds per-endpoint request limits, retry and circuit breaker settings.
+// Zero values fall back to the client defaults.
+type ConnPolicy struct {
+	TimeoutSec       int     `json:"timeoutSec,omitempty"`       // whole-request timeout
+	QPS              float64 `json:"qps,omitempty"`              // sustained requests per second
+	Burst            int     `json:"burst,omitempty"`            // requests allowed above the QPS rate
+	MaxConcurrent    int     `json:"maxConcurrent,omitempty"`    // requests in flight at once
+	MaxRetries       int     `json:"maxRetries,omitempty"`       // retries after the first attempt, -1 disables
+	RetryBaseMs      int     `json:"retryBaseMs,omitempty"`      // first backoff delay, doubled per retry
+	BreakerThreshold int     `json:"breakerThreshold,omitempty"` // consecutive failures that open the circuit
+	BreakerCooldown  int     `json:"breakerCooldownSec,omitempty"`
+}
+
+// TransportStats reports the request counters and circuit state of one endpoint
+type TransportStats struct {
+	EndpointID          string    `json:"endpointId"`
+	Circuit             string    `json:"circuit"` // closed, open, half-open
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	OpenUntil           time.Time `json:"openUntil,omitempty"`
+	InFlight            int       `json:"inFlight"`
+	Requests            int64     `json:"requests"`
+	Retries             int64     `json:"retries"`
+	Failures            int64     `json:"failures"`
+	Rejected            int64     `json:"rejected"`
+}
+
+// Session modes of the eAPI and CloudVision clients
+const (
+	SessionLive      = "live"
+	SessionRecording = "recording"
+	SessionReplay    = "replay"
+)
+
+// SessionStatus reports whether client traffic is live, recorded to a cassette or
+// replayed from one
+type SessionStatus struct {
+	Mode         string `json:"mode"`
+	Name         string `json:"name,omitempty"`
+	Path         string `json:"path,omitempty"` // cassette being replayed
+	Interactions int    `json:"interactions"`   // recorded so far, or available to replay
+	Missed       int    `json:"missed"`         // replayed requests without a recording
 }
 
 // APIDefinition represents a discovered API endpoint
 type APIDefinition struct {
 	ID          string   `json:"id"`
-	Service     string   `json:"service"`     // eapi, cloudvision, telemetry
-	Method      string   `json:"method"`      // GET/POST/PUT/DELETE
+	Service     string   `json:"service"` // eapi, cloudvision, telemetry
+	Method      string   `json:"method"`  // GET/POST/PUT/DELETE
 	Path        string   `json:"path"`
 	Description string   `json:"description"`
 	Params      []string `json:"params"`
 	Category    string   `json:"category,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	OperationID   string   `json:"operationId,omitempty"`
+	QueryParams   []string `json:"queryParams,omitempty"`
+	RequestModel  string   `json:"requestModel,omitempty"`  // model of the request body
+	ResponseModel string   `json:"responseModel,omitempty"` // model of the response
 }
 
 // ExplorerRequest represents an API request from the UI
 type ExplorerRequest struct {
-	EndpointID string                 `json:"endpointId"`
-	Method     string                 `json:"method"` // GET/POST/PUT/DELETE (CV REST) or "runCmds" (eAPI)
-	Path       string                 `json:"path"`   // e.g. "/command-api" or "/api/resources/..."
-	Body       map[string]any         `json:"body,omitempty"`
-	TimeoutMs  int                    `json:"timeoutMs,omitempty"`
+	EndpointID string         `json:"endpointId"`
+	Method     string         `json:"method"` // GET/POST/PUT/DELETE (CV REST) or "runCmds" (eAPI)
+	Path       string         `json:"path"`   // e.g. "/command-api" or "/api/resources/..."
+	Body       map[string]any `json:"body,omitempty"`
+	TimeoutMs  int            `json:"timeoutMs,omitempty"`
 }
 
 // ExplorerResponse represents the response from an API call
 type ExplorerResponse struct {
-	Status     int                    `json:"status"`
-	Headers    map[string][]string    `json:"headers"`
-	JSON       any                    `json:"json,omitempty"`
-	Text       string                 `json:"text,omitempty"`
-	ElapsedMs  int64                  `json:"elapsedMs"`
-	EndpointID string                 `json:"endpointId"`
-	LogID      string                 `json:"logId"`
-	Error      string                 `json:"error,omitempty"`
+	Status        int                 `json:"status"`
+	Headers       map[string][]string `json:"headers"`
+	JSON          any                 `json:"json,omitempty"`
+	Text          string              `json:"text,omitempty"`
+	ElapsedMs     int64               `json:"elapsedMs"`
+	EndpointID    string              `json:"endpointId"`
+	LogID         string              `json:"logId"`
+	Error         string              `json:"error,omitempty"`
+	Commands      []CommandResult     `json:"commands,omitempty"`      // eAPI: per-command outcome
+	FailedCommand *int                `json:"failedCommand,omitempty"` // eAPI: index of the failing command
+}
+
+// Command outcomes within an eAPI batch
+const (
+	CommandOK      = "ok"
+	CommandFailed  = "failed"
+	CommandSkipped = "skipped" // not run because an earlier command failed
+)
+
+// CommandResult is the outcome of one command in an eAPI runCmds batch
+type CommandResult struct {
+	Index      int        `json:"index"`
+	Command    string     `json:"command"`
+	Revision   int        `json:"revision,omitempty"`
+	Status     string     `json:"status"`
+	Output     any        `json:"output,omitempty"`
+	Errors     []string   `json:"errors,omitempty"`
+	Warnings   []string   `json:"warnings,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`  // with the timestamps option
+	DurationMs float64    `json:"durationMs,omitempty"` // with the timestamps option
 }
 
 // APIQueryRecord represents a logged API query
 type APIQueryRecord struct {
-	ID         string                 `json:"id"`
-	EndpointID string                 `json:"endpointId"`
-	Method     string                 `json:"method"`
-	Path       string                 `json:"path"`
-	Body       map[string]any         `json:"body,omitempty"`
-	Status     int                    `json:"status"`
-	Response   map[string]any         `json:"response"`
-	Timestamp  time.Time              `json:"timestamp"`
-	ElapsedMs  int64                  `json:"elapsedMs"`
-	Error      string                 `json:"error,omitempty"`
+	ID         string         `json:"id"`
+	EndpointID string         `json:"endpointId"`
+	Method     string         `json:"method"`
+	Path       string         `json:"path"`
+	Body       map[string]any `json:"body,omitempty"`
+	Status     int            `json:"status"`
+	Response   map[string]any `json:"response"`
+	Timestamp  time.Time      `json:"timestamp"`
+	ElapsedMs  int64          `json:"elapsedMs"`
+	Error      string         `json:"error,omitempty"`
 }
 
 // ConnectionTestResult represents the result of testing an endpoint connection
@@ -83,6 +196,83 @@ type ConnectionTestResult struct {
 	Details    any    `json:"details,omitempty"`
 }
 
+// ConnectionReport represents the detailed findings of a connection test
+type ConnectionReport struct {
+	Host          string          `json:"host"`
+	Port          string          `json:"port"`
+	ResolvedAddrs []string        `json:"resolvedAddrs,omitempty"`
+	DNSMs         int64           `json:"dnsMs"`
+	TCPConnectMs  int64           `json:"tcpConnectMs"`
+	TLS           *TLSReport      `json:"tls,omitempty"`
+	Via           string          `json:"via,omitempty"` // proxy or jump host the probe went through
+	Auth          *AuthReport     `json:"auth,omitempty"`
+	Token         *TokenInfo      `json:"token,omitempty"`
+	Device        *DeviceMetadata `json:"device,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// TLSReport represents the negotiated TLS session and the server's certificate chain
+type TLSReport struct {
+	Version       string            `json:"version"`
+	CipherSuite   string            `json:"cipherSuite"`
+	ServerName    string            `json:"serverName"`
+	HandshakeMs   int64             `json:"handshakeMs"`
+	Verified      bool              `json:"verified"`
+	VerifyError   string            `json:"verifyError,omitempty"`
+	HostnameMatch bool              `json:"hostnameMatch"`
+	Chain         []CertificateInfo `json:"chain"`
+}
+
+// CertificateInfo summarizes one certificate of the presented chain
+type CertificateInfo struct {
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	SANs          []string  `json:"sans,omitempty"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+	DaysRemaining int       `json:"daysRemaining"`
+	SelfSigned    bool      `json:"selfSigned"`
+	SHA256        string    `json:"sha256"`
+}
+
+// AuthReport represents the authentication outcome of a connection test
+type AuthReport struct {
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Message    string `json:"message"`
+}
+
+// TokenInfo represents the claims decoded from a CloudVision bearer token (JWT)
+type TokenInfo struct {
+	ID            string    `json:"id,omitempty"`
+	Subject       string    `json:"subject,omitempty"`
+	Issuer        string    `json:"issuer,omitempty"`
+	IssuedAt      time.Time `json:"issuedAt,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt,omitempty"`
+	NoExpiry      bool      `json:"noExpiry"`
+	Expired       bool      `json:"expired"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Warning       string    `json:"warning,omitempty"`
+}
+
+// CVServiceToken represents a token minted for a CloudVision service account
+type CVServiceToken struct {
+	ID          string    `json:"id"`
+	User        string    `json:"user"`
+	Description string    `json:"description,omitempty"`
+	ValidUntil  time.Time `json:"validUntil"`
+	Token       string    `json:"token,omitempty"`
+}
+
+// DeviceMetadata represents identifying facts reported by the device or controller
+type DeviceMetadata struct {
+	Version  string `json:"version,omitempty"` // EOS or CloudVision version
+	Model    string `json:"model,omitempty"`
+	Serial   string `json:"serial,omitempty"`
+	MAC      string `json:"mac,omitempty"`
+	Hardware string `json:"hardware,omitempty"`
+}
+
 // DeviceInventory represents a formal device inventory record
 type DeviceInventory struct {
 	ID           string    `json:"id" db:"id"`
@@ -98,6 +288,117 @@ type DeviceInventory struct {
 	TestCount    int       `json:"testCount" db:"test_count"`
 	SuccessCount int       `json:"successCount" db:"success_count"`
 	Notes        string    `json:"notes" db:"notes"`
+	Serial       string    `json:"serial,omitempty" db:"serial"`
+	Model        string    `json:"model,omitempty" db:"model"`
+	Version      string    `json:"version,omitempty" db:"version"`
+	ManagementIP string    `json:"managementIp,omitempty" db:"management_ip"`
+	Streaming    string    `json:"streaming,omitempty" db:"streaming"` // CloudVision streaming status
+	Source       string    `json:"source,omitempty" db:"source"`       // manual, import, cloudvision
+	Missing      bool      `json:"missing,omitempty" db:"missing"`     // no longer reported by its source
+	LastSynced   time.Time `json:"lastSynced,omitempty" db:"last_synced"`
+
+	// Facts collected from the device over eAPI
+	Hostname       string        `json:"hostname,omitempty" db:"hostname"`
+	FQDN           string        `json:"fqdn,omitempty" db:"fqdn"`
+	MAC            string        `json:"mac,omitempty" db:"mac"`
+	ChassisID      string        `json:"chassisId,omitempty" db:"chassis_id"`
+	UptimeSeconds  int64         `json:"uptimeSeconds,omitempty" db:"uptime_seconds"`
+	MemTotalKB     int64         `json:"memTotalKb,omitempty" db:"mem_total_kb"`
+	MemFreeKB      int64         `json:"memFreeKb,omitempty" db:"mem_free_kb"`
+	Transceivers   []Transceiver `json:"transceivers,omitempty" db:"transceivers"`
+	FactsCollected time.Time     `json:"factsCollected,omitempty" db:"facts_collected"`
+}
+
+// DeviceFacts holds the facts collected from a device by show commands
+type DeviceFacts struct {
+	Hostname      string        `json:"hostname"`
+	FQDN          string        `json:"fqdn"`
+	Model         string        `json:"model"`
+	Serial        string        `json:"serial"`
+	Version       string        `json:"version"`
+	MAC           string        `json:"mac"`
+	ChassisID     string        `json:"chassisId"`
+	UptimeSeconds int64         `json:"uptimeSeconds"`
+	MemTotalKB    int64         `json:"memTotalKb"`
+	MemFreeKB     int64         `json:"memFreeKb"`
+	Transceivers  []Transceiver `json:"transceivers"`
+	CollectedAt   time.Time     `json:"collectedAt"`
+}
+
+// Transceiver describes a transceiver slot reported by show inventory
+type Transceiver struct {
+	Slot        string `json:"slot"`
+	Vendor      string `json:"vendor"`
+	Model       string `json:"model"`
+	Serial      string `json:"serial"`
+	HardwareRev string `json:"hardwareRev,omitempty"`
+}
+
+// InventoryFilter selects inventory records; every non-empty field must match.
+// Fields accept glob patterns and match case-insensitively.
+type InventoryFilter struct {
+	Query   string `json:"query,omitempty"` // substring of name, hostname, serial or URL
+	Model   string `json:"model,omitempty"`
+	Version string `json:"version,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Tag     string `json:"tag,omitempty"`
+}
+
+// InventoryGroup is a set of inventory records sharing a fact value
+type InventoryGroup struct {
+	Key     string            `json:"key"`
+	Devices []DeviceInventory `json:"devices"`
+}
+
+// CVDevice is a device as reported by the CloudVision inventory
+type CVDevice struct {
+	Serial          string `json:"serial"`
+	Hostname        string `json:"hostname"`
+	FQDN            string `json:"fqdn"`
+	Model           string `json:"model"`
+	Version         string `json:"version"`
+	MAC             string `json:"mac"`
+	StreamingStatus string `json:"streamingStatus"` // active, inactive
+	ManagementIP    string `json:"managementIp"`
+}
+
+// CVSyncResult summarises a CloudVision inventory sync
+type CVSyncResult struct {
+	Cluster string   `json:"cluster"`
+	Devices int      `json:"devices"`
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Missing []string `json:"missing"`
+}
+
+// TestHistoryEntry represents one recorded connection test of a device
+type TestHistoryEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Success    bool      `json:"success"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	LatencyMs  int64     `json:"latencyMs"`
+}
+
+// ImportResult summarises an inventory import or its dry-run preview
+type ImportResult struct {
+	Format     string        `json:"format"`
+	DryRun     bool          `json:"dryRun"`
+	Entries    []ImportEntry `json:"entries"`
+	Added      int           `json:"added"`
+	Duplicates int           `json:"duplicates"`
+	Invalid    int           `json:"invalid"`
+}
+
+// ImportEntry is one host parsed from an inventory file
+type ImportEntry struct {
+	Source   string   `json:"source"` // line number or inventory host name
+	Endpoint Endpoint `json:"endpoint"`
+	Action   string   `json:"action"` // add, duplicate, invalid
+	Reason   string   `json:"reason,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // APICatalog represents the complete enumerated API surface
@@ -107,19 +408,86 @@ type APICatalog struct {
 	EOSREST     map[string]APIDefinition `json:"eos_rest"`
 	Telemetry   map[string]APIDefinition `json:"telemetry"`
 	LastUpdated time.Time                `json:"lastUpdated"`
+
+	Source        string `json:"source,omitempty"`        // title of the API reference
+	SourceVersion string `json:"sourceVersion,omitempty"` // release of the API reference, e.g. 7.1.1-7010119952
+}
+
+// Catalog source kinds
+const (
+	CatalogSourceReference = "reference" // API reference document parsed into a catalog
+	CatalogSourceImport    = "import"    // catalog JSON file merged into the active catalog
+)
+
+// CatalogSource is a document the active catalog is built from
+type CatalogSource struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Path      string    `json:"path"`
+	Hash      string    `json:"hash"` // SHA-256 of the file when it was last built
+	Version   string    `json:"version,omitempty"`
+	Endpoints int       `json:"endpoints"`
+	Built     time.Time `json:"built"`
+}
+
+// CatalogProgress reports a catalog rebuild or import to the UI
+type CatalogProgress struct {
+	Source     string `json:"source"`
+	Stage      string `json:"stage"` // parsing, saving, done, failed
+	Line       int    `json:"line,omitempty"`
+	TotalLines int    `json:"totalLines,omitempty"`
+	Endpoints  int    `json:"endpoints,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CatalogVersion summarizes a stored catalog version
+type CatalogVersion struct {
+	Version     string    `json:"version"`
+	Source      string    `json:"source"`
+	Endpoints   int       `json:"endpoints"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
+
+// CatalogDiff lists the operations that differ between two catalog versions.
+// Operations are identified by method and path.
+type CatalogDiff struct {
+	From            string          `json:"from"`
+	To              string          `json:"to"`
+	Added           []APIDefinition `json:"added"`
+	Removed         []APIDefinition `json:"removed"`
+	Changed         []CatalogChange `json:"changed"`
+	AffectedQueries []AffectedQuery `json:"affectedQueries"`
+}
+
+// CatalogChange is an operation present in both versions whose definition changed
+type CatalogChange struct {
+	Method string        `json:"method"`
+	Path   string        `json:"path"`
+	Fields []string      `json:"fields"` // params, queryParams, requestModel, responseModel, category, service
+	From   APIDefinition `json:"from"`
+	To     APIDefinition `json:"to"`
+}
+
+// AffectedQuery is a logged query that uses an operation removed or changed by a catalog diff
+type AffectedQuery struct {
+	QueryID    string `json:"queryId"`
+	EndpointID string `json:"endpointId"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Reason     string `json:"reason"`
 }
 
 // CommandTemplate represents a pre-built command template
 type CommandTemplate struct {
-	ID          string                 `json:"id"`
-	Title       string                 `json:"title"`
-	Description string                 `json:"description"`
-	Service     string                 `json:"service"` // eapi, cloudvision
-	Method      string                 `json:"method"`
-	Path        string                 `json:"path"`
-	Body        map[string]any         `json:"body,omitempty"`
-	Category    string                 `json:"category"`
-	Tags        []string               `json:"tags"`
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Service     string         `json:"service"` // eapi, cloudvision
+	Method      string         `json:"method"`
+	Path        string         `json:"path"`
+	Body        map[string]any `json:"body,omitempty"`
+	Category    string         `json:"category"`
+	Tags        []string       `json:"tags"`
 }
 
 // PolicyRule represents a safety policy rule
@@ -133,3 +501,174 @@ type PolicyRule struct {
 	Effect      string            `json:"effect"` // allow, deny
 	Enabled     bool              `json:"enabled"`
 }
+
+// Sample represents a single collected value for a device path (from polling or telemetry)
+type Sample struct {
+	Device    string    `json:"device"`
+	Path      string    `json:"path"`
+	Value     any       `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SeriesPoint represents one (possibly downsampled) point of a time series
+type SeriesPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"` // average when downsampled
+	Min       float64   `json:"min"`
+	Max       float64   `json:"max"`
+	Count     int64     `json:"count"`
+}
+
+// SeriesStats represents min/max/avg aggregations over a time series window
+type SeriesStats struct {
+	Device     string    `json:"device"`
+	Path       string    `json:"path"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Min        float64   `json:"min"`
+	Max        float64   `json:"max"`
+	Avg        float64   `json:"avg"`
+	First      float64   `json:"first"`
+	Last       float64   `json:"last"`
+	Count      int64     `json:"count"`
+	Resolution string    `json:"resolution"` // data the stats were computed from: raw, 1m or 1h
+}
+
+// SampleFloat converts a sample value to float64 if it is numeric
+func SampleFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// AlertRule represents a threshold rule evaluated against collected samples
+type AlertRule struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Device      string    `json:"device,omitempty"` // endpoint ID or glob; empty matches all devices
+	Expression  string    `json:"expression"`       // e.g. "inErrors delta > 100 in 5m", "peerState != Established"
+	Severity    string    `json:"severity"`         // info, warning, critical
+	Enabled     bool      `json:"enabled"`
+	Created     time.Time `json:"created"`
+}
+
+// AlertEvent represents a state change of an alert (firing or resolved)
+type AlertEvent struct {
+	ID        string    `json:"id"`
+	RuleID    string    `json:"ruleId"`
+	RuleName  string    `json:"ruleName"`
+	Device    string    `json:"device"`
+	Path      string    `json:"path"`
+	State     string    `json:"state"` // firing, resolved
+	Severity  string    `json:"severity"`
+	Value     any       `json:"value"`
+	Message   string    `json:"message"`
+	Flapping  bool      `json:"flapping,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AlertSink represents an outbound notification target for alert events
+type AlertSink struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`              // webhook, syslog
+	Address string `json:"address"`           // webhook URL or syslog host:port
+	Network string `json:"network,omitempty"` // udp or tcp (syslog only)
+	Enabled bool   `json:"enabled"`
+}
+
+// Topology is a discovered LLDP graph of the network
+type Topology struct {
+	Nodes       []TopologyNode `json:"nodes"`
+	Links       []TopologyLink `json:"links"`
+	Errors      []string       `json:"errors,omitempty"` // endpoints that could not be polled
+	CollectedAt time.Time      `json:"collectedAt"`
+}
+
+// TopologyNode is a device in the topology; Known is false for LLDP neighbors
+// that are not in the inventory
+type TopologyNode struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	EndpointID   string `json:"endpointId,omitempty"`
+	ChassisID    string `json:"chassisId,omitempty"`
+	Description  string `json:"description,omitempty"`
+	ManagementIP string `json:"managementIp,omitempty"`
+	Known        bool   `json:"known"`
+}
+
+// TopologyLink is a link between two node ports
+type TopologyLink struct {
+	ID         string `json:"id"`
+	Source     string `json:"source"`
+	SourcePort string `json:"sourcePort"`
+	Target     string `json:"target"`
+	TargetPort string `json:"targetPort"`
+	SpeedBps   int64  `json:"speedBps,omitempty"`
+	Status     string `json:"status"` // confirmed (seen from both ends), one-sided, unverified
+}
+
+// NeighborWalkRequest configures a neighbor-hop discovery walk starting from a known endpoint
+type NeighborWalkRequest struct {
+	SeedEndpointID string   `json:"seedEndpointId"`
+	MaxHops        int      `json:"maxHops"`         // hops away from the seed to explore (default 1)
+	Allow          []string `json:"allow,omitempty"` // CIDR ranges candidates must fall in; empty allows all
+	Deny           []string `json:"deny,omitempty"`  // CIDR ranges that are never contacted
+	Username       string   `json:"username,omitempty"`
+	Password       string   `json:"password,omitempty"`
+	CredentialRef  string   `json:"credentialRef,omitempty"`
+	TLSVerify      bool     `json:"tlsVerify"`
+}
+
+// DiscoveryCandidate is a proposed endpoint found through an LLDP neighbor, awaiting review
+type DiscoveryCandidate struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	ManagementIP  string    `json:"managementIp"`
+	Hop           int       `json:"hop"`
+	DiscoveredVia string    `json:"discoveredVia"` // name of the device that reported the neighbor
+	ViaPort       string    `json:"viaPort"`       // port on the reporting device
+	NeighborPort  string    `json:"neighborPort"`
+	Reachable     bool      `json:"reachable"`
+	Message       string    `json:"message"`
+	Model         string    `json:"model,omitempty"`
+	Version       string    `json:"version,omitempty"`
+	Serial        string    `json:"serial,omitempty"`
+	Endpoint      Endpoint  `json:"endpoint"`
+	Discovered    time.Time `json:"discovered"`
+}
+
+// CredentialProfile is a named set of credentials shared by endpoints through
+// Endpoint.CredentialRef. Secrets are stored encrypted and never returned to the UI.
+type CredentialProfile struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Username    string    `json:"username,omitempty"`
+	Password    string    `json:"password,omitempty"` // stored encrypted
+	Token       string    `json:"token,omitempty"`    // stored encrypted
+	HasPassword bool      `json:"hasPassword"`
+	HasToken    bool      `json:"hasToken"`
+	Created     time.Time `json:"created"`
+	Rotated     time.Time `json:"rotated,omitempty"`
+}
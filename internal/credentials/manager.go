@@ -0,0 +1,403 @@
+package credentials
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/secrets"
+	"arista_engine/internal/store"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// encPrefix marks an encrypted secret value
+const encPrefix = "enc:v1:"
+
+// Manager stores credential profiles with their secrets encrypted (AES-256-GCM)
+// and resolves endpoint credential references at dispatch time
+type Manager struct {
+	store   *store.Store
+	aead    cipher.AEAD
+	secrets *secrets.Registry
+}
+
+// NewManager creates a credential manager using the key in keyPath, generating
+// a new random key on first use. Secret references such as "vault:kv/net/eapi#password"
+// in endpoint or profile credentials are resolved through the registry, which may be nil.
+func NewManager(store *store.Store, keyPath string, registry *secrets.Registry) (*Manager, error) {
+	key, err := loadKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return &Manager{store: store, aead: aead, secrets: registry}, nil
+}
+
+// loadKey reads the 32-byte encryption key, creating the key file if it does not exist
+func loadKey(keyPath string) ([]byte, error) {
+	key, err := os.ReadFile(keyPath)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("credential key %s has invalid length %d", keyPath, len(key))
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read credential key: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate credential key: %w", err)
+	}
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write credential key: %w", err)
+	}
+	return key, nil
+}
+
+// SaveProfile creates or updates a credential profile. On update, an empty
+// password or token keeps the stored secret.
+func (m *Manager) SaveProfile(profile core.CredentialProfile) (core.CredentialProfile, error) {
+	if strings.TrimSpace(profile.Name) == "" {
+		return core.CredentialProfile{}, errors.New("credential profile name is required")
+	}
+
+	if profile.ID == "" {
+		profile.ID = fmt.Sprintf("cred_%d", time.Now().UnixNano())
+		profile.Created = time.Now()
+	} else {
+		existing, err := m.store.GetCredentialProfile(profile.ID)
+		if err != nil {
+			return core.CredentialProfile{}, err
+		}
+		profile.Created = existing.Created
+		profile.Rotated = existing.Rotated
+		if profile.Password == "" {
+			profile.Password = existing.Password
+		}
+		if profile.Token == "" {
+			profile.Token = existing.Token
+		}
+	}
+
+	if err := m.seal(&profile); err != nil {
+		return core.CredentialProfile{}, err
+	}
+	if err := m.store.SaveCredentialProfile(profile); err != nil {
+		return core.CredentialProfile{}, fmt.Errorf("failed to save credential profile: %w", err)
+	}
+	return redact(profile), nil
+}
+
+// Profiles returns all credential profiles with their secrets removed
+func (m *Manager) Profiles() ([]core.CredentialProfile, error) {
+	profiles, err := m.store.GetCredentialProfiles()
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		profiles[i] = redact(profiles[i])
+	}
+	return profiles, nil
+}
+
+// DeleteProfile deletes a credential profile that no endpoint references
+func (m *Manager) DeleteProfile(id string) error {
+	users, err := m.references(id)
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 {
+		return fmt.Errorf("credential profile is used by %d endpoint(s): %s", len(users), strings.Join(users, ", "))
+	}
+	return m.store.DeleteCredentialProfile(id)
+}
+
+// Rotate replaces the secrets of a profile and returns the names of the endpoints
+// that pick up the new credentials. Empty values leave the current secret in place.
+// The profile may be given by ID or name.
+func (m *Manager) Rotate(id, username, password, token string) ([]string, error) {
+	profile, err := m.lookup(id)
+	if err != nil {
+		return nil, err
+	}
+	if password == "" && token == "" && username == "" {
+		return nil, errors.New("nothing to rotate")
+	}
+
+	if username != "" {
+		profile.Username = username
+	}
+	if password != "" {
+		profile.Password = password
+	}
+	if token != "" {
+		profile.Token = token
+	}
+	profile.Rotated = time.Now()
+
+	if err := m.seal(&profile); err != nil {
+		return nil, err
+	}
+	if err := m.store.SaveCredentialProfile(profile); err != nil {
+		return nil, fmt.Errorf("failed to save credential profile: %w", err)
+	}
+	if m.secrets != nil {
+		m.secrets.Invalidate()
+	}
+	return m.references(profile.ID)
+}
+
+// Resolve fills in an endpoint's credentials from its referenced profile. Credentials
+// set on the endpoint itself override the profile. The reference may be a profile ID or name.
+// Secret provider references in the resulting password and token, the proxy secrets and
+// the TLS client key are then resolved.
+func (m *Manager) Resolve(endpoint core.Endpoint) (core.Endpoint, error) {
+	if endpoint.CredentialRef != "" {
+		profile, err := m.lookup(endpoint.CredentialRef)
+		if err != nil {
+			return endpoint, fmt.Errorf("endpoint %s: %w", endpoint.Name, err)
+		}
+		if err := m.open(&profile); err != nil {
+			return endpoint, fmt.Errorf("endpoint %s: %w", endpoint.Name, err)
+		}
+
+		if endpoint.Username == "" {
+			endpoint.Username = profile.Username
+		}
+		if endpoint.Password == "" {
+			endpoint.Password = profile.Password
+		}
+		if endpoint.Token == "" {
+			endpoint.Token = profile.Token
+		}
+	}
+
+	if endpoint.Proxy != nil {
+		proxy, err := m.openProxy(*endpoint.Proxy)
+		if err != nil {
+			return endpoint, fmt.Errorf("endpoint %s: %w", endpoint.Name, err)
+		}
+		endpoint.Proxy = &proxy
+	}
+
+	if m.secrets == nil {
+		return endpoint, nil
+	}
+	// Usernames are never resolved: they are not secrets, and imports reject references in them
+	fields := []*string{&endpoint.Password, &endpoint.Token}
+	if endpoint.Proxy != nil {
+		proxy := endpoint.Proxy
+		fields = append(fields, &proxy.Password, &proxy.SSHKeyPEM, &proxy.SSHKeyPassphrase)
+	}
+	if endpoint.TLS != nil {
+		tls := *endpoint.TLS
+		endpoint.TLS = &tls
+		fields = append(fields, &tls.ClientKeyPEM)
+	}
+
+	ctx := context.Background()
+	for _, field := range fields {
+		value, err := m.secrets.Resolve(ctx, *field)
+		if err != nil {
+			return endpoint, fmt.Errorf("endpoint %s: %w", endpoint.Name, err)
+		}
+		*field = value
+	}
+	return endpoint, nil
+}
+
+// SealProxy encrypts the proxy secrets of an endpoint before it is saved. Secrets left
+// empty keep the ones of the stored proxy, which may be nil; secret references are
+// saved as they are so they can still be resolved and shown.
+func (m *Manager) SealProxy(endpoint *core.Endpoint, stored *core.ProxyConfig) error {
+	if endpoint.Proxy == nil {
+		return nil
+	}
+	proxy := *endpoint.Proxy
+	if stored != nil {
+		if proxy.Password == "" && proxy.HasPassword {
+			proxy.Password = stored.Password
+		}
+		if proxy.SSHKeyPEM == "" && proxy.HasSSHKey {
+			proxy.SSHKeyPEM = stored.SSHKeyPEM
+			if proxy.SSHKeyPassphrase == "" {
+				proxy.SSHKeyPassphrase = stored.SSHKeyPassphrase
+			}
+		}
+	}
+
+	for _, field := range []*string{&proxy.Password, &proxy.SSHKeyPEM, &proxy.SSHKeyPassphrase} {
+		if m.secrets != nil && m.secrets.IsReference(*field) {
+			continue
+		}
+		sealed, err := m.encrypt(*field)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt proxy secret of %s: %w", endpoint.Name, err)
+		}
+		*field = sealed
+	}
+	proxy.HasPassword = false
+	proxy.HasSSHKey = false
+	endpoint.Proxy = &proxy
+	return nil
+}
+
+// openProxy returns a copy of a proxy with its secrets decrypted, so the stored
+// endpoint keeps the sealed values
+func (m *Manager) openProxy(proxy core.ProxyConfig) (core.ProxyConfig, error) {
+	for _, field := range []*string{&proxy.Password, &proxy.SSHKeyPEM, &proxy.SSHKeyPassphrase} {
+		plain, err := m.decrypt(*field)
+		if err != nil {
+			return proxy, fmt.Errorf("failed to decrypt proxy secret: %w", err)
+		}
+		*field = plain
+	}
+	return proxy, nil
+}
+
+// RedactProxy removes the encrypted proxy secrets of an endpoint before it leaves the
+// backend. Secret references are kept since they hold no secret.
+func RedactProxy(endpoint core.Endpoint) core.Endpoint {
+	if endpoint.Proxy == nil {
+		return endpoint
+	}
+	proxy := *endpoint.Proxy
+	if strings.HasPrefix(proxy.Password, encPrefix) {
+		proxy.Password = ""
+		proxy.HasPassword = true
+	}
+	if strings.HasPrefix(proxy.SSHKeyPEM, encPrefix) {
+		proxy.SSHKeyPEM = ""
+		proxy.HasSSHKey = true
+	}
+	if strings.HasPrefix(proxy.SSHKeyPassphrase, encPrefix) {
+		proxy.SSHKeyPassphrase = ""
+	}
+	endpoint.Proxy = &proxy
+	return endpoint
+}
+
+// lookup finds a profile by ID, falling back to a case-insensitive name match
+func (m *Manager) lookup(ref string) (core.CredentialProfile, error) {
+	if profile, err := m.store.GetCredentialProfile(ref); err == nil {
+		return profile, nil
+	}
+
+	profiles, err := m.store.GetCredentialProfiles()
+	if err != nil {
+		return core.CredentialProfile{}, err
+	}
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, ref) {
+			return p, nil
+		}
+	}
+	return core.CredentialProfile{}, fmt.Errorf("credential profile %q not found", ref)
+}
+
+// references returns the names of the endpoints that use a profile
+func (m *Manager) references(id string) ([]string, error) {
+	profile, err := m.store.GetCredentialProfile(id)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := m.store.GetEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, ep := range endpoints {
+		if ep.CredentialRef == profile.ID || (ep.CredentialRef != "" && strings.EqualFold(ep.CredentialRef, profile.Name)) {
+			names = append(names, ep.Name)
+		}
+	}
+	return names, nil
+}
+
+// seal encrypts the secrets of a profile in place
+func (m *Manager) seal(profile *core.CredentialProfile) error {
+	var err error
+	if profile.Password, err = m.encrypt(profile.Password); err != nil {
+		return err
+	}
+	if profile.Token, err = m.encrypt(profile.Token); err != nil {
+		return err
+	}
+	profile.HasPassword = profile.Password != ""
+	profile.HasToken = profile.Token != ""
+	return nil
+}
+
+// open decrypts the secrets of a profile in place
+func (m *Manager) open(profile *core.CredentialProfile) error {
+	var err error
+	if profile.Password, err = m.decrypt(profile.Password); err != nil {
+		return fmt.Errorf("failed to decrypt password of %s: %w", profile.Name, err)
+	}
+	if profile.Token, err = m.decrypt(profile.Token); err != nil {
+		return fmt.Errorf("failed to decrypt token of %s: %w", profile.Name, err)
+	}
+	return nil
+}
+
+// encrypt seals a secret; empty and already encrypted values are returned unchanged
+func (m *Manager) encrypt(plain string) (string, error) {
+	if plain == "" || strings.HasPrefix(plain, encPrefix) {
+		return plain, nil
+	}
+
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := m.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a secret produced by encrypt
+func (m *Manager) decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, encPrefix) {
+		return value, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < m.aead.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	nonce, sealed := raw[:m.aead.NonceSize()], raw[m.aead.NonceSize():]
+	plain, err := m.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// redact removes the secrets of a profile before it leaves the backend
+func redact(profile core.CredentialProfile) core.CredentialProfile {
+	profile.HasPassword = profile.Password != ""
+	profile.HasToken = profile.Token != ""
+	profile.Password = ""
+	profile.Token = ""
+	return profile
+}
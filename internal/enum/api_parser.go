@@ -6,30 +6,67 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 )
 
 // APIParser handles parsing and organizing the complete Arista API surface
 type APIParser struct {
-	catalog *core.APICatalog
+	catalog  *core.APICatalog
+	report   *ParseReport
+	progress ProgressFunc
 }
 
+// ProgressFunc is called while a document is parsed with the number of lines read so far
+type ProgressFunc func(line, total int)
+
+// progressInterval is how many lines are parsed between progress calls
+const progressInterval = 5000
+
 // NewAPIParser creates a new API parser
 func NewAPIParser() *APIParser {
-	return &APIParser{
-		catalog: &core.APICatalog{
-			EAPI:        make(map[string]core.APIDefinition),
-			CloudVision: make(map[string]core.APIDefinition),
-			EOSREST:     make(map[string]core.APIDefinition),
-			Telemetry:   make(map[string]core.APIDefinition),
-			LastUpdated: time.Now(),
-		},
+	return &APIParser{catalog: newCatalog()}
+}
+
+// newCatalog creates an empty catalog
+func newCatalog() *core.APICatalog {
+	return &core.APICatalog{
+		EAPI:        make(map[string]core.APIDefinition),
+		CloudVision: make(map[string]core.APIDefinition),
+		EOSREST:     make(map[string]core.APIDefinition),
+		Telemetry:   make(map[string]core.APIDefinition),
+		LastUpdated: time.Now(),
 	}
 }
 
-// ParseEnumeratedAPI parses the complete enumerated API from the markdown file
+var (
+	// operationLine matches an operation, e.g. "get /aaa-tacacs/{name}"
+	operationLine = regexp.MustCompile(`^(get|post|put|delete|patch)\s+(/\S*)$`)
+	// operationLike matches lines that start like an operation, to report the ones operationLine rejects
+	operationLike = regexp.MustCompile(`(?i)^(get|post|put|delete|patch)\s+/`)
+	// headerLike matches category headers, e.g. "AaaTacacs"
+	headerLike = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+	// sourceTitle matches the title of the reference, e.g. "Arista Networks REST API (7.1.1-7010119952)"
+	sourceTitle = regexp.MustCompile(`^(.*\bAPI)\s*\(([0-9][^)\s]*)\)$`)
+	// operationIDLine matches the operation ID that follows an operation in the details, e.g. "(showTacacs)"
+	operationIDLine = regexp.MustCompile(`^\((\w+)\)$`)
+	// parameterLine matches a parameter of the details, e.g. "limit-output (optional)"
+	parameterLine = regexp.MustCompile(`^(\S+) \((?:optional|required)\)$`)
+	// pathParam matches a path parameter, e.g. "{name}"
+	pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+)
+
+// sectionWords are headings of the reference that look like categories but are not
+var sectionWords = map[string]bool{
+	"Access": true, "Methods": true, "Consumes": true, "Produces": true,
+	"Responses": true, "Up": true, "Models": true,
+}
+
+// ParseEnumeratedAPI parses the complete enumerated API from the markdown file,
+// replacing the current catalog
 func (p *APIParser) ParseEnumeratedAPI(filePath string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -42,138 +79,216 @@ func (p *APIParser) ParseEnumeratedAPI(filePath string) error {
 		return fmt.Errorf("failed to read API file: %w", err)
 	}
 
+	p.catalog = newCatalog()
+	p.report = newParseReport(filepath.Base(filePath))
 	return p.parseContent(string(content))
 }
 
-// parseContent parses the API content and extracts endpoints
+// Report returns the report of the last parse, or nil before the first one
+func (p *APIParser) Report() *ParseReport {
+	return p.report
+}
+
+// parseContent parses the API content and extracts endpoints. The reference lists every
+// operation twice, in the table of contents and in the method details, each time under a
+// category header; parsing stops at the Models section. The details add the operation ID,
+// query parameters and request and response models.
 func (p *APIParser) parseContent(content string) error {
 	lines := strings.Split(content, "\n")
-	
-	var currentCategory string
-	
+	for i := range lines {
+		lines[i] = strings.TrimSpace(lines[i])
+	}
+	report := p.report
+	if report == nil {
+		report = newParseReport("")
+		p.report = report
+	}
+	report.Lines = len(lines)
+
+	var basePath, currentCategory, section string
+	var detail *core.APIDefinition // operation whose details are being read
+	categories := make(map[string]bool)
+	seen := make(map[string]*core.APIDefinition) // "METHOD path" -> definition, in the category first listed
+	var order []*core.APIDefinition
+
+parse:
 	for i, line := range lines {
-		line = strings.TrimSpace(line)
-		
-		// Skip empty lines and headers
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Arista Networks") {
+		lineNo := i + 1
+		if p.progress != nil && lineNo%progressInterval == 0 {
+			p.progress(lineNo, len(lines))
+		}
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case line == "Models":
+			break parse
+		case strings.HasPrefix(line, "BasePath:"):
+			basePath = strings.TrimSpace(strings.TrimPrefix(line, "BasePath:"))
+			report.BasePath = basePath
 			continue
 		}
-		
-		// Detect category headers (e.g., "AaaTacacs", "AccessLists")
-		if isCategoryHeader(line) {
-			currentCategory = line
+
+		if p.catalog.SourceVersion == "" && currentCategory == "" {
+			if m := sourceTitle.FindStringSubmatch(line); m != nil {
+				p.catalog.Source, p.catalog.SourceVersion = m[1], m[2]
+				report.SourceVersion = m[2]
+				continue
+			}
+		}
+
+		if headerLike.MatchString(line) && !sectionWords[line] {
+			if isCategoryHeader(lines, i) {
+				currentCategory = line
+				categories[line] = true
+			} else {
+				report.suspect(lineNo, line, "not followed by an operation")
+			}
+			detail, section = nil, ""
 			continue
 		}
-		
-		// Parse API endpoints
-		if strings.Contains(line, " /") {
-			endpoint := p.parseEndpoint(line, currentCategory)
-			if endpoint != nil {
-				// Determine service type based on path patterns
-				service := p.determineService(endpoint.Path)
-				endpoint.Service = service
-				endpoint.Category = currentCategory
-				
-				// Add to appropriate catalog
-				key := fmt.Sprintf("%s_%s_%s", service, currentCategory, endpoint.Method)
-				switch service {
-				case "eapi":
-					p.catalog.EAPI[key] = *endpoint
-				case "cloudvision":
-					p.catalog.CloudVision[key] = *endpoint
-				case "eos_rest":
-					p.catalog.EOSREST[key] = *endpoint
-				case "telemetry":
-					p.catalog.Telemetry[key] = *endpoint
-				}
+
+		matches := operationLine.FindStringSubmatch(line)
+		if matches == nil {
+			if operationLike.MatchString(line) {
+				report.unparsed(lineNo, line, "malformed operation")
+				detail, section = nil, ""
+				continue
+			}
+			if detail != nil {
+				section = readDetail(detail, section, line)
 			}
+			continue
+		}
+		detail, section = nil, ""
+		report.OperationLines++
+		if currentCategory == "" {
+			report.unparsed(lineNo, line, "operation outside a category")
+			continue
 		}
-		
-		// Progress indicator for large files
-		if i%1000 == 0 && i > 0 {
-			fmt.Printf("Parsed %d lines...\n", i)
+
+		endpoint := p.parseEndpoint(matches[1], matches[2], currentCategory)
+		service := p.determineService(basePath, endpoint.Path)
+		if service == "" {
+			report.unparsed(lineNo, line, "unknown service for "+basePath+endpoint.Path)
+			continue
 		}
+		operation := endpoint.Method + " " + endpoint.Path
+		if first, ok := seen[operation]; ok {
+			if first.Category != currentCategory {
+				report.unparsed(lineNo, line, "already listed under "+first.Category)
+			} else {
+				detail = first
+			}
+			continue
+		}
+		endpoint.Service = service
+		seen[operation] = endpoint
+		order = append(order, endpoint)
+		detail = endpoint
 	}
-	
+
+	if p.progress != nil {
+		p.progress(len(lines), len(lines))
+	}
+
+	for _, endpoint := range order {
+		// Add to appropriate catalog
+		switch endpoint.Service {
+		case "eapi":
+			p.catalog.EAPI[endpoint.ID] = *endpoint
+		case "cloudvision":
+			p.catalog.CloudVision[endpoint.ID] = *endpoint
+		case "eos_rest":
+			p.catalog.EOSREST[endpoint.ID] = *endpoint
+		case "telemetry":
+			p.catalog.Telemetry[endpoint.ID] = *endpoint
+		}
+		report.count(endpoint.Service, endpoint.Method)
+	}
+	report.Categories = len(categories)
 	return nil
 }
 
-// isCategoryHeader determines if a line is a category header
-func isCategoryHeader(line string) bool {
-	// Category headers are typically single words or short phrases
-	// and don't contain HTTP methods or paths
-	if strings.Contains(line, " /") || strings.Contains(line, "get ") || 
-	   strings.Contains(line, "post ") || strings.Contains(line, "put ") || 
-	   strings.Contains(line, "delete ") {
-		return false
+// readDetail applies one line of an operation's details, returning the section the next
+// line belongs to
+func readDetail(endpoint *core.APIDefinition, section, line string) string {
+	switch line {
+	case "Path parameters", "Query parameters", "Request body", "Return type":
+		return line
+	case "Consumes", "Produces", "Example data", "Responses":
+		return ""
 	}
-	
-	// Must be a reasonable length and not contain special characters
-	if len(line) > 50 || strings.Contains(line, " ") && len(line) > 30 {
-		return false
+
+	switch section {
+	case "":
+		if m := operationIDLine.FindStringSubmatch(line); m != nil && endpoint.OperationID == "" {
+			endpoint.OperationID = m[1]
+		}
+	case "Query parameters":
+		if m := parameterLine.FindStringSubmatch(line); m != nil && !slices.Contains(endpoint.QueryParams, m[1]) {
+			endpoint.QueryParams = append(endpoint.QueryParams, m[1])
+		}
+	case "Request body":
+		// e.g. "body aaa-tacacs-create (optional)"
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "body" {
+			endpoint.RequestModel = fields[1]
+		}
+		return ""
+	case "Return type":
+		endpoint.ResponseModel = line
+		return ""
 	}
-	
-	return true
+	return section
 }
 
-// parseEndpoint parses a single API endpoint line
-func (p *APIParser) parseEndpoint(line, category string) *core.APIDefinition {
-	// Extract HTTP method and path
-	re := regexp.MustCompile(`(get|post|put|delete)\s+(/[^\s]+)`)
-	matches := re.FindStringSubmatch(line)
-	
-	if len(matches) < 3 {
-		return nil
+// isCategoryHeader reports whether the header-like line at i starts a category: the next
+// significant line, skipping "Up" links, is an operation
+func isCategoryHeader(lines []string, i int) bool {
+	for _, next := range lines[i+1:] {
+		if next == "" || next == "Up" {
+			continue
+		}
+		return operationLine.MatchString(next)
 	}
-	
-	method := strings.ToUpper(matches[1])
-	path := matches[2]
-	
-	// Generate description based on path and category
-	description := p.generateDescription(category, path, method)
-	
-	// Extract parameters from path
-	params := p.extractParameters(path)
-	
-	// Generate tags
-	tags := p.generateTags(category, path, method)
-	
+	return false
+}
+
+// parseEndpoint builds the definition of one operation
+func (p *APIParser) parseEndpoint(method, path, category string) *core.APIDefinition {
+	method = strings.ToUpper(method)
+
 	return &core.APIDefinition{
 		ID:          fmt.Sprintf("%s_%s_%s", category, method, strings.ReplaceAll(path, "/", "_")),
 		Service:     "", // Will be set by caller
 		Method:      method,
 		Path:        path,
-		Description: description,
-		Params:      params,
+		Description: p.generateDescription(category, path, method),
+		Params:      p.extractParameters(path),
 		Category:    category,
-		Tags:        tags,
+		Tags:        p.generateTags(category, path, method),
 	}
 }
 
-// determineService determines the service type based on path patterns
-func (p *APIParser) determineService(path string) string {
-	// CloudVision patterns
-	if strings.Contains(path, "/api/") || strings.Contains(path, "/resources/") {
+// determineService determines the service type from where an operation lives: the
+// document's base path joined with the operation path. It returns "" when the location
+// matches no known API.
+func (p *APIParser) determineService(basePath, path string) string {
+	full := strings.TrimSuffix(basePath, "/") + path
+
+	switch {
+	case strings.HasPrefix(full, "/vRest/"), full == "/vRest":
+		// EOS REST API
+		return "eos_rest"
+	case strings.HasPrefix(full, "/command-api"):
+		// eAPI (JSON-RPC over /command-api)
+		return "eapi"
+	case strings.HasPrefix(full, "/api/"), strings.HasPrefix(full, "/cvpservice/"):
+		// CloudVision resource and REST APIs
 		return "cloudvision"
-	}
-	
-	// Telemetry patterns
-	if strings.Contains(path, "/telemetry/") || strings.Contains(path, "/streaming/") {
+	case strings.HasPrefix(full, "/telemetry/"), strings.HasPrefix(full, "/streaming/"):
 		return "telemetry"
 	}
-	
-	// eAPI patterns (JSON-RPC over /command-api)
-	if strings.Contains(path, "/command-api") {
-		return "eapi"
-	}
-	
-	// EOS REST API patterns (all the /vRest endpoints)
-	if strings.HasPrefix(path, "/") && !strings.Contains(path, "/api/") {
-		return "eos_rest"
-	}
-	
-	// Default to eos_rest for most other patterns
-	return "eos_rest"
+	return ""
 }
 
 // generateDescription creates a human-readable description
@@ -193,8 +308,7 @@ func (p *APIParser) generateDescription(category, path, method string) string {
 
 // extractParameters extracts parameter names from the path
 func (p *APIParser) extractParameters(path string) []string {
-	re := regexp.MustCompile(`\{([^}]+)\}`)
-	matches := re.FindAllStringSubmatch(path, -1)
+	matches := pathParam.FindAllStringSubmatch(path, -1)
 	
 	var params []string
 	for _, match := range matches {
@@ -238,6 +352,16 @@ func (p *APIParser) GetCatalog() *core.APICatalog {
 	return p.catalog
 }
 
+// SetCatalog replaces the catalog, e.g. with one loaded from the store
+func (p *APIParser) SetCatalog(catalog *core.APICatalog) {
+	p.catalog = catalog
+}
+
+// SetProgress sets a callback for parse progress; nil removes it
+func (p *APIParser) SetProgress(fn ProgressFunc) {
+	p.progress = fn
+}
+
 // SaveCatalog saves the catalog to a JSON file
 func (p *APIParser) SaveCatalog(filePath string) error {
 	data, err := json.MarshalIndent(p.catalog, "", "  ")
@@ -0,0 +1,189 @@
+package enum
+
+import (
+	"arista_engine/internal/core"
+	"flag"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files under testdata")
+
+// parseExcerpt parses one excerpt under testdata
+func parseExcerpt(t *testing.T, name string) *APIParser {
+	t.Helper()
+	p := NewAPIParser()
+	if err := p.ParseEnumeratedAPI(filepath.Join("testdata", name+".md")); err != nil {
+		t.Fatalf("ParseEnumeratedAPI(%s): %v", name, err)
+	}
+	return p
+}
+
+// findEndpoint looks up an endpoint by ID in every service of the catalog
+func findEndpoint(c *core.APICatalog, id string) (core.APIDefinition, bool) {
+	for _, service := range []map[string]core.APIDefinition{c.EAPI, c.CloudVision, c.EOSREST, c.Telemetry} {
+		if def, ok := service[id]; ok {
+			return def, true
+		}
+	}
+	return core.APIDefinition{}, false
+}
+
+func TestGolden(t *testing.T) {
+	results, err := CheckGolden("testdata", *update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Updated {
+			t.Logf("updated %s", r.Name)
+		} else if !r.OK {
+			t.Errorf("%s differs from its golden file; rerun with -update if the change is intended:\n%s", r.Name, r.Diff)
+		}
+	}
+}
+
+func TestServiceDetection(t *testing.T) {
+	tests := []struct {
+		excerpt string
+		id      string
+		service string
+	}{
+		// Under BasePath:/vRest every operation is EOS REST, /command-api included
+		{"service_classification", "ApiInstalls_POST__api-installs_install", "eos_rest"},
+		{"service_classification", "PortResources_GET__port-resources_resources_{name}", "eos_rest"},
+		{"service_classification", "PortResources_GET__ports_api_stats", "eos_rest"},
+		{"service_classification", "CommandApi_POST__command-api", "eos_rest"},
+		// Without a base path the operation path decides
+		{"unparsed_lines", "Inventory_GET__api_resources_inventory_v1_Devices", "cloudvision"},
+		{"unparsed_lines", "Eapi_POST__command-api", "eapi"},
+		{"unparsed_lines", "Telemetry_GET__telemetry_subscriptions", "telemetry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			def, ok := findEndpoint(parseExcerpt(t, tt.excerpt).GetCatalog(), tt.id)
+			if !ok {
+				t.Fatalf("%s not in the catalog", tt.id)
+			}
+			if def.Service != tt.service {
+				t.Errorf("service = %q, want %q", def.Service, tt.service)
+			}
+		})
+	}
+
+	p := NewAPIParser()
+	services := []struct {
+		basePath, path, want string
+	}{
+		{"/vRest", "/aaa-tacacs", "eos_rest"},
+		{"/vRest/", "/aaa-tacacs", "eos_rest"},
+		{"", "/command-api", "eapi"},
+		{"", "/api/resources/tag/v2/Tag", "cloudvision"},
+		{"", "/cvpservice/inventory/devices", "cloudvision"},
+		{"", "/telemetry/subscriptions", "telemetry"},
+		{"", "/streaming/paths", "telemetry"},
+		{"", "/interfaces", ""},
+	}
+	for _, tt := range services {
+		if got := p.determineService(tt.basePath, tt.path); got != tt.want {
+			t.Errorf("determineService(%q, %q) = %q, want %q", tt.basePath, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	catalog := parseExcerpt(t, "toc_and_methods").GetCatalog()
+	tests := []struct {
+		id            string
+		params        []string
+		operationID   string
+		requestModel  string
+		responseModel string
+	}{
+		{"AaaTacacs_POST__aaa-tacacs", nil, "createTacacs", "aaa-tacacs-create", "result-list"},
+		{"AaaTacacs_DELETE__aaa-tacacs_{name}", []string{"name"}, "deleteTacacsByName", "", "result-list"},
+		{"AaaTacacs_GET__aaa-tacacs_status_{name}", []string{"name"}, "", "", ""},
+		{"ApiInstalls_POST__api-installs_install", nil, "installApi", "api-install-install", "result-list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			def, ok := findEndpoint(catalog, tt.id)
+			if !ok {
+				t.Fatalf("%s not in the catalog", tt.id)
+			}
+			if !reflect.DeepEqual(def.Params, tt.params) {
+				t.Errorf("params = %v, want %v", def.Params, tt.params)
+			}
+			if def.OperationID != tt.operationID {
+				t.Errorf("operation ID = %q, want %q", def.OperationID, tt.operationID)
+			}
+			if def.RequestModel != tt.requestModel || def.ResponseModel != tt.responseModel {
+				t.Errorf("models = %q/%q, want %q/%q", def.RequestModel, def.ResponseModel, tt.requestModel, tt.responseModel)
+			}
+		})
+	}
+
+	// Query parameters are read from the details of an operation
+	p := NewAPIParser()
+	doc := "BasePath:/vRest\nVlans\n\n    get /vlans\n\nVlans\nUp\nget /vlans\n(showVlans)\n" +
+		"Query parameters\nlimit-output (optional)\nQuery Parameter\nshow-vlans (optional)\nReturn type\nvlan-show-response\n"
+	if err := p.parseContent(doc); err != nil {
+		t.Fatal(err)
+	}
+	def, ok := findEndpoint(p.GetCatalog(), "Vlans_GET__vlans")
+	if !ok {
+		t.Fatal("Vlans_GET__vlans not in the catalog")
+	}
+	if want := []string{"limit-output", "show-vlans"}; !reflect.DeepEqual(def.QueryParams, want) {
+		t.Errorf("query params = %v, want %v", def.QueryParams, want)
+	}
+	if def.OperationID != "showVlans" || def.ResponseModel != "vlan-show-response" {
+		t.Errorf("operation ID/response model = %q/%q", def.OperationID, def.ResponseModel)
+	}
+}
+
+func TestReportUnparsed(t *testing.T) {
+	tests := []struct {
+		excerpt   string
+		unparsed  map[string]int // reason -> lines
+		suspected int
+		endpoints int
+	}{
+		{"service_classification", map[string]int{}, 0, 4},
+		{"toc_and_methods", map[string]int{}, 1, 8},
+		{"unparsed_lines", map[string]int{
+			"operation outside a category":    1,
+			"malformed operation":             2,
+			"unknown service for /interfaces": 1,
+			"already listed under Eapi":       1,
+		}, 1, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.excerpt, func(t *testing.T) {
+			report := parseExcerpt(t, tt.excerpt).Report()
+			got := make(map[string]int)
+			for _, u := range report.Unparsed {
+				got[u.Reason]++
+			}
+			if !reflect.DeepEqual(got, tt.unparsed) {
+				t.Errorf("unparsed = %v, want %v", got, tt.unparsed)
+			}
+			if len(report.SuspectedHeaders) != tt.suspected {
+				t.Errorf("suspected headers = %d, want %d", len(report.SuspectedHeaders), tt.suspected)
+			}
+			if report.Endpoints != tt.endpoints {
+				t.Errorf("endpoints = %d, want %d", report.Endpoints, tt.endpoints)
+			}
+		})
+	}
+
+	// Six operation lines in unparsed_lines.md, three of them in the catalog
+	report := parseExcerpt(t, "unparsed_lines").Report()
+	if report.OperationLines != 6 {
+		t.Errorf("operation lines = %d, want 6", report.OperationLines)
+	}
+	if got, want := report.Coverage(), 3.0/8; got != want {
+		t.Errorf("coverage = %v, want %v", got, want)
+	}
+}
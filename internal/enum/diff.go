@@ -0,0 +1,151 @@
+package enum
+
+import (
+	"arista_engine/internal/core"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DiffCatalogs compares two catalog versions. Operations are matched by method and
+// path, so an operation moved to another category shows up as changed rather than as
+// removed and added.
+func DiffCatalogs(from, to *core.APICatalog) core.CatalogDiff {
+	diff := core.CatalogDiff{
+		From:            from.SourceVersion,
+		To:              to.SourceVersion,
+		Added:           []core.APIDefinition{},
+		Removed:         []core.APIDefinition{},
+		Changed:         []core.CatalogChange{},
+		AffectedQueries: []core.AffectedQuery{},
+	}
+
+	old := operations(from)
+	current := operations(to)
+	for key, def := range current {
+		prev, ok := old[key]
+		if !ok {
+			diff.Added = append(diff.Added, def)
+			continue
+		}
+		if fields := changedFields(prev, def); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, core.CatalogChange{Method: def.Method, Path: def.Path, Fields: fields, From: prev, To: def})
+		}
+	}
+	for key, def := range old {
+		if _, ok := current[key]; !ok {
+			diff.Removed = append(diff.Removed, def)
+		}
+	}
+
+	sortDefinitions(diff.Added)
+	sortDefinitions(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].Path != diff.Changed[j].Path {
+			return diff.Changed[i].Path < diff.Changed[j].Path
+		}
+		return diff.Changed[i].Method < diff.Changed[j].Method
+	})
+	return diff
+}
+
+// FindAffectedQueries returns the logged queries that call an operation the diff removed
+// or changed, so saved work can be reviewed before moving to the new version
+func FindAffectedQueries(diff core.CatalogDiff, records []core.APIQueryRecord) []core.AffectedQuery {
+	affected := []core.AffectedQuery{}
+	for _, record := range records {
+		reason := ""
+		for _, def := range diff.Removed {
+			if matchesOperation(def, record.Method, record.Path) {
+				reason = "operation removed in " + diff.To
+				break
+			}
+		}
+		if reason == "" {
+			for _, change := range diff.Changed {
+				if matchesOperation(change.From, record.Method, record.Path) {
+					reason = strings.Join(change.Fields, ", ") + " changed in " + diff.To
+					break
+				}
+			}
+		}
+		if reason != "" {
+			affected = append(affected, core.AffectedQuery{
+				QueryID:    record.ID,
+				EndpointID: record.EndpointID,
+				Method:     record.Method,
+				Path:       record.Path,
+				Reason:     reason,
+			})
+		}
+	}
+	return affected
+}
+
+// operations indexes every definition of a catalog by "METHOD path"
+func operations(c *core.APICatalog) map[string]core.APIDefinition {
+	ops := make(map[string]core.APIDefinition)
+	for _, group := range []map[string]core.APIDefinition{c.EAPI, c.CloudVision, c.EOSREST, c.Telemetry} {
+		for _, def := range group {
+			ops[def.Method+" "+def.Path] = def
+		}
+	}
+	return ops
+}
+
+// changedFields lists the fields of an operation that differ between two versions
+func changedFields(from, to core.APIDefinition) []string {
+	var fields []string
+	if !slices.Equal(from.Params, to.Params) {
+		fields = append(fields, "params")
+	}
+	if !slices.Equal(from.QueryParams, to.QueryParams) {
+		fields = append(fields, "queryParams")
+	}
+	if from.RequestModel != to.RequestModel {
+		fields = append(fields, "requestModel")
+	}
+	if from.ResponseModel != to.ResponseModel {
+		fields = append(fields, "responseModel")
+	}
+	if from.Category != to.Category {
+		fields = append(fields, "category")
+	}
+	if from.Service != to.Service {
+		fields = append(fields, "service")
+	}
+	return fields
+}
+
+// matchesOperation reports whether a logged request calls an operation. Path parameters
+// match any segment, and EOS REST requests may carry the /vRest base path.
+func matchesOperation(def core.APIDefinition, method, path string) bool {
+	if !strings.EqualFold(def.Method, method) {
+		return false
+	}
+	path, _, _ = strings.Cut(path, "?")
+	if def.Service == "eos_rest" {
+		path = strings.TrimPrefix(path, "/vRest")
+	}
+	want := strings.Split(strings.Trim(def.Path, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i, seg := range want {
+		if seg != got[i] && !pathParam.MatchString(seg) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortDefinitions orders definitions by path, then method
+func sortDefinitions(defs []core.APIDefinition) {
+	sort.Slice(defs, func(i, j int) bool {
+		if defs[i].Path != defs[j].Path {
+			return defs[i].Path < defs[j].Path
+		}
+		return defs[i].Method < defs[j].Method
+	})
+}
@@ -0,0 +1,115 @@
+package enum
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GoldenResult is the outcome of parsing one reference excerpt against its golden file
+type GoldenResult struct {
+	Name    string
+	OK      bool
+	Updated bool
+	Diff    string // first differing lines when not OK
+}
+
+// goldenOutput is what a golden file records: the catalog without its timestamp, and the report
+type goldenOutput struct {
+	EAPI        map[string]core.APIDefinition `json:"eapi"`
+	CloudVision map[string]core.APIDefinition `json:"cloudvision"`
+	EOSREST     map[string]core.APIDefinition `json:"eos_rest"`
+	Telemetry   map[string]core.APIDefinition `json:"telemetry"`
+	Report      *ParseReport                  `json:"report"`
+}
+
+// CheckGolden parses every <name>.md excerpt in dir and compares the catalog and report
+// with <name>.golden.json. With update set, golden files are rewritten instead.
+func CheckGolden(dir string, update bool) ([]GoldenResult, error) {
+	excerpts, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list excerpts: %w", err)
+	}
+	if len(excerpts) == 0 {
+		return nil, fmt.Errorf("no excerpts in %s", dir)
+	}
+
+	results := make([]GoldenResult, 0, len(excerpts))
+	for _, excerpt := range excerpts {
+		name := strings.TrimSuffix(filepath.Base(excerpt), ".md")
+		got, err := renderGolden(excerpt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		goldenPath := filepath.Join(dir, name+".golden.json")
+		if update {
+			if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+				return nil, fmt.Errorf("failed to write golden file: %w", err)
+			}
+			results = append(results, GoldenResult{Name: name, OK: true, Updated: true})
+			continue
+		}
+
+		want, err := os.ReadFile(goldenPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read golden file: %w", err)
+		}
+		result := GoldenResult{Name: name, OK: bytes.Equal(got, want)}
+		if !result.OK {
+			result.Diff = lineDiff(string(want), string(got), 10)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// renderGolden parses an excerpt and renders the golden file contents
+func renderGolden(path string) ([]byte, error) {
+	p := NewAPIParser()
+	if err := p.ParseEnumeratedAPI(path); err != nil {
+		return nil, err
+	}
+	c := p.GetCatalog()
+	out, err := json.MarshalIndent(goldenOutput{
+		EAPI:        c.EAPI,
+		CloudVision: c.CloudVision,
+		EOSREST:     c.EOSREST,
+		Telemetry:   c.Telemetry,
+		Report:      p.Report(),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	return append(out, '\n'), nil
+}
+
+// lineDiff lists up to max lines that differ between want and got
+func lineDiff(want, got string, max int) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	shown := 0
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&b, "line %d:\n  want: %s\n  got:  %s\n", i+1, w, g)
+		if shown++; shown == max {
+			b.WriteString("  ...\n")
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,116 @@
+package enum
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ParseReport describes how much of an API reference the parser understood
+type ParseReport struct {
+	Source           string         `json:"source"`
+	Lines            int            `json:"lines"`
+	SourceVersion    string         `json:"sourceVersion,omitempty"`
+	BasePath         string         `json:"basePath,omitempty"`
+	OperationLines   int            `json:"operationLines"` // lines recognized as operations, including repeats
+	Endpoints        int            `json:"endpoints"`      // distinct endpoints added to the catalog
+	Categories       int            `json:"categories"`
+	ByService        map[string]int `json:"byService"`
+	ByMethod         map[string]int `json:"byMethod"`
+	Unparsed         []ReportLine   `json:"unparsed"`
+	SuspectedHeaders []ReportLine   `json:"suspectedHeaders"`
+}
+
+// ReportLine is a line of the reference the parser could not use
+type ReportLine struct {
+	Line   int    `json:"line"`
+	Text   string `json:"text"`
+	Reason string `json:"reason"`
+}
+
+// newParseReport creates an empty report for a source
+func newParseReport(source string) *ParseReport {
+	return &ParseReport{
+		Source:           source,
+		ByService:        make(map[string]int),
+		ByMethod:         make(map[string]int),
+		Unparsed:         []ReportLine{},
+		SuspectedHeaders: []ReportLine{},
+	}
+}
+
+// count records an endpoint added to the catalog
+func (r *ParseReport) count(service, method string) {
+	r.Endpoints++
+	r.ByService[service]++
+	r.ByMethod[method]++
+}
+
+// unparsed records an operation line that did not make it into the catalog
+func (r *ParseReport) unparsed(line int, text, reason string) {
+	r.Unparsed = append(r.Unparsed, ReportLine{Line: line, Text: text, Reason: reason})
+}
+
+// suspect records a line that looks like a category header but starts no category
+func (r *ParseReport) suspect(line int, text, reason string) {
+	r.SuspectedHeaders = append(r.SuspectedHeaders, ReportLine{Line: line, Text: text, Reason: reason})
+}
+
+// Coverage returns the fraction of operation-like lines that were understood
+func (r *ParseReport) Coverage() float64 {
+	malformed := 0
+	for _, u := range r.Unparsed {
+		if u.Reason == "malformed operation" {
+			malformed++
+		}
+	}
+	total := r.OperationLines + malformed
+	if total == 0 {
+		return 0
+	}
+	return float64(total-len(r.Unparsed)) / float64(total)
+}
+
+// Print writes a summary of the report; verbose adds every unparsed line and suspected header
+func (r *ParseReport) Print(w io.Writer, verbose bool) {
+	fmt.Fprintf(w, "source:             %s\n", r.Source)
+	fmt.Fprintf(w, "source version:     %s\n", r.SourceVersion)
+	fmt.Fprintf(w, "base path:          %s\n", r.BasePath)
+	fmt.Fprintf(w, "lines:              %d\n", r.Lines)
+	fmt.Fprintf(w, "operation lines:    %d\n", r.OperationLines)
+	fmt.Fprintf(w, "endpoints:          %d in %d categories\n", r.Endpoints, r.Categories)
+	fmt.Fprintf(w, "coverage:           %.1f%%\n", 100*r.Coverage())
+	fmt.Fprintf(w, "by service:         %s\n", formatCounts(r.ByService))
+	fmt.Fprintf(w, "by method:          %s\n", formatCounts(r.ByMethod))
+	fmt.Fprintf(w, "unparsed lines:     %d\n", len(r.Unparsed))
+	fmt.Fprintf(w, "suspected headers:  %d\n", len(r.SuspectedHeaders))
+	if !verbose {
+		return
+	}
+	for _, u := range r.Unparsed {
+		fmt.Fprintf(w, "  unparsed %6d: %-50s %s\n", u.Line, u.Text, u.Reason)
+	}
+	for _, h := range r.SuspectedHeaders {
+		fmt.Fprintf(w, "  header   %6d: %-50s %s\n", h.Line, h.Text, h.Reason)
+	}
+}
+
+// formatCounts prints counts in key order, e.g. "GET=3 POST=1"
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := ""
+	for i, k := range keys {
+		if i > 0 {
+			out += " "
+		}
+		out += fmt.Sprintf("%s=%d", k, counts[k])
+	}
+	if out == "" {
+		return "-"
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+// Package eos provides typed models for the JSON output of common EOS show commands,
+// keyed by command and model revision, so consumers do not navigate untyped maps.
+package eos
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Model describes the typed output of one command at one revision
+type Model struct {
+	Command  string
+	Revision int
+	new      func() any
+}
+
+// New returns an empty value of the model's type
+func (m Model) New() any {
+	return m.new()
+}
+
+// modelKey identifies a registered model
+type modelKey struct {
+	command  string
+	revision int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[modelKey]Model)
+)
+
+// Register adds the model for a command and revision. newFn returns a pointer to an
+// empty value of the output type.
+func Register(command string, revision int, newFn func() any) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	cmd := NormalizeCommand(command)
+	registry[modelKey{cmd, revision}] = Model{Command: cmd, Revision: revision, new: newFn}
+}
+
+// Lookup returns the model of a command. Revision 0 selects the newest registered
+// revision, matching what eAPI returns for version "latest".
+func Lookup(command string, revision int) (Model, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	cmd := NormalizeCommand(command)
+	if revision > 0 {
+		m, ok := registry[modelKey{cmd, revision}]
+		return m, ok
+	}
+	var best Model
+	found := false
+	for key, m := range registry {
+		if key.command == cmd && (!found || key.revision > best.Revision) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
+
+// Models lists the registered models by command and revision
+func Models() []Model {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	models := make([]Model, 0, len(registry))
+	for _, m := range registry {
+		models = append(models, m)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Command != models[j].Command {
+			return models[i].Command < models[j].Command
+		}
+		return models[i].Revision < models[j].Revision
+	})
+	return models
+}
+
+// NormalizeCommand lowercases a command, collapses whitespace and drops a trailing
+// "| json", so "Show  Version | json" and "show version" share a model
+func NormalizeCommand(command string) string {
+	cmd := strings.Join(strings.Fields(strings.ToLower(command)), " ")
+	if before, ok := strings.CutSuffix(cmd, "| json"); ok {
+		cmd = strings.TrimSpace(before)
+	}
+	return cmd
+}
+
+// Decode converts a command output, raw JSON or an already decoded value such as an
+// eAPI result entry, into the registered model. It returns a pointer to the typed output.
+func Decode(command string, revision int, output any) (any, error) {
+	m, ok := Lookup(command, revision)
+	if !ok {
+		return nil, fmt.Errorf("no model for %q revision %d", command, revision)
+	}
+	v := m.New()
+	if err := DecodeInto(output, v); err != nil {
+		return nil, fmt.Errorf("failed to decode %q: %w", command, err)
+	}
+	return v, nil
+}
+
+// As decodes a command output into T, e.g. eos.As[eos.ShowVersion](result)
+func As[T any](output any) (*T, error) {
+	v := new(T)
+	if err := DecodeInto(output, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// DecodeInto decodes a command output into v
+func DecodeInto(output any, v any) error {
+	var raw []byte
+	switch o := output.(type) {
+	case []byte:
+		raw = o
+	case json.RawMessage:
+		raw = o
+	case string:
+		raw = []byte(o)
+	default:
+		var err error
+		if raw, err = json.Marshal(output); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// ASN is a BGP AS number. EOS reports it as a number in older releases and as a
+// string, possibly in asdot notation, in newer ones.
+type ASN string
+
+// UnmarshalJSON accepts a number or a string
+func (a *ASN) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		*a = ASN(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("invalid AS number: %w", err)
+	}
+	*a = ASN(n.String())
+	return nil
+}
@@ -0,0 +1,309 @@
+package eos
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixtureModel decodes the newest fixture of a command into its registered model
+func fixtureModel(t *testing.T, command string) any {
+	t.Helper()
+	raw, ok := Fixture(command, 0)
+	if !ok {
+		t.Fatalf("no fixture for %q", command)
+	}
+	v, err := Decode(command, 0, raw)
+	if err != nil {
+		t.Fatalf("Decode(%q): %v", command, err)
+	}
+	return v
+}
+
+func TestFixtures(t *testing.T) {
+	tests := []struct {
+		command string
+		check   func(t *testing.T, v any)
+	}{
+		{"show version", func(t *testing.T, v any) {
+			ver := v.(*ShowVersion)
+			if ver.Version != "4.30.1F" || ver.ModelName != "DCS-7050SX3-48YC8" {
+				t.Errorf("version/model = %q/%q", ver.Version, ver.ModelName)
+			}
+			if ver.SerialNumber != "JPE20481234" || ver.SystemMacAddress != "fc:bd:67:0a:1b:2c" {
+				t.Errorf("serial/mac = %q/%q", ver.SerialNumber, ver.SystemMacAddress)
+			}
+			if ver.MemTotal != 8098984 || ver.Uptime != 1209600.31 {
+				t.Errorf("memTotal/uptime = %d/%v", ver.MemTotal, ver.Uptime)
+			}
+			if ver.IsVirtual() {
+				t.Error("hardware switch reported as virtual")
+			}
+			if got := ver.BootedAt().Unix(); got != 1696934400 {
+				t.Errorf("BootedAt = %d", got)
+			}
+		}},
+		{"show environment temperature", func(t *testing.T, v any) {
+			temp := v.(*ShowEnvironmentTemperature)
+			if temp.SystemStatus != "temperatureOk" {
+				t.Errorf("systemStatus = %q", temp.SystemStatus)
+			}
+			sensors := temp.Sensors()
+			if len(sensors) != 3 || sensors[1].Name != "TempSensor2" || sensors[1].CurrentTemperature != 61 || sensors[2].Name != "TempSensorP1/1" {
+				t.Errorf("sensors = %+v", sensors)
+			}
+		}},
+		{"show environment cooling", func(t *testing.T, v any) {
+			cooling := v.(*ShowEnvironmentCooling)
+			if cooling.SystemStatus != "coolingOk" || len(cooling.FanTraySlots) != 2 || len(cooling.PowerSupplySlots) != 1 {
+				t.Fatalf("cooling = %+v", cooling)
+			}
+			if fan := cooling.FanTraySlots[1].Fans[0]; fan.Label != "2/1" || fan.ActualSpeed != 41 || fan.ConfiguredSpeed != 40 {
+				t.Errorf("fan 2/1 = %+v", fan)
+			}
+		}},
+		{"show environment power", func(t *testing.T, v any) {
+			power := v.(*ShowEnvironmentPower)
+			if psu := power.PowerSupplies["1"]; psu.State != "ok" || psu.OutputPower != 149.2 || psu.Capacity != 500 {
+				t.Errorf("power supply 1 = %+v", psu)
+			}
+			if psu := power.PowerSupplies["2"]; psu.State != "powerLoss" {
+				t.Errorf("power supply 2 state = %q", psu.State)
+			}
+		}},
+		{"show interfaces", func(t *testing.T, v any) {
+			ifaces := v.(*ShowInterfaces)
+			if names := ifaces.Names(); !reflect.DeepEqual(names, []string{"Ethernet1", "Ethernet2", "Management1"}) {
+				t.Errorf("names = %v", names)
+			}
+			et1 := ifaces.Interfaces["Ethernet1"]
+			if !et1.Up() || et1.MTU != 9214 || et1.Bandwidth != 25000000000 {
+				t.Errorf("Ethernet1 = up %v, mtu %d, bandwidth %d", et1.Up(), et1.MTU, et1.Bandwidth)
+			}
+			if len(et1.InterfaceAddress) != 1 || et1.InterfaceAddress[0].PrimaryIP != (IPAddress{Address: "10.0.1.1", MaskLen: 31}) {
+				t.Errorf("Ethernet1 address = %+v", et1.InterfaceAddress)
+			}
+			c := et1.InterfaceCounters
+			if c == nil {
+				t.Fatal("Ethernet1 has no counters")
+			}
+			if c.InOctets != 928374651 || c.OutOctets != 837261542 || c.InUcastPkts != 1203948 || c.LinkStatusChanges != 3 || c.TotalInErrors != 0 {
+				t.Errorf("Ethernet1 counters = %+v", c)
+			}
+			if r := et1.InterfaceStatistics; r == nil || r.UpdateInterval != 300 || r.InBitsRate != 183422.7 {
+				t.Errorf("Ethernet1 rates = %+v", r)
+			}
+			if et2 := ifaces.Interfaces["Ethernet2"]; et2.Up() || et2.InterfaceCounters != nil {
+				t.Errorf("Ethernet2 = %+v", et2)
+			}
+		}},
+		{"show interfaces status", func(t *testing.T, v any) {
+			status := v.(*ShowInterfacesStatus)
+			names := status.Names()
+			if len(names) < 4 || names[0] != "Ethernet1" || names[1] != "Ethernet2" || names[2] != "Ethernet10" {
+				t.Errorf("names = %v", names)
+			}
+			if et10 := status.InterfaceStatuses["Ethernet10"]; et10.LinkStatus != "connected" || et10.VlanInformation.VlanExplanation != "in Po10" {
+				t.Errorf("Ethernet10 = %+v", et10)
+			}
+			if et2 := status.InterfaceStatuses["Ethernet2"]; et2.LinkStatus != "notconnect" || et2.VlanInformation.VlanID != 1 {
+				t.Errorf("Ethernet2 = %+v", et2)
+			}
+		}},
+		{"show vlan", func(t *testing.T, v any) {
+			vlans := v.(*ShowVlan)
+			if len(vlans.Vlans) != 3 {
+				t.Fatalf("got %d VLANs, want 3", len(vlans.Vlans))
+			}
+			if vlan := vlans.Vlans["110"]; vlan.Name != "Tenant_A_OP_Zone_1" || vlan.Status != "active" || len(vlan.Interfaces) != 2 {
+				t.Errorf("VLAN 110 = %+v", vlan)
+			}
+		}},
+		{"show ip bgp summary", func(t *testing.T, v any) {
+			bgp := v.(*ShowIPBGPSummary)
+			vrf, ok := bgp.VRFs["default"]
+			if !ok || vrf.RouterID != "192.0.255.3" || vrf.ASN != "65101" {
+				t.Fatalf("default VRF = %+v", vrf)
+			}
+			up := vrf.Peers["10.0.1.0"]
+			if !up.Established() || up.PeerState != "Established" || up.ASN != "65001" || up.PrefixAccepted != 12 || up.MsgReceived != 24187 {
+				t.Errorf("peer 10.0.1.0 = %+v", up)
+			}
+			if got := up.LastStateChange().Unix(); got != 1696934530 {
+				t.Errorf("peer 10.0.1.0 LastStateChange = %d", got)
+			}
+			if down := vrf.Peers["10.0.1.2"]; down.Established() || down.PeerState != "Active" || down.PrefixReceived != 0 {
+				t.Errorf("peer 10.0.1.2 = %+v", down)
+			}
+		}},
+		{"show ip route", func(t *testing.T, v any) {
+			routes := v.(*ShowIPRoute).VRFs["default"].Routes
+			if len(routes) != 3 {
+				t.Fatalf("got %d routes, want 3", len(routes))
+			}
+			bgp := routes["192.0.255.1/32"]
+			if bgp.RouteType != "eBGP" || bgp.Preference != 200 || !reflect.DeepEqual(bgp.Vias, []RouteVia{{NexthopAddr: "10.0.1.0", Interface: "Ethernet1"}}) {
+				t.Errorf("192.0.255.1/32 = %+v", bgp)
+			}
+			if connected := routes["10.0.1.0/31"]; !connected.DirectlyConnected || connected.RouteType != "connected" {
+				t.Errorf("10.0.1.0/31 = %+v", connected)
+			}
+		}},
+		{"show lldp neighbors", func(t *testing.T, v any) {
+			lldp := v.(*ShowLLDPNeighbors)
+			if len(lldp.LLDPNeighbors) != 3 || lldp.TablesInserts != 3 {
+				t.Fatalf("lldp = %+v", lldp)
+			}
+			if n := lldp.LLDPNeighbors[0]; n != (LLDPNeighbor{Port: "Ethernet1", NeighborDevice: "spine1.lab.local", NeighborPort: "Ethernet1", TTL: 120}) {
+				t.Errorf("first neighbor = %+v", n)
+			}
+		}},
+		{"show lldp neighbors detail", func(t *testing.T, v any) {
+			detail := v.(*ShowLLDPNeighborsDetail)
+			infos := detail.LLDPNeighbors["Ethernet1"].LLDPNeighborInfo
+			if len(infos) != 1 {
+				t.Fatalf("Ethernet1 neighbors = %+v", infos)
+			}
+			n := infos[0]
+			if n.SystemName != "spine1.lab.local" || n.ChassisID != "001c.7301.0001" || n.NeighborInterfaceInfo.Port() != "Ethernet1" {
+				t.Errorf("neighbor = %+v, port %q", n, n.NeighborInterfaceInfo.Port())
+			}
+			if len(n.ManagementAddresses) != 1 || n.ManagementAddresses[0].Address != "192.168.0.1" {
+				t.Errorf("management addresses = %+v", n.ManagementAddresses)
+			}
+			if len(detail.LLDPNeighbors["Ethernet2"].LLDPNeighborInfo) != 0 {
+				t.Error("Ethernet2 should have no neighbors")
+			}
+		}},
+		{"show mlag", func(t *testing.T, v any) {
+			mlag := v.(*ShowMlag)
+			if !mlag.Healthy() || mlag.DomainID != "leaf_pair_1" || mlag.PeerLink != "Port-Channel10" || mlag.PeerAddress != "10.255.252.1" {
+				t.Errorf("mlag = %+v", mlag)
+			}
+			if mlag.MlagPorts["Active-full"] != 2 || mlag.ReloadDelay != 300 {
+				t.Errorf("ports/reload delay = %v/%d", mlag.MlagPorts, mlag.ReloadDelay)
+			}
+		}},
+	}
+
+	tested := make(map[string]bool)
+	for _, tt := range tests {
+		tested[tt.command] = true
+		t.Run(tt.command, func(t *testing.T) {
+			tt.check(t, fixtureModel(t, tt.command))
+		})
+	}
+
+	// Every registered model has a fixture and a test above
+	for _, m := range Models() {
+		if _, ok := Fixture(m.Command, m.Revision); !ok {
+			t.Errorf("%s revision %d: no fixture", m.Command, m.Revision)
+		}
+		if !tested[m.Command] {
+			t.Errorf("%s: no fixture test", m.Command)
+		}
+	}
+}
+
+func TestFixturesRoundTrip(t *testing.T) {
+	for _, m := range Models() {
+		t.Run(m.Command, func(t *testing.T) {
+			raw, ok := Fixture(m.Command, m.Revision)
+			if !ok {
+				t.Skip("no fixture")
+			}
+			decoded := m.New()
+			if err := json.Unmarshal(raw, decoded); err != nil {
+				t.Fatal(err)
+			}
+			if reflect.ValueOf(decoded).Elem().IsZero() {
+				t.Fatal("fixture decoded to an empty model")
+			}
+			encoded, err := json.Marshal(decoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again := m.New()
+			if err := json.Unmarshal(encoded, again); err != nil || !reflect.DeepEqual(decoded, again) {
+				t.Errorf("model does not round-trip: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeInputs(t *testing.T) {
+	raw, _ := Fixture("show version", 0)
+	var generic map[string]any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raw JSON, strings and already decoded eAPI results all decode the same way
+	for name, output := range map[string]any{
+		"bytes":   raw,
+		"raw":     json.RawMessage(raw),
+		"string":  string(raw),
+		"decoded": generic,
+	} {
+		v, err := As[ShowVersion](output)
+		if err != nil || v.Version != "4.30.1F" {
+			t.Errorf("%s: As = %+v, %v", name, v, err)
+		}
+	}
+
+	if _, err := Decode("show | json", 0, raw); err == nil {
+		t.Error("Decode of an unknown command: expected an error")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, cmd := range []string{"show version", "Show  Version", "show version | json", " SHOW VERSION |  json "} {
+		m, ok := Lookup(cmd, 0)
+		if !ok || m.Command != "show version" || m.Revision != 1 {
+			t.Errorf("Lookup(%q) = %+v, %v", cmd, m, ok)
+		}
+	}
+	if _, ok := Lookup("show version", 7); ok {
+		t.Error("Lookup of an unregistered revision succeeded")
+	}
+}
+
+func TestASN(t *testing.T) {
+	tests := map[string]ASN{
+		`65001`:      "65001",
+		`"65001"`:    "65001",
+		`"65000.10"`: "65000.10",
+		`4200000000`: "4200000000",
+	}
+	for in, want := range tests {
+		var got ASN
+		if err := json.Unmarshal([]byte(in), &got); err != nil || got != want {
+			t.Errorf("ASN %s = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	var bad ASN
+	if err := json.Unmarshal([]byte(`true`), &bad); err == nil {
+		t.Error("ASN true: expected an error")
+	}
+}
+
+func TestInterfaceOrder(t *testing.T) {
+	m := map[string]int{}
+	for _, name := range []string{"Ethernet10", "Port-Channel10", "Ethernet1/10", "Ethernet2", "Ethernet1/2", "Management1", "Loopback0"} {
+		m[name] = 0
+	}
+	want := []string{"Ethernet1/2", "Ethernet1/10", "Ethernet2", "Ethernet10", "Loopback0", "Management1", "Port-Channel10"}
+	if got := sortedInterfaces(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedInterfaces = %v, want %v", got, want)
+	}
+}
+
+func TestUnixTime(t *testing.T) {
+	if !unixTime(0).IsZero() {
+		t.Error("unixTime(0) should be the zero time")
+	}
+	if got := unixTime(1696934400.5); !got.Equal(time.Unix(1696934400, 5e8)) {
+		t.Errorf("unixTime = %v", got)
+	}
+}
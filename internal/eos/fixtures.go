@@ -0,0 +1,58 @@
+package eos
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// fixtures holds captured outputs of every modelled command. Files are named after the
+// command with spaces replaced by underscores, e.g. show_ip_route.json; an ".rN" suffix
+// before the extension marks an output of model revision N.
+//
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// fixtureName returns the fixture file of a command and revision
+func fixtureName(command string, revision int) string {
+	name := strings.ReplaceAll(NormalizeCommand(command), " ", "_")
+	if revision > 1 {
+		name += ".r" + strconv.Itoa(revision)
+	}
+	return "fixtures/" + name + ".json"
+}
+
+// Fixture returns the captured JSON output of a command. Revision 0 selects the newest
+// registered revision.
+func Fixture(command string, revision int) ([]byte, bool) {
+	if revision == 0 {
+		if m, ok := Lookup(command, 0); ok {
+			revision = m.Revision
+		}
+	}
+	raw, err := fixtures.ReadFile(fixtureName(command, revision))
+	if err != nil && revision <= 1 {
+		raw, err = fixtures.ReadFile(fixtureName(command, 1))
+	}
+	return raw, err == nil
+}
+
+// FixtureCommands lists the commands that have a fixture
+func FixtureCommands() []string {
+	entries, _ := fs.ReadDir(fixtures, "fixtures")
+	seen := make(map[string]bool)
+	var cmds []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if i := strings.Index(name, ".r"); i >= 0 {
+			name = name[:i]
+		}
+		cmd := strings.ReplaceAll(name, "_", " ")
+		if !seen[cmd] {
+			seen[cmd] = true
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
@@ -0,0 +1,196 @@
+package eos
+
+import (
+	"sort"
+	"time"
+)
+
+func init() {
+	Register("show interfaces", 1, func() any { return new(ShowInterfaces) })
+	Register("show interfaces status", 1, func() any { return new(ShowInterfacesStatus) })
+	Register("show vlan", 1, func() any { return new(ShowVlan) })
+}
+
+// ShowInterfaces is the output of "show interfaces", keyed by interface name
+type ShowInterfaces struct {
+	Interfaces map[string]Interface `json:"interfaces"`
+}
+
+// Interface is the detail of one interface
+type Interface struct {
+	Name                      string              `json:"name"`
+	Description               string              `json:"description"`
+	Hardware                  string              `json:"hardware"`
+	ForwardingModel           string              `json:"forwardingModel"`    // routed, bridged, dataLink
+	InterfaceStatus           string              `json:"interfaceStatus"`    // connected, notconnect, disabled, ...
+	LineProtocolStatus        string              `json:"lineProtocolStatus"` // up, down, lowerLayerDown, ...
+	PhysicalAddress           string              `json:"physicalAddress"`
+	BurnedInAddress           string              `json:"burnedInAddress"`
+	Bandwidth                 int64               `json:"bandwidth"` // bits per second
+	MTU                       int                 `json:"mtu"`
+	L2MRU                     int                 `json:"l2Mru"`
+	Duplex                    string              `json:"duplex"`
+	AutoNegotiate             string              `json:"autoNegotiate"`
+	LastStatusChangeTimestamp float64             `json:"lastStatusChangeTimestamp"`
+	InterfaceAddress          []InterfaceAddress  `json:"interfaceAddress"`
+	InterfaceCounters         *InterfaceCounters  `json:"interfaceCounters,omitempty"`
+	InterfaceStatistics       *InterfaceRates     `json:"interfaceStatistics,omitempty"`
+	MemberInterfaces          map[string]struct{} `json:"memberInterfaces,omitempty"` // port-channels
+}
+
+// InterfaceAddress is the IPv4 addressing of an interface
+type InterfaceAddress struct {
+	PrimaryIP        IPAddress            `json:"primaryIp"`
+	SecondaryIPs     map[string]IPAddress `json:"secondaryIps"`
+	BroadcastAddress string               `json:"broadcastAddress"`
+	DHCP             bool                 `json:"dhcp"`
+}
+
+// IPAddress is an address with its prefix length
+type IPAddress struct {
+	Address string `json:"address"`
+	MaskLen int    `json:"maskLen"`
+}
+
+// InterfaceCounters are the packet and error counters of an interface
+type InterfaceCounters struct {
+	InOctets           int64   `json:"inOctets"`
+	InUcastPkts        int64   `json:"inUcastPkts"`
+	InMulticastPkts    int64   `json:"inMulticastPkts"`
+	InBroadcastPkts    int64   `json:"inBroadcastPkts"`
+	InDiscards         int64   `json:"inDiscards"`
+	InTotalPkts        int64   `json:"inTotalPkts"`
+	OutOctets          int64   `json:"outOctets"`
+	OutUcastPkts       int64   `json:"outUcastPkts"`
+	OutMulticastPkts   int64   `json:"outMulticastPkts"`
+	OutBroadcastPkts   int64   `json:"outBroadcastPkts"`
+	OutDiscards        int64   `json:"outDiscards"`
+	OutTotalPkts       int64   `json:"outTotalPkts"`
+	TotalInErrors      int64   `json:"totalInErrors"`
+	TotalOutErrors     int64   `json:"totalOutErrors"`
+	LinkStatusChanges  int64   `json:"linkStatusChanges"`
+	CounterRefreshTime float64 `json:"counterRefreshTime"`
+}
+
+// InterfaceRates are the load rates of an interface over the update interval
+type InterfaceRates struct {
+	UpdateInterval float64 `json:"updateInterval"` // seconds
+	InBitsRate     float64 `json:"inBitsRate"`
+	InPktsRate     float64 `json:"inPktsRate"`
+	OutBitsRate    float64 `json:"outBitsRate"`
+	OutPktsRate    float64 `json:"outPktsRate"`
+}
+
+// Up reports whether the line protocol of the interface is up
+func (i Interface) Up() bool {
+	return i.LineProtocolStatus == "up"
+}
+
+// LastStatusChange returns when the interface last changed state
+func (i Interface) LastStatusChange() time.Time {
+	return unixTime(i.LastStatusChangeTimestamp)
+}
+
+// Names returns the interface names in EOS order, e.g. Ethernet2 before Ethernet10
+func (s *ShowInterfaces) Names() []string {
+	return sortedInterfaces(s.Interfaces)
+}
+
+// ShowInterfacesStatus is the output of "show interfaces status", keyed by interface name
+type ShowInterfacesStatus struct {
+	InterfaceStatuses map[string]InterfaceStatus `json:"interfaceStatuses"`
+}
+
+// InterfaceStatus is the summary status of one interface
+type InterfaceStatus struct {
+	Description         string          `json:"description"`
+	LinkStatus          string          `json:"linkStatus"` // connected, notconnect, disabled, errdisabled
+	LineProtocolStatus  string          `json:"lineProtocolStatus"`
+	Bandwidth           int64           `json:"bandwidth"` // bits per second
+	InterfaceType       string          `json:"interfaceType"`
+	Duplex              string          `json:"duplex"`
+	AutoNegotiateActive bool            `json:"autoNegotiateActive"`
+	VlanInformation     VlanInformation `json:"vlanInformation"`
+}
+
+// VlanInformation is the switching mode of an interface
+type VlanInformation struct {
+	InterfaceMode            string `json:"interfaceMode"` // routed, bridged, trunk, ...
+	InterfaceForwardingModel string `json:"interfaceForwardingModel"`
+	VlanID                   int    `json:"vlanId,omitempty"`
+	VlanExplanation          string `json:"vlanExplanation,omitempty"` // e.g. "in Po10"
+}
+
+// Names returns the interface names in EOS order
+func (s *ShowInterfacesStatus) Names() []string {
+	return sortedInterfaces(s.InterfaceStatuses)
+}
+
+// ShowVlan is the output of "show vlan", keyed by VLAN ID
+type ShowVlan struct {
+	Vlans        map[string]Vlan `json:"vlans"`
+	SourceDetail string          `json:"sourceDetail"`
+}
+
+// Vlan is one VLAN and its member interfaces
+type Vlan struct {
+	Name       string                   `json:"name"`
+	Status     string                   `json:"status"` // active, suspended, ...
+	Dynamic    bool                     `json:"dynamic"`
+	Interfaces map[string]VlanInterface `json:"interfaces"`
+}
+
+// VlanInterface is a VLAN member
+type VlanInterface struct {
+	PrivatePromoted bool `json:"privatePromoted"`
+}
+
+// sortedInterfaces returns map keys ordered by interface type, then numerically by
+// each number in the name, so Ethernet1/2 sorts before Ethernet1/10
+func sortedInterfaces[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return interfaceLess(names[i], names[j])
+	})
+	return names
+}
+
+// interfaceLess compares interface names with their numbers compared as integers
+func interfaceLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := isDigit(a[0]), isDigit(b[0])
+		if da && db {
+			na, ra := leadingNumber(a)
+			nb, rb := leadingNumber(b)
+			if na != nb {
+				return na < nb
+			}
+			a, b = ra, rb
+			continue
+		}
+		if a[0] != b[0] {
+			return a[0] < b[0]
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) < len(b)
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// leadingNumber splits the number at the start of s from the rest
+func leadingNumber(s string) (int, string) {
+	n := 0
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		n = n*10 + int(s[i]-'0')
+		i++
+	}
+	return n, s[i:]
+}
@@ -0,0 +1,171 @@
+package eos
+
+import "time"
+
+func init() {
+	Register("show ip bgp summary", 1, func() any { return new(ShowIPBGPSummary) })
+	Register("show ip route", 1, func() any { return new(ShowIPRoute) })
+	Register("show lldp neighbors", 1, func() any { return new(ShowLLDPNeighbors) })
+	Register("show lldp neighbors detail", 1, func() any { return new(ShowLLDPNeighborsDetail) })
+	Register("show mlag", 1, func() any { return new(ShowMlag) })
+}
+
+// ShowIPBGPSummary is the output of "show ip bgp summary", keyed by VRF
+type ShowIPBGPSummary struct {
+	VRFs map[string]BGPSummaryVRF `json:"vrfs"`
+}
+
+// BGPSummaryVRF is the BGP summary of one VRF, with peers keyed by address
+type BGPSummaryVRF struct {
+	RouterID string             `json:"routerId"`
+	ASN      ASN                `json:"asn"`
+	Peers    map[string]BGPPeer `json:"peers"`
+}
+
+// BGPPeer is one BGP neighbor
+type BGPPeer struct {
+	Description      string  `json:"description"`
+	ASN              ASN     `json:"asn"`
+	Version          int     `json:"version"`
+	PeerState        string  `json:"peerState"`  // Established, Active, Idle, ...
+	UpDownTime       float64 `json:"upDownTime"` // Unix timestamp of the last state change
+	MsgReceived      int64   `json:"msgReceived"`
+	MsgSent          int64   `json:"msgSent"`
+	InMsgQueue       int     `json:"inMsgQueue"`
+	OutMsgQueue      int     `json:"outMsgQueue"`
+	PrefixReceived   int     `json:"prefixReceived"`
+	PrefixAccepted   int     `json:"prefixAccepted"`
+	UnderMaintenance bool    `json:"underMaintenance"`
+}
+
+// Established reports whether the session is up
+func (p BGPPeer) Established() bool {
+	return p.PeerState == "Established"
+}
+
+// LastStateChange returns when the session last went up or down
+func (p BGPPeer) LastStateChange() time.Time {
+	return unixTime(p.UpDownTime)
+}
+
+// ShowIPRoute is the output of "show ip route", keyed by VRF
+type ShowIPRoute struct {
+	VRFs map[string]RouteVRF `json:"vrfs"`
+}
+
+// RouteVRF is the routing table of one VRF, keyed by prefix
+type RouteVRF struct {
+	RoutingDisabled           bool             `json:"routingDisabled"`
+	AllRoutesProgrammedHW     bool             `json:"allRoutesProgrammedHardware"`
+	AllRoutesProgrammedKernel bool             `json:"allRoutesProgrammedKernel"`
+	DefaultRouteState         string           `json:"defaultRouteState"`
+	Routes                    map[string]Route `json:"routes"`
+}
+
+// Route is one route with its next hops
+type Route struct {
+	RouteType          string     `json:"routeType"`   // connected, static, eBGP, iBGP, ospfIntraArea, ...
+	RouteAction        string     `json:"routeAction"` // forward, drop, ...
+	Preference         int        `json:"preference"`
+	Metric             int        `json:"metric"`
+	DirectlyConnected  bool       `json:"directlyConnected"`
+	HardwareProgrammed bool       `json:"hardwareProgrammed"`
+	KernelProgrammed   bool       `json:"kernelProgrammed"`
+	RouteLeaked        bool       `json:"routeLeaked"`
+	Vias               []RouteVia `json:"vias"`
+}
+
+// RouteVia is a next hop of a route
+type RouteVia struct {
+	NexthopAddr string `json:"nexthopAddr,omitempty"`
+	Interface   string `json:"interface,omitempty"`
+}
+
+// ShowLLDPNeighbors is the output of "show lldp neighbors"
+type ShowLLDPNeighbors struct {
+	TablesLastChangeTime float64        `json:"tablesLastChangeTime"`
+	TablesAgeOuts        int            `json:"tablesAgeOuts"`
+	TablesInserts        int            `json:"tablesInserts"`
+	TablesDeletes        int            `json:"tablesDeletes"`
+	TablesDrops          int            `json:"tablesDrops"`
+	LLDPNeighbors        []LLDPNeighbor `json:"lldpNeighbors"`
+}
+
+// LLDPNeighbor is one neighbor seen on a local port
+type LLDPNeighbor struct {
+	Port           string `json:"port"`
+	NeighborDevice string `json:"neighborDevice"`
+	NeighborPort   string `json:"neighborPort"`
+	TTL            int    `json:"ttl"`
+}
+
+// ShowLLDPNeighborsDetail is the output of "show lldp neighbors detail", keyed by local port
+type ShowLLDPNeighborsDetail struct {
+	LLDPNeighbors map[string]LLDPPortNeighbors `json:"lldpNeighbors"`
+}
+
+// LLDPPortNeighbors holds the neighbors of one local port
+type LLDPPortNeighbors struct {
+	LLDPNeighborInfo []LLDPNeighborInfo `json:"lldpNeighborInfo"`
+}
+
+// LLDPNeighborInfo is the detail a neighbor advertises
+type LLDPNeighborInfo struct {
+	ChassisIDType         string                `json:"chassisIdType"`
+	ChassisID             string                `json:"chassisId"`
+	SystemName            string                `json:"systemName"`
+	SystemDescription     string                `json:"systemDescription"`
+	TTL                   int                   `json:"ttl"`
+	ManagementAddresses   []LLDPManagementAddr  `json:"managementAddresses"`
+	NeighborInterfaceInfo LLDPNeighborInterface `json:"neighborInterfaceInfo"`
+}
+
+// LLDPManagementAddr is a management address advertised by a neighbor
+type LLDPManagementAddr struct {
+	AddressType string `json:"addressType"`
+	Address     string `json:"address"`
+}
+
+// LLDPNeighborInterface identifies the neighbor's port
+type LLDPNeighborInterface struct {
+	InterfaceIDType      string `json:"interfaceIdType"`
+	InterfaceID          string `json:"interfaceId"`
+	InterfaceIDV2        string `json:"interfaceId_v2"`
+	InterfaceDescription string `json:"interfaceDescription"`
+}
+
+// Port returns the neighbor's port name; older releases only report it quoted in interfaceId
+func (n LLDPNeighborInterface) Port() string {
+	if n.InterfaceIDV2 != "" {
+		return n.InterfaceIDV2
+	}
+	id := n.InterfaceID
+	if len(id) >= 2 && id[0] == '"' && id[len(id)-1] == '"' {
+		return id[1 : len(id)-1]
+	}
+	return id
+}
+
+// ShowMlag is the output of "show mlag"
+type ShowMlag struct {
+	DomainID                  string         `json:"domainId"`
+	State                     string         `json:"state"`        // active, inactive, disabled
+	NegStatus                 string         `json:"negStatus"`    // connected, connecting, ...
+	ConfigSanity              string         `json:"configSanity"` // consistent, inconsistent
+	LocalIntf                 string         `json:"localInterface"`
+	LocalIntfStatus           string         `json:"localIntfStatus"`
+	PeerAddress               string         `json:"peerAddress"`
+	PeerLink                  string         `json:"peerLink"`
+	PeerLinkStatus            string         `json:"peerLinkStatus"`
+	SystemID                  string         `json:"systemId"`
+	DualPrimaryDetectionState string         `json:"dualPrimaryDetectionState"`
+	ReloadDelay               int            `json:"reloadDelay"`
+	ReloadDelayNonMlag        int            `json:"reloadDelayNonMlag"`
+	PortsErrdisabled          bool           `json:"portsErrdisabled"`
+	MlagPorts                 map[string]int `json:"mlagPorts"` // counts by state, e.g. "Active-full"
+}
+
+// Healthy reports whether MLAG is active, connected and consistent with its peer
+func (m *ShowMlag) Healthy() bool {
+	return m.State == "active" && m.NegStatus == "connected" && m.ConfigSanity == "consistent" && m.PeerLinkStatus == "up"
+}
@@ -0,0 +1,132 @@
+package eos
+
+import "time"
+
+func init() {
+	Register("show version", 1, func() any { return new(ShowVersion) })
+	Register("show environment temperature", 1, func() any { return new(ShowEnvironmentTemperature) })
+	Register("show environment cooling", 1, func() any { return new(ShowEnvironmentCooling) })
+	Register("show environment power", 1, func() any { return new(ShowEnvironmentPower) })
+}
+
+// ShowVersion is the output of "show version"
+type ShowVersion struct {
+	MfgName          string  `json:"mfgName"`
+	ModelName        string  `json:"modelName"`
+	HardwareRevision string  `json:"hardwareRevision"`
+	SerialNumber     string  `json:"serialNumber"`
+	SystemMacAddress string  `json:"systemMacAddress"`
+	HwMacAddress     string  `json:"hwMacAddress"`
+	ConfigMacAddress string  `json:"configMacAddress"`
+	Version          string  `json:"version"`
+	Architecture     string  `json:"architecture"`
+	InternalVersion  string  `json:"internalVersion"`
+	InternalBuildID  string  `json:"internalBuildId"`
+	ImageFormat      string  `json:"imageFormatVersion"`
+	BootupTimestamp  float64 `json:"bootupTimestamp"`
+	Uptime           float64 `json:"uptime"`   // seconds
+	MemTotal         int64   `json:"memTotal"` // KB
+	MemFree          int64   `json:"memFree"`  // KB
+	IsIntlVersion    bool    `json:"isIntlVersion"`
+}
+
+// BootedAt returns when the device booted
+func (v *ShowVersion) BootedAt() time.Time {
+	return unixTime(v.BootupTimestamp)
+}
+
+// IsVirtual reports whether the device is a vEOS or cEOS instance without a serial number
+func (v *ShowVersion) IsVirtual() bool {
+	return v.SerialNumber == "" || v.ModelName == "vEOS" || v.ModelName == "vEOS-lab" || v.ModelName == "cEOSLab"
+}
+
+// ShowEnvironmentTemperature is the output of "show environment temperature"
+type ShowEnvironmentTemperature struct {
+	SystemStatus       string            `json:"systemStatus"` // temperatureOk, temperatureWarning, temperatureCritical
+	ShutdownOnOverheat string            `json:"shutdownOnOverheat"`
+	TempSensors        []TempSensor      `json:"tempSensors"`
+	PowerSupplySlots   []TempSensorGroup `json:"powerSupplySlots"`
+	CardSlots          []TempSensorGroup `json:"cardSlots"`
+}
+
+// TempSensorGroup holds the sensors of a card or power supply slot
+type TempSensorGroup struct {
+	RelPos           string       `json:"relPos"`
+	EntPhysicalClass string       `json:"entPhysicalClass"`
+	TempSensors      []TempSensor `json:"tempSensors"`
+}
+
+// TempSensor is one temperature sensor, in degrees Celsius
+type TempSensor struct {
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	CurrentTemperature float64 `json:"currentTemperature"`
+	MaxTemperature     float64 `json:"maxTemperature"`
+	OverheatThreshold  float64 `json:"overheatThreshold"`
+	CriticalThreshold  float64 `json:"criticalThreshold"`
+	HwStatus           string  `json:"hwStatus"`
+	InAlertState       bool    `json:"inAlertState"`
+	AlertCount         int     `json:"alertCount"`
+}
+
+// Sensors returns the system sensors followed by those of every slot
+func (t *ShowEnvironmentTemperature) Sensors() []TempSensor {
+	sensors := append([]TempSensor(nil), t.TempSensors...)
+	for _, group := range append(t.CardSlots, t.PowerSupplySlots...) {
+		sensors = append(sensors, group.TempSensors...)
+	}
+	return sensors
+}
+
+// ShowEnvironmentCooling is the output of "show environment cooling"
+type ShowEnvironmentCooling struct {
+	SystemStatus     string    `json:"systemStatus"` // coolingOk, coolingWarning, ...
+	AirflowDirection string    `json:"airflowDirection"`
+	OverrideFanSpeed int       `json:"overrideFanSpeed"`
+	FanTraySlots     []FanTray `json:"fanTraySlots"`
+	PowerSupplySlots []FanTray `json:"powerSupplySlots"`
+}
+
+// FanTray is a fan tray or power supply with its fans
+type FanTray struct {
+	Label  string `json:"label"`
+	Status string `json:"status"`
+	Speed  int    `json:"speed"`
+	Fans   []Fan  `json:"fans"`
+}
+
+// Fan is one fan; speeds are in percent
+type Fan struct {
+	Label           string `json:"label"`
+	Status          string `json:"status"`
+	ActualSpeed     int    `json:"actualSpeed"`
+	ConfiguredSpeed int    `json:"configuredSpeed"`
+	SpeedStable     bool   `json:"speedStable"`
+	SpeedHwOverride bool   `json:"speedHwOverride"`
+}
+
+// ShowEnvironmentPower is the output of "show environment power"
+type ShowEnvironmentPower struct {
+	PowerSupplies map[string]PowerSupply `json:"powerSupplies"` // keyed by slot number
+}
+
+// PowerSupply is one power supply; currents are in amperes, power in watts
+type PowerSupply struct {
+	ModelName     string  `json:"modelName"`
+	Capacity      float64 `json:"capacity"`
+	State         string  `json:"state"` // ok, powerLoss, failed, ...
+	InputCurrent  float64 `json:"inputCurrent"`
+	OutputCurrent float64 `json:"outputCurrent"`
+	InputVoltage  float64 `json:"inputVoltage"`
+	OutputVoltage float64 `json:"outputVoltage"`
+	OutputPower   float64 `json:"outputPower"`
+	Uptime        float64 `json:"uptime"`
+}
+
+// unixTime converts the fractional Unix timestamps EOS reports
+func unixTime(secs float64) time.Time {
+	if secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(secs*float64(time.Second)))
+}
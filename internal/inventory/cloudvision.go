@@ -0,0 +1,171 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ClusterTag returns the tag applied to endpoints synced from a CloudVision endpoint
+func ClusterTag(cv core.Endpoint) string {
+	name := cv.Name
+	if name == "" {
+		if u, err := url.Parse(cv.URL); err == nil {
+			name = u.Hostname()
+		}
+	}
+	return "cvp:" + strings.ToLower(strings.Join(strings.Fields(name), "-"))
+}
+
+// SyncCloudVision creates or updates eAPI endpoints and inventory records from a
+// CloudVision device list. Devices are matched by serial number, then by URL and
+// finally by name. Records tagged with the cluster that CloudVision no longer
+// reports are flagged as missing rather than deleted.
+func (s *Service) SyncCloudVision(cv core.Endpoint, devices []core.CVDevice) (core.CVSyncResult, error) {
+	tag := ClusterTag(cv)
+	result := core.CVSyncResult{Cluster: tag, Devices: len(devices)}
+
+	endpoints, err := s.store.GetEndpoints()
+	if err != nil {
+		return result, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+	inventory, err := s.store.GetDeviceInventory()
+	if err != nil {
+		return result, fmt.Errorf("failed to load device inventory: %w", err)
+	}
+
+	bySerial := make(map[string]string)
+	for _, d := range inventory {
+		if d.Serial != "" {
+			bySerial[d.Serial] = d.ID
+		}
+	}
+	byURL := make(map[string]string)
+	byName := make(map[string]string)
+	for _, ep := range endpoints {
+		if ep.Type != core.EndpointEAPI {
+			continue
+		}
+		byURL[NormalizeURL(ep.URL)] = ep.ID
+		byName[strings.ToLower(ep.Name)] = ep.ID
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool)
+	for _, dev := range devices {
+		name := dev.Hostname
+		if name == "" {
+			name = dev.Serial
+		}
+		deviceURL := cvDeviceURL(dev)
+
+		id := bySerial[dev.Serial]
+		if id == "" && deviceURL != "" {
+			id = byURL[NormalizeURL(deviceURL)]
+		}
+		if id == "" {
+			id = byName[strings.ToLower(name)]
+		}
+
+		if id != "" {
+			seen[id] = true
+			err := s.store.UpdateEndpointAndDevice(id, func(endpoint *core.Endpoint, device *core.DeviceInventory) error {
+				endpoint.Tags = appendTag(endpoint.Tags, tag)
+				initDevice(device, *endpoint)
+				syncDevice(device, *endpoint)
+				applyCVDevice(device, dev, now)
+				return nil
+			})
+			if err != nil {
+				return result, fmt.Errorf("failed to update %s: %w", name, err)
+			}
+			result.Updated = append(result.Updated, name)
+			continue
+		}
+
+		if deviceURL == "" {
+			// Nothing to connect to; CloudVision knows the device only by serial
+			continue
+		}
+
+		device := core.DeviceInventory{
+			Source: "cloudvision",
+			Notes:  fmt.Sprintf("Synced from CloudVision %s", cv.Name),
+		}
+		applyCVDevice(&device, dev, now)
+		endpoint, err := s.addEndpoint(core.Endpoint{
+			Name: name,
+			Type: core.EndpointEAPI,
+			URL:  deviceURL,
+			Tags: []string{tag},
+		}, device)
+		if err != nil {
+			return result, fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		seen[endpoint.ID] = true
+		result.Created = append(result.Created, name)
+	}
+
+	// Flag devices of this cluster that CloudVision no longer reports
+	for _, ep := range endpoints {
+		if seen[ep.ID] || !hasTag(ep.Tags, tag) {
+			continue
+		}
+		err := s.store.UpdateEndpointAndDevice(ep.ID, func(endpoint *core.Endpoint, device *core.DeviceInventory) error {
+			initDevice(device, *endpoint)
+			device.Missing = true
+			device.Status = "missing"
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to flag %s as missing: %w", ep.Name, err)
+		}
+		result.Missing = append(result.Missing, ep.Name)
+	}
+
+	return result, nil
+}
+
+// applyCVDevice copies CloudVision facts onto an inventory record
+func applyCVDevice(device *core.DeviceInventory, dev core.CVDevice, now time.Time) {
+	device.Serial = dev.Serial
+	device.Model = dev.Model
+	device.Version = dev.Version
+	device.Streaming = dev.StreamingStatus
+	if dev.ManagementIP != "" {
+		device.ManagementIP = dev.ManagementIP
+	}
+	if device.Missing {
+		device.Missing = false
+		device.Status = "disconnected"
+	}
+	device.LastSynced = now
+}
+
+// cvDeviceURL builds the eAPI base URL of a CloudVision device, preferring its
+// management IP. The client appends /command-api.
+func cvDeviceURL(dev core.CVDevice) string {
+	host := dev.ManagementIP
+	if host == "" {
+		host = dev.FQDN
+	}
+	if host == "" {
+		return ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + strings.Trim(host, "[]") + "]"
+	}
+	return "https://" + host
+}
+
+// hasTag reports whether tags contains tag
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
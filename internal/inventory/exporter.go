@@ -0,0 +1,224 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Export writes every endpoint in the given inventory format. Passwords and
+// tokens are never exported; endpoints carry their credential reference instead.
+// The Ansible formats only hold eAPI endpoints, since Ansible drives EOS over eAPI.
+func (s *Service) Export(format string) ([]byte, error) {
+	endpoints, err := s.store.GetEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].Name < endpoints[j].Name })
+
+	switch format {
+	case FormatCSV:
+		return exportCSV(endpoints)
+	case FormatYAML:
+		return exportYAML(endpoints)
+	case FormatAnsibleINI:
+		return exportAnsibleINI(endpoints), nil
+	case FormatAnsibleYAML:
+		return exportAnsibleYAML(endpoints)
+	default:
+		return nil, fmt.Errorf("unsupported inventory format: %s", format)
+	}
+}
+
+// exportCSV writes endpoints as CSV with the columns understood by parseCSV
+func exportCSV(endpoints []core.Endpoint) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"name", "type", "url", "username", "credential_ref", "tags", "tls_verify"})
+	for _, ep := range endpoints {
+		w.Write([]string{
+			ep.Name,
+			string(ep.Type),
+			ep.URL,
+			ep.Username,
+			ep.CredentialRef,
+			strings.Join(ep.Tags, ";"),
+			strconv.FormatBool(ep.TLSVerify),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exportYAML writes endpoints in the native YAML format
+func exportYAML(endpoints []core.Endpoint) ([]byte, error) {
+	doc := yamlInventory{Endpoints: []yamlEndpoint{}}
+	for _, ep := range endpoints {
+		doc.Endpoints = append(doc.Endpoints, yamlEndpoint{
+			Name:          ep.Name,
+			Type:          string(ep.Type),
+			URL:           ep.URL,
+			Username:      ep.Username,
+			CredentialRef: ep.CredentialRef,
+			Tags:          ep.Tags,
+			TLSVerify:     ep.TLSVerify,
+		})
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal YAML inventory: %w", err)
+	}
+	return data, nil
+}
+
+// ansibleHostVars maps an eAPI endpoint onto Ansible httpapi connection variables
+func ansibleHostVars(ep core.Endpoint) [][2]string {
+	vars := [][2]string{}
+	host := ep.URL
+	useTLS := true
+	port := ""
+	if u, err := url.Parse(ep.URL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+		useTLS = u.Scheme != "http"
+		port = u.Port()
+	}
+
+	vars = append(vars, [2]string{"ansible_host", host})
+	vars = append(vars, [2]string{"ansible_connection", "ansible.netcommon.httpapi"})
+	vars = append(vars, [2]string{"ansible_network_os", "arista.eos.eos"})
+	vars = append(vars, [2]string{"ansible_httpapi_use_ssl", strconv.FormatBool(useTLS)})
+	vars = append(vars, [2]string{"ansible_httpapi_validate_certs", strconv.FormatBool(ep.TLSVerify)})
+	if port != "" {
+		vars = append(vars, [2]string{"ansible_httpapi_port", port})
+	}
+	if ep.Username != "" {
+		vars = append(vars, [2]string{"ansible_user", ep.Username})
+	}
+	if ep.CredentialRef != "" {
+		vars = append(vars, [2]string{"credential_ref", ep.CredentialRef})
+	}
+	return vars
+}
+
+// ansibleGroups groups eAPI endpoints by tag; untagged endpoints fall under "ungrouped"
+func ansibleGroups(endpoints []core.Endpoint) ([]string, map[string][]core.Endpoint) {
+	groups := make(map[string][]core.Endpoint)
+	for _, ep := range endpoints {
+		if ep.Type != core.EndpointEAPI {
+			continue
+		}
+		if len(ep.Tags) == 0 {
+			groups["ungrouped"] = append(groups["ungrouped"], ep)
+		}
+		for _, tag := range ep.Tags {
+			groups[ansibleGroupName(tag)] = append(groups[ansibleGroupName(tag)], ep)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, groups
+}
+
+// ansibleGroupName turns a tag into a valid Ansible group name
+func ansibleGroupName(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, tag)
+}
+
+// ansibleHostName turns an endpoint name into an Ansible inventory host name
+func ansibleHostName(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// exportAnsibleINI writes eAPI endpoints as an Ansible INI inventory. Host
+// variables are written on a host's first appearance only.
+func exportAnsibleINI(endpoints []core.Endpoint) []byte {
+	var buf bytes.Buffer
+	names, groups := ansibleGroups(endpoints)
+	written := make(map[string]bool)
+
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "[%s]\n", name)
+		for _, ep := range groups[name] {
+			buf.WriteString(ansibleHostName(ep.Name))
+			if !written[ep.ID] {
+				written[ep.ID] = true
+				for _, kv := range ansibleHostVars(ep) {
+					value := kv[1]
+					if strings.ContainsAny(value, " \t#") {
+						value = strconv.Quote(value)
+					}
+					fmt.Fprintf(&buf, " %s=%s", kv[0], value)
+				}
+			}
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes()
+}
+
+// exportAnsibleYAML writes eAPI endpoints as an Ansible YAML inventory
+func exportAnsibleYAML(endpoints []core.Endpoint) ([]byte, error) {
+	names, groups := ansibleGroups(endpoints)
+	written := make(map[string]bool)
+
+	children := &yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range names {
+		hosts := &yaml.Node{Kind: yaml.MappingNode}
+		for _, ep := range groups[name] {
+			vars := &yaml.Node{Kind: yaml.MappingNode}
+			if !written[ep.ID] {
+				written[ep.ID] = true
+				for _, kv := range ansibleHostVars(ep) {
+					vars.Content = append(vars.Content, scalar(kv[0]), scalar(kv[1]))
+				}
+			}
+			hosts.Content = append(hosts.Content, scalar(ansibleHostName(ep.Name)), vars)
+		}
+		group := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalar("hosts"), hosts}}
+		children.Content = append(children.Content, scalar(name), group)
+	}
+
+	all := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalar("children"), children}}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{scalar("all"), all}}
+
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Ansible inventory: %w", err)
+	}
+	return data, nil
+}
+
+// scalar builds a YAML scalar node, letting the encoder pick the tag and quoting
+func scalar(v string) *yaml.Node {
+	n := &yaml.Node{}
+	n.SetString(v)
+	if v == "true" || v == "false" {
+		n.Tag = "!!bool"
+	} else if _, err := strconv.Atoi(v); err == nil {
+		n.Tag = "!!int"
+	}
+	return n
+}
@@ -0,0 +1,160 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ApplyFacts stores collected device facts on an endpoint's inventory record
+func (s *Service) ApplyFacts(id string, facts core.DeviceFacts) error {
+	err := s.store.UpdateEndpointAndDevice(id, func(endpoint *core.Endpoint, device *core.DeviceInventory) error {
+		initDevice(device, *endpoint)
+
+		device.Hostname = facts.Hostname
+		device.FQDN = facts.FQDN
+		device.Model = facts.Model
+		device.Serial = facts.Serial
+		device.Version = facts.Version
+		device.MAC = facts.MAC
+		device.ChassisID = facts.ChassisID
+		device.UptimeSeconds = facts.UptimeSeconds
+		device.MemTotalKB = facts.MemTotalKB
+		device.MemFreeKB = facts.MemFreeKB
+		device.Transceivers = facts.Transceivers
+		device.FactsCollected = facts.CollectedAt
+		if facts.Model != "" {
+			device.DeviceType = facts.Model
+		}
+
+		syncDevice(device, *endpoint)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store device facts: %w", err)
+	}
+	return nil
+}
+
+// Query returns the inventory records matching a filter, ordered by name
+func (s *Service) Query(filter core.InventoryFilter) ([]core.DeviceInventory, error) {
+	devices, err := s.store.GetDeviceInventory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load device inventory: %w", err)
+	}
+
+	var tags map[string][]string
+	if filter.Tag != "" {
+		endpoints, err := s.store.GetEndpoints()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load endpoints: %w", err)
+		}
+		tags = make(map[string][]string)
+		for _, ep := range endpoints {
+			tags[ep.ID] = ep.Tags
+		}
+	}
+
+	var matched []core.DeviceInventory
+	for _, d := range devices {
+		if matchesFilter(d, tags[d.ID], filter) {
+			matched = append(matched, d)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return strings.ToLower(matched[i].Name) < strings.ToLower(matched[j].Name) })
+	return matched, nil
+}
+
+// GroupKeys lists the facts inventory can be grouped by
+var GroupKeys = []string{"model", "version", "status", "source", "type"}
+
+// Group returns the inventory records matching a filter, grouped by a fact
+func (s *Service) Group(filter core.InventoryFilter, by string) ([]core.InventoryGroup, error) {
+	key, ok := groupKeyFunc(by)
+	if !ok {
+		return nil, fmt.Errorf("cannot group inventory by %q (supported: %s)", by, strings.Join(GroupKeys, ", "))
+	}
+
+	devices, err := s.Query(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	var groups []core.InventoryGroup
+	for _, d := range devices {
+		k := key(d)
+		if k == "" {
+			k = "unknown"
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, core.InventoryGroup{Key: k})
+		}
+		groups[i].Devices = append(groups[i].Devices, d)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
+
+// groupKeyFunc returns the accessor for a group-by fact
+func groupKeyFunc(by string) (func(core.DeviceInventory) string, bool) {
+	switch by {
+	case "model":
+		return func(d core.DeviceInventory) string { return d.Model }, true
+	case "version":
+		return func(d core.DeviceInventory) string { return d.Version }, true
+	case "status":
+		return func(d core.DeviceInventory) string { return d.Status }, true
+	case "source":
+		return func(d core.DeviceInventory) string { return d.Source }, true
+	case "type":
+		return func(d core.DeviceInventory) string { return d.Type }, true
+	}
+	return nil, false
+}
+
+// matchesFilter reports whether an inventory record passes every set filter field
+func matchesFilter(d core.DeviceInventory, tags []string, f core.InventoryFilter) bool {
+	if f.Query != "" {
+		q := strings.ToLower(f.Query)
+		found := false
+		for _, v := range []string{d.Name, d.Hostname, d.Serial, d.URL, d.ManagementIP} {
+			if strings.Contains(strings.ToLower(v), q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if !globMatch(f.Model, d.Model) || !globMatch(f.Version, d.Version) ||
+		!globMatch(f.Status, d.Status) || !globMatch(f.Source, d.Source) || !globMatch(f.Type, d.Type) {
+		return false
+	}
+
+	if f.Tag != "" {
+		for _, t := range tags {
+			if globMatch(f.Tag, t) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// globMatch matches a value against a case-insensitive glob; an empty pattern matches everything
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return err == nil && ok
+}
@@ -0,0 +1,712 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported inventory file formats
+const (
+	FormatCSV         = "csv"
+	FormatYAML        = "yaml"
+	FormatAnsibleINI  = "ansible-ini"
+	FormatAnsibleYAML = "ansible-yaml"
+)
+
+// Import parses an inventory file and adds every new endpoint. With dryRun set
+// nothing is saved and the result previews what would be added.
+func (s *Service) Import(format string, data []byte, dryRun bool) (core.ImportResult, error) {
+	entries, err := Parse(format, data)
+	if err != nil {
+		return core.ImportResult{}, err
+	}
+
+	existing, err := s.store.GetEndpoints()
+	if err != nil {
+		return core.ImportResult{}, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+	seen := make(map[string]string)
+	for _, ep := range existing {
+		seen[NormalizeURL(ep.URL)] = ep.Name
+	}
+
+	result := core.ImportResult{Format: format, DryRun: dryRun}
+	for _, entry := range entries {
+		if entry.Action != "invalid" {
+			if field := s.referenceField(entry.Endpoint); field != "" {
+				entry.Action = "invalid"
+				entry.Reason = fmt.Sprintf("%s is a secret reference; only credential_ref may refer to secrets", field)
+			}
+		}
+		if entry.Action != "invalid" {
+			key := NormalizeURL(entry.Endpoint.URL)
+			if name, ok := seen[key]; ok {
+				entry.Action = "duplicate"
+				entry.Reason = fmt.Sprintf("URL already used by %s", name)
+			} else {
+				seen[key] = entry.Endpoint.Name
+				entry.Action = "add"
+			}
+		}
+
+		switch entry.Action {
+		case "add":
+			if !dryRun {
+				added, err := s.addEndpoint(entry.Endpoint, core.DeviceInventory{
+					Source: "import",
+					Notes:  fmt.Sprintf("Imported from %s inventory", format),
+				})
+				if err != nil {
+					return result, fmt.Errorf("failed to import %s: %w", entry.Endpoint.Name, err)
+				}
+				entry.Endpoint = added
+			}
+			result.Added++
+		case "duplicate":
+			result.Duplicates++
+		default:
+			result.Invalid++
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+// referenceField returns the first imported field other than credential_ref that
+// holds a secret reference, or "" when there is none
+func (s *Service) referenceField(ep core.Endpoint) string {
+	if s.isReference == nil {
+		return ""
+	}
+	fields := []struct{ name, value string }{
+		{"name", ep.Name},
+		{"url", ep.URL},
+		{"username", ep.Username},
+	}
+	for _, tag := range ep.Tags {
+		fields = append(fields, struct{ name, value string }{"tag", tag})
+	}
+	for _, f := range fields {
+		if s.isReference(f.value) {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// Parse reads an inventory file into import entries without checking for duplicates
+func Parse(format string, data []byte) ([]core.ImportEntry, error) {
+	var entries []core.ImportEntry
+	var err error
+
+	switch format {
+	case FormatCSV:
+		entries, err = parseCSV(data)
+	case FormatYAML:
+		entries, err = parseYAML(data)
+	case FormatAnsibleINI:
+		entries, err = parseAnsibleINI(data)
+	case FormatAnsibleYAML:
+		entries, err = parseAnsibleYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported inventory format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		validate(&entries[i])
+	}
+	return entries, nil
+}
+
+// validate marks an entry invalid when its endpoint cannot be used
+func validate(entry *core.ImportEntry) {
+	if entry.Action == "invalid" {
+		return
+	}
+
+	ep := &entry.Endpoint
+	if ep.Type == "" {
+		ep.Type = core.EndpointEAPI
+	}
+	switch ep.Type {
+	case core.EndpointEAPI, core.EndpointCV, core.EndpointTelemetry, core.EndpointSSH:
+	default:
+		entry.Action = "invalid"
+		entry.Reason = fmt.Sprintf("unsupported endpoint type: %s", ep.Type)
+		return
+	}
+
+	if ep.URL == "" {
+		entry.Action = "invalid"
+		entry.Reason = "missing host or URL"
+		return
+	}
+	if ep.Name == "" {
+		ep.Name = ep.URL
+	}
+}
+
+// csvColumns lists the recognised CSV header names
+var csvColumns = []string{"name", "type", "url", "host", "port", "username", "credential_ref", "tags", "tls_verify", "password"}
+
+// parseCSV reads a CSV file with a header row naming its columns
+func parseCSV(data []byte) ([]core.ImportEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[name] = i
+	}
+	if _, ok := columns["url"]; !ok {
+		if _, ok := columns["host"]; !ok {
+			return nil, fmt.Errorf("CSV header must contain a url or host column (known columns: %s)", strings.Join(csvColumns, ", "))
+		}
+	}
+
+	var entries []core.ImportEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := r.FieldPos(0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := core.ImportEntry{Source: fmt.Sprintf("line %d", line)}
+		ep := &entry.Endpoint
+		ep.Name = get("name")
+		ep.Type = core.EndpointType(strings.ToLower(get("type")))
+		ep.Username = get("username")
+		ep.CredentialRef = get("credential_ref")
+		ep.TLSVerify = parseBool(get("tls_verify"), false)
+		for _, tag := range strings.FieldsFunc(get("tags"), func(r rune) bool { return r == ';' || r == '|' }) {
+			ep.Tags = appendTag(ep.Tags, strings.TrimSpace(tag))
+		}
+		if get("password") != "" {
+			entry.Warnings = append(entry.Warnings, "inline password ignored; use credential_ref")
+		}
+
+		ep.URL = get("url")
+		if ep.URL == "" && get("host") != "" {
+			ep.URL = buildURL(ep.Type, get("host"), get("port"), true)
+		}
+		if ep.Name == "" {
+			ep.Name = get("host")
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// yamlEndpoint is an endpoint as written in the native YAML inventory format
+type yamlEndpoint struct {
+	Name          string   `yaml:"name"`
+	Type          string   `yaml:"type"`
+	URL           string   `yaml:"url,omitempty"`
+	Host          string   `yaml:"host,omitempty"`
+	Port          string   `yaml:"port,omitempty"`
+	Username      string   `yaml:"username,omitempty"`
+	Password      string   `yaml:"password,omitempty"`
+	CredentialRef string   `yaml:"credentialRef,omitempty"`
+	Tags          []string `yaml:"tags,omitempty"`
+	TLSVerify     bool     `yaml:"tlsVerify"`
+}
+
+// yamlInventory is the native YAML inventory document
+type yamlInventory struct {
+	Endpoints []yamlEndpoint `yaml:"endpoints"`
+}
+
+// parseYAML reads the native YAML format: a list of endpoints, optionally under an "endpoints" key
+func parseYAML(data []byte) ([]core.ImportEntry, error) {
+	var doc yamlInventory
+	if err := yaml.Unmarshal(data, &doc); err != nil || doc.Endpoints == nil {
+		var list []yamlEndpoint
+		if lerr := yaml.Unmarshal(data, &list); lerr != nil {
+			if err == nil {
+				err = lerr
+			}
+			return nil, fmt.Errorf("failed to parse YAML inventory: %w", err)
+		}
+		doc.Endpoints = list
+	}
+
+	var entries []core.ImportEntry
+	for i, y := range doc.Endpoints {
+		entry := core.ImportEntry{Source: fmt.Sprintf("endpoint %d", i+1)}
+		if y.Name != "" {
+			entry.Source = y.Name
+		}
+		ep := &entry.Endpoint
+		ep.Name = y.Name
+		ep.Type = core.EndpointType(strings.ToLower(y.Type))
+		ep.URL = y.URL
+		if ep.URL == "" && y.Host != "" {
+			ep.URL = buildURL(ep.Type, y.Host, y.Port, true)
+		}
+		if ep.Name == "" {
+			ep.Name = y.Host
+		}
+		ep.Username = y.Username
+		ep.CredentialRef = y.CredentialRef
+		ep.TLSVerify = y.TLSVerify
+		for _, tag := range y.Tags {
+			ep.Tags = appendTag(ep.Tags, tag)
+		}
+		if y.Password != "" {
+			entry.Warnings = append(entry.Warnings, "inline password ignored; use credentialRef")
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ansibleHost collects the groups and variables of one Ansible inventory host
+type ansibleHost struct {
+	name   string
+	groups []string
+	vars   map[string]string
+}
+
+// ansibleGroup is an Ansible inventory group
+type ansibleGroup struct {
+	hosts    []string
+	children []string
+	vars     map[string]string
+}
+
+// ansibleInventory is a parsed Ansible inventory, before variable resolution
+type ansibleInventory struct {
+	hosts  map[string]*ansibleHost
+	order  []string
+	groups map[string]*ansibleGroup
+}
+
+// newAnsibleInventory creates an empty Ansible inventory
+func newAnsibleInventory() *ansibleInventory {
+	return &ansibleInventory{
+		hosts:  make(map[string]*ansibleHost),
+		groups: make(map[string]*ansibleGroup),
+	}
+}
+
+// group returns the named group, creating it if needed
+func (inv *ansibleInventory) group(name string) *ansibleGroup {
+	g, ok := inv.groups[name]
+	if !ok {
+		g = &ansibleGroup{vars: make(map[string]string)}
+		inv.groups[name] = g
+	}
+	return g
+}
+
+// addHost records a host as a member of a group, merging its host variables
+func (inv *ansibleInventory) addHost(group, name string, vars map[string]string) {
+	h, ok := inv.hosts[name]
+	if !ok {
+		h = &ansibleHost{name: name, vars: make(map[string]string)}
+		inv.hosts[name] = h
+		inv.order = append(inv.order, name)
+	}
+	for k, v := range vars {
+		h.vars[k] = v
+	}
+	if group != "" {
+		g := inv.group(group)
+		g.hosts = append(g.hosts, name)
+	}
+}
+
+// parseAnsibleINI reads an Ansible INI inventory
+func parseAnsibleINI(data []byte) ([]core.ImportEntry, error) {
+	inv := newAnsibleInventory()
+	section, kind := "ungrouped", "hosts"
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header %q", lineNo, line)
+			}
+			section, kind = strings.Trim(line, "[]"), "hosts"
+			if i := strings.Index(section, ":"); i >= 0 {
+				section, kind = section[:i], section[i+1:]
+			}
+			if kind != "hosts" && kind != "vars" && kind != "children" {
+				return nil, fmt.Errorf("line %d: unknown section type %q", lineNo, kind)
+			}
+			inv.group(section)
+			continue
+		}
+
+		fields, err := splitINIFields(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+
+		switch kind {
+		case "vars":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value in [%s:vars]", lineNo, section)
+			}
+			inv.group(section).vars[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		case "children":
+			inv.group(section).children = append(inv.group(section).children, fields[0])
+			inv.group(fields[0])
+		default:
+			vars := make(map[string]string)
+			for _, f := range fields[1:] {
+				key, value, ok := strings.Cut(f, "=")
+				if !ok {
+					return nil, fmt.Errorf("line %d: expected key=value after host name, got %q", lineNo, f)
+				}
+				vars[key] = unquote(value)
+			}
+			inv.addHost(section, fields[0], vars)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	return inv.entries(), nil
+}
+
+// splitINIFields splits an inventory line on whitespace, honouring quoted values
+func splitINIFields(line string) ([]string, error) {
+	var fields []string
+	var cur strings.Builder
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			cur.WriteRune(r)
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			cur.WriteRune(r)
+		case r == ' ' || r == '\t':
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		case r == '#' && cur.Len() == 0:
+			// Trailing comment
+			return fields, nil
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields, nil
+}
+
+// parseAnsibleYAML reads an Ansible YAML inventory
+func parseAnsibleYAML(data []byte) ([]core.ImportEntry, error) {
+	var root map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse Ansible YAML inventory: %w", err)
+	}
+
+	inv := newAnsibleInventory()
+	names := make([]string, 0, len(root))
+	for name := range root {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		node := root[name]
+		if err := inv.readYAMLGroup(name, &node); err != nil {
+			return nil, err
+		}
+	}
+	return inv.entries(), nil
+}
+
+// yamlGroup is a group as written in an Ansible YAML inventory
+type yamlGroup struct {
+	Hosts    map[string]map[string]any `yaml:"hosts"`
+	Vars     map[string]any            `yaml:"vars"`
+	Children map[string]yaml.Node      `yaml:"children"`
+}
+
+// readYAMLGroup reads a group and, recursively, its children
+func (inv *ansibleInventory) readYAMLGroup(name string, node *yaml.Node) error {
+	var g yamlGroup
+	if err := node.Decode(&g); err != nil {
+		return fmt.Errorf("group %s: %w", name, err)
+	}
+
+	group := inv.group(name)
+	for k, v := range g.Vars {
+		group.vars[k] = fmt.Sprint(v)
+	}
+
+	hosts := make([]string, 0, len(g.Hosts))
+	for host := range g.Hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		vars := make(map[string]string)
+		for k, v := range g.Hosts[host] {
+			vars[k] = fmt.Sprint(v)
+		}
+		inv.addHost(name, host, vars)
+	}
+
+	children := make([]string, 0, len(g.Children))
+	for child := range g.Children {
+		children = append(children, child)
+	}
+	sort.Strings(children)
+	for _, child := range children {
+		group.children = append(group.children, child)
+		node := g.Children[child]
+		if err := inv.readYAMLGroup(child, &node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entries resolves group membership and variables into import entries. Groups
+// become tags; variables apply from outermost group to innermost, then host vars.
+func (inv *ansibleInventory) entries() []core.ImportEntry {
+	// Walk each group's ancestry so hosts inherit parent groups and their vars
+	parents := make(map[string][]string)
+	for name, g := range inv.groups {
+		for _, child := range g.children {
+			parents[child] = append(parents[child], name)
+		}
+	}
+	for _, p := range parents {
+		sort.Strings(p)
+	}
+
+	for name, g := range inv.groups {
+		for _, host := range g.hosts {
+			inv.hosts[host].groups = append(inv.hosts[host].groups, name)
+		}
+	}
+
+	var entries []core.ImportEntry
+	for _, name := range inv.order {
+		h := inv.hosts[name]
+		sort.Strings(h.groups)
+
+		// Collect every group the host belongs to, ordered from outermost to innermost
+		var chain []string
+		visited := make(map[string]bool)
+		var visit func(group string)
+		visit = func(group string) {
+			if visited[group] {
+				return
+			}
+			visited[group] = true
+			for _, p := range parents[group] {
+				visit(p)
+			}
+			chain = append(chain, group)
+		}
+		visit("all")
+		for _, g := range h.groups {
+			visit(g)
+		}
+
+		vars := make(map[string]string)
+		var tags []string
+		for _, group := range chain {
+			if g, ok := inv.groups[group]; ok {
+				for k, v := range g.vars {
+					vars[k] = v
+				}
+			}
+			if group != "all" && group != "ungrouped" {
+				tags = appendTag(tags, group)
+			}
+		}
+		for k, v := range h.vars {
+			vars[k] = v
+		}
+
+		entries = append(entries, ansibleEntry(name, vars, tags))
+	}
+	return entries
+}
+
+// ansibleEntry maps the resolved variables of an Ansible host onto an endpoint
+func ansibleEntry(name string, vars map[string]string, tags []string) core.ImportEntry {
+	entry := core.ImportEntry{Source: name}
+	ep := &entry.Endpoint
+	ep.Name = name
+	ep.Type = core.EndpointEAPI
+	ep.Tags = tags
+	ep.Username = firstVar(vars, "ansible_user", "ansible_ssh_user")
+	ep.CredentialRef = vars["credential_ref"]
+
+	if conn := vars["ansible_connection"]; conn != "" && !strings.HasSuffix(conn, "httpapi") {
+		entry.Warnings = append(entry.Warnings, fmt.Sprintf("ansible_connection %s mapped to eAPI", conn))
+	}
+	if netOS := vars["ansible_network_os"]; netOS != "" && !strings.HasSuffix(netOS, "eos") {
+		entry.Action = "invalid"
+		entry.Reason = fmt.Sprintf("ansible_network_os %s is not EOS", netOS)
+		return entry
+	}
+	if firstVar(vars, "ansible_password", "ansible_ssh_pass", "ansible_httpapi_password") != "" {
+		entry.Warnings = append(entry.Warnings, "inline password ignored; set credential_ref")
+	}
+
+	useTLS := parseBool(vars["ansible_httpapi_use_ssl"], true)
+	ep.TLSVerify = parseBool(vars["ansible_httpapi_validate_certs"], false)
+
+	host := firstVar(vars, "ansible_host", "ansible_ssh_host")
+	if host == "" {
+		host = name
+	}
+	port := firstVar(vars, "ansible_httpapi_port", "ansible_port")
+	if vars["ansible_httpapi_port"] == "" && port == "22" {
+		// ansible_port usually names the SSH port, not eAPI
+		port = ""
+	}
+	ep.URL = buildURL(ep.Type, host, port, useTLS)
+	return entry
+}
+
+// buildURL builds an endpoint URL from a host and optional port. eAPI URLs are base
+// URLs; the client appends /command-api.
+func buildURL(typ core.EndpointType, host, port string, useTLS bool) string {
+	if strings.Contains(host, "://") {
+		return host
+	}
+	if port != "" {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), port)
+	}
+	switch typ {
+	case core.EndpointTelemetry:
+		return host
+	case core.EndpointCV:
+		return "https://" + host
+	case core.EndpointSSH:
+		return "ssh://" + host
+	}
+	scheme := "https"
+	if !useTLS {
+		scheme = "http"
+	}
+	return scheme + "://" + host
+}
+
+// NormalizeURL reduces a URL to a canonical form for duplicate detection. The eAPI
+// /command-api path is optional in endpoint URLs and ignored.
+func NormalizeURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return strings.ToLower(strings.TrimRight(strings.TrimSpace(raw), "/"))
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "https" && port == "443") || (u.Scheme == "http" && port == "80") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	path := strings.TrimSuffix(strings.TrimRight(u.Path, "/"), "/command-api")
+	return strings.ToLower(u.Scheme) + "://" + host + path
+}
+
+// firstVar returns the first non-empty variable among keys
+func firstVar(vars map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := vars[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// parseBool parses Ansible/YAML style booleans, falling back to def
+func parseBool(v string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "yes", "on", "y":
+		return true
+	case "no", "off", "n":
+		return false
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// unquote strips matching quotes from an INI value
+func unquote(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// appendTag appends a tag unless it is empty or already present
+func appendTag(tags []string, tag string) []string {
+	if tag == "" {
+		return tags
+	}
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
@@ -0,0 +1,279 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/store"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newTestService creates an inventory service over a temporary store
+func newTestService(t *testing.T) *Service {
+	t.Helper()
+	s, err := store.NewStore(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return NewService(s)
+}
+
+// readFixture reads an inventory file under testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// parsedEntry is the part of an import entry checked by the parser tests
+type parsedEntry struct {
+	Name          string
+	Type          core.EndpointType
+	URL           string
+	Username      string
+	CredentialRef string
+	Tags          []string
+	TLSVerify     bool
+	Action        string
+	Warnings      int
+}
+
+// summarize reduces an import entry to the fields the tests compare
+func summarize(entry core.ImportEntry) parsedEntry {
+	ep := entry.Endpoint
+	return parsedEntry{
+		Name:          ep.Name,
+		Type:          ep.Type,
+		URL:           ep.URL,
+		Username:      ep.Username,
+		CredentialRef: ep.CredentialRef,
+		Tags:          ep.Tags,
+		TLSVerify:     ep.TLSVerify,
+		Action:        entry.Action,
+		Warnings:      len(entry.Warnings),
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		format  string
+		fixture string
+		want    []parsedEntry
+	}{
+		{FormatCSV, "inventory.csv", []parsedEntry{
+			{Name: "leaf1", Type: core.EndpointEAPI, URL: "https://10.0.0.11", Username: "admin", CredentialRef: "lab-admin", Tags: []string{"dc1", "leaf"}, TLSVerify: true},
+			{Name: "leaf2", Type: core.EndpointEAPI, URL: "https://10.0.0.12:8443", Username: "admin", Tags: []string{"dc1", "leaf"}, Warnings: 1},
+			{Name: "cvp", Type: core.EndpointCV, URL: "https://cvp.example.net"},
+			{Name: "gnmi1", Type: core.EndpointTelemetry, URL: "10.0.0.11:6030"},
+			{Name: "ssh1", Type: core.EndpointSSH, URL: "ssh://10.0.0.13", Username: "ops"},
+			{Name: "bad", Type: "netconf", URL: "https://10.0.0.14", Action: "invalid"},
+			{Name: "nohost", Type: core.EndpointEAPI, Action: "invalid"},
+		}},
+		{FormatYAML, "inventory.yaml", []parsedEntry{
+			{Name: "spine1", Type: core.EndpointEAPI, URL: "https://10.0.1.1", Username: "admin", CredentialRef: "lab-admin", Tags: []string{"dc1", "spine"}, TLSVerify: true},
+			{Name: "10.0.1.2", Type: core.EndpointEAPI, URL: "https://10.0.1.2:8443", Warnings: 1},
+			{Name: "cvp", Type: core.EndpointCV, URL: "https://cvp.example.net"},
+		}},
+		{FormatAnsibleINI, "ansible.ini", []parsedEntry{
+			{Name: "leaf1", Type: core.EndpointEAPI, URL: "https://10.0.0.11", Username: "admin", Tags: []string{"dc1", "fabric", "leaf"}, TLSVerify: true},
+			{Name: "leaf2", Type: core.EndpointEAPI, URL: "https://10.0.0.12:8443", Username: "admin", Tags: []string{"dc1", "fabric", "leaf"}, TLSVerify: true},
+			{Name: "spine1", Type: core.EndpointEAPI, URL: "https://10.0.1.1", Username: "ops team", CredentialRef: "lab-admin", Tags: []string{"dc1", "fabric", "spine"}, TLSVerify: true},
+			{Name: "ios1", Type: core.EndpointEAPI, Username: "admin", Tags: []string{"legacy"}, Action: "invalid"},
+			// ansible_port names the SSH port and is not used for eAPI
+			{Name: "lab", Type: core.EndpointEAPI, URL: "https://10.0.2.1", Username: "admin", Tags: []string{"legacy"}, Warnings: 1},
+		}},
+		{FormatAnsibleYAML, "ansible.yaml", []parsedEntry{
+			{Name: "leaf1", Type: core.EndpointEAPI, URL: "https://10.0.0.11", Username: "admin", Tags: []string{"lab", "dc1", "leaf"}},
+			{Name: "leaf2", Type: core.EndpointEAPI, URL: "http://10.0.0.12", Username: "admin", Tags: []string{"dc1", "leaf"}},
+			{Name: "spine1", Type: core.EndpointEAPI, URL: "https://10.0.1.1", Username: "admin", Tags: []string{"dc1", "spine"}, TLSVerify: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			entries, err := Parse(tt.format, readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.want))
+			}
+			for i, entry := range entries {
+				if got := summarize(entry); !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("entry %d:\n got %+v\nwant %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := Parse("toml", nil); err == nil {
+		t.Error("Parse of an unsupported format: expected an error")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+		err    string
+	}{
+		{FormatCSV, "name,username\nleaf1,admin\n", "url or host column"},
+		{FormatAnsibleINI, "[leaf\nleaf1\n", "malformed section header"},
+		{FormatAnsibleINI, "[leaf:hostvars]\n", "unknown section type"},
+		{FormatAnsibleINI, "[leaf:vars]\nansible_user\n", "expected key=value"},
+		{FormatAnsibleINI, "leaf1 ansible_host\n", "expected key=value after host name"},
+		{FormatAnsibleINI, "leaf1 ansible_user=\"admin\n", "unterminated quote"},
+		{FormatYAML, "endpoints: [", "failed to parse YAML inventory"},
+		{FormatAnsibleYAML, "- leaf1\n", "failed to parse Ansible YAML inventory"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.format, []byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%s, %q) = %v, want an error containing %q", tt.format, tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestImportDuplicates(t *testing.T) {
+	svc := newTestService(t)
+	if _, err := svc.AddEndpoint(core.Endpoint{Name: "existing", Type: core.EndpointEAPI, URL: "https://10.0.0.11:443/command-api"}); err != nil {
+		t.Fatal(err)
+	}
+	svc.RejectReferences(func(value string) bool { return strings.HasPrefix(value, "env:") })
+
+	data := []byte("name,host,username\n" +
+		"leaf1,10.0.0.11,admin\n" + // same device as the existing endpoint
+		"leaf2,10.0.0.12,admin\n" +
+		"leaf2-again,10.0.0.12,admin\n" + // duplicate within the file
+		"leaf3,10.0.0.13,env:EAPI_USER\n" + // secret reference outside credential_ref
+		"leaf4,,admin\n")
+
+	// A dry run previews the import without saving anything
+	preview, err := svc.Import(FormatCSV, data, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, e := range preview.Entries {
+		actions = append(actions, e.Action)
+	}
+	if want := []string{"duplicate", "add", "duplicate", "invalid", "invalid"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	if preview.Added != 1 || preview.Duplicates != 2 || preview.Invalid != 2 {
+		t.Errorf("added/duplicates/invalid = %d/%d/%d, want 1/2/2", preview.Added, preview.Duplicates, preview.Invalid)
+	}
+	if reason := preview.Entries[0].Reason; !strings.Contains(reason, "existing") {
+		t.Errorf("duplicate reason = %q, want the name of the existing endpoint", reason)
+	}
+	if reason := preview.Entries[3].Reason; !strings.Contains(reason, "username") {
+		t.Errorf("reference reason = %q, want the offending field", reason)
+	}
+	if endpoints, _ := svc.store.GetEndpoints(); len(endpoints) != 1 {
+		t.Errorf("dry run saved %d endpoints", len(endpoints)-1)
+	}
+
+	result, err := svc.Import(FormatCSV, data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Added != 1 || result.Entries[1].Endpoint.ID == "" {
+		t.Errorf("import added %d endpoints, entry %+v", result.Added, result.Entries[1].Endpoint)
+	}
+
+	// Importing the same file again only finds duplicates
+	again, err := svc.Import(FormatCSV, data, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Added != 0 || again.Duplicates != 3 {
+		t.Errorf("reimport added/duplicates = %d/%d, want 0/3", again.Added, again.Duplicates)
+	}
+}
+
+// exported is the part of an endpoint that survives an export and re-import
+type exported struct {
+	Type          core.EndpointType
+	URL           string
+	Username      string
+	CredentialRef string
+	Tags          []string
+	TLSVerify     bool
+}
+
+// endpointsByName loads the endpoints of a service keyed by name
+func endpointsByName(t *testing.T, svc *Service) map[string]exported {
+	t.Helper()
+	endpoints, err := svc.store.GetEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(map[string]exported)
+	for _, ep := range endpoints {
+		tags := append([]string(nil), ep.Tags...)
+		sort.Strings(tags)
+		out[ep.Name] = exported{ep.Type, ep.URL, ep.Username, ep.CredentialRef, tags, ep.TLSVerify}
+	}
+	return out
+}
+
+func TestExportReimport(t *testing.T) {
+	source := newTestService(t)
+	if _, err := source.Import(FormatCSV, readFixture(t, "inventory.csv"), false); err != nil {
+		t.Fatal(err)
+	}
+	want := endpointsByName(t, source)
+	if len(want) != 5 {
+		t.Fatalf("fixture imported %d endpoints, want 5", len(want))
+	}
+
+	// The Ansible formats only carry eAPI endpoints
+	eapiOnly := make(map[string]exported)
+	for name, ep := range want {
+		if ep.Type == core.EndpointEAPI {
+			eapiOnly[name] = ep
+		}
+	}
+
+	tests := []struct {
+		format string
+		want   map[string]exported
+	}{
+		{FormatCSV, want},
+		{FormatYAML, want},
+		{FormatAnsibleINI, eapiOnly},
+		{FormatAnsibleYAML, eapiOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			data, err := source.Export(tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(data), "hunter2") {
+				t.Error("export contains an inline password")
+			}
+
+			target := newTestService(t)
+			result, err := target.Import(tt.format, data, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Invalid != 0 || result.Duplicates != 0 {
+				t.Errorf("re-import had %d invalid and %d duplicate entries: %+v", result.Invalid, result.Duplicates, result.Entries)
+			}
+			if got := endpointsByName(t, target); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("re-imported endpoints:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := source.Export("toml"); err == nil {
+		t.Error("Export of an unsupported format: expected an error")
+	}
+}
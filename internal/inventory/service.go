@@ -0,0 +1,212 @@
+package inventory
+
+import (
+	"arista_engine/internal/core"
+	"arista_engine/internal/store"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Service owns the relationship between endpoints and their device inventory records.
+// Every change to an endpoint goes through the service so both stay in sync.
+type Service struct {
+	store *store.Store
+
+	// isReference reports whether an imported value is a secret reference
+	isReference func(string) bool
+
+	mu     sync.Mutex
+	lastID int64
+}
+
+// NewService creates a new inventory service
+func NewService(store *store.Store) *Service {
+	return &Service{store: store}
+}
+
+// RejectReferences makes imports reject entries that carry a secret reference in any
+// field but credential_ref, so references are only resolved for credentials
+func (s *Service) RejectReferences(isReference func(value string) bool) {
+	s.isReference = isReference
+}
+
+// AddEndpoint creates an endpoint together with its inventory record
+func (s *Service) AddEndpoint(endpoint core.Endpoint) (core.Endpoint, error) {
+	return s.addEndpoint(endpoint, core.DeviceInventory{Source: "manual"})
+}
+
+// addEndpoint creates an endpoint and an inventory record starting from device
+func (s *Service) addEndpoint(endpoint core.Endpoint, device core.DeviceInventory) (core.Endpoint, error) {
+	now := time.Now()
+	endpoint.ID = s.nextID(now)
+	endpoint.Created = now
+
+	device.ID = endpoint.ID
+	device.Status = "disconnected"
+	device.AddedAt = now
+	if device.Notes == "" {
+		device.Notes = fmt.Sprintf("Added via Endpoint Manager - %s", endpoint.Type)
+	}
+	syncDevice(&device, endpoint)
+
+	if err := s.store.SaveEndpointWithDevice(endpoint, device); err != nil {
+		return core.Endpoint{}, fmt.Errorf("failed to save endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint saves an endpoint and refreshes its inventory record, keeping
+// the record's test counters and notes
+func (s *Service) UpdateEndpoint(endpoint core.Endpoint) error {
+	err := s.store.UpdateEndpointAndDevice(endpoint.ID, func(stored *core.Endpoint, device *core.DeviceInventory) error {
+		if endpoint.Created.IsZero() {
+			endpoint.Created = stored.Created
+		}
+		*stored = endpoint
+		initDevice(device, endpoint)
+		syncDevice(device, endpoint)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update endpoint: %w", err)
+	}
+	return nil
+}
+
+// DeleteEndpoint deletes an endpoint, its inventory record and its test history
+func (s *Service) DeleteEndpoint(id string) error {
+	if err := s.store.DeleteEndpointCascade(id); err != nil {
+		return fmt.Errorf("failed to delete endpoint: %w", err)
+	}
+	return nil
+}
+
+// RecordTest updates the endpoint and inventory status and counters from a
+// connection test and appends the result to the device's test history
+func (s *Service) RecordTest(id string, result core.ConnectionTestResult) error {
+	now := time.Now()
+	entry := core.TestHistoryEntry{
+		Timestamp:  now,
+		Success:    result.Success,
+		Message:    result.Message,
+		StatusCode: result.StatusCode,
+		LatencyMs:  result.ElapsedMs,
+	}
+
+	err := s.store.RecordTestResult(id, entry, func(endpoint *core.Endpoint, device *core.DeviceInventory) error {
+		initDevice(device, *endpoint)
+		syncDevice(device, *endpoint)
+
+		endpoint.Status = "Connected"
+		device.Status = "connected"
+		if !result.Success {
+			endpoint.Status = "Failed"
+			device.Status = "failed"
+		}
+
+		device.LastTested = now
+		device.TestCount++
+		if result.Success {
+			device.SuccessCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record test result: %w", err)
+	}
+	return nil
+}
+
+// GetTestHistory returns the most recent test results of a device, newest first
+func (s *Service) GetTestHistory(id string, limit int) ([]core.TestHistoryEntry, error) {
+	return s.store.GetTestHistory(id, limit)
+}
+
+// Reconcile creates inventory records for endpoints that lack one and removes
+// records (and history) left behind by endpoints deleted before the service existed
+func (s *Service) Reconcile() (int, int, error) {
+	endpoints, err := s.store.GetEndpoints()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+	devices, err := s.store.GetDeviceInventory()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load device inventory: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, d := range devices {
+		known[d.ID] = true
+	}
+
+	var created, removed int
+	existing := make(map[string]bool)
+	for _, ep := range endpoints {
+		existing[ep.ID] = true
+		if known[ep.ID] {
+			continue
+		}
+		err := s.store.UpdateEndpointAndDevice(ep.ID, func(endpoint *core.Endpoint, device *core.DeviceInventory) error {
+			initDevice(device, *endpoint)
+			syncDevice(device, *endpoint)
+			return nil
+		})
+		if err != nil {
+			return created, removed, fmt.Errorf("failed to create inventory record for %s: %w", ep.Name, err)
+		}
+		created++
+	}
+
+	for _, d := range devices {
+		if existing[d.ID] {
+			continue
+		}
+		if err := s.store.DeleteEndpointCascade(d.ID); err != nil {
+			return created, removed, fmt.Errorf("failed to remove orphaned inventory record %s: %w", d.ID, err)
+		}
+		removed++
+	}
+
+	return created, removed, nil
+}
+
+// nextID returns a unique endpoint ID, even for endpoints added within the same clock tick
+func (s *Service) nextID(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := now.UnixNano()
+	if id <= s.lastID {
+		id = s.lastID + 1
+	}
+	s.lastID = id
+	return fmt.Sprintf("ep_%d", id)
+}
+
+// initDevice fills in a missing inventory record for an endpoint
+func initDevice(device *core.DeviceInventory, endpoint core.Endpoint) {
+	if device.ID != "" {
+		return
+	}
+	device.ID = endpoint.ID
+	device.Status = "disconnected"
+	device.AddedAt = endpoint.Created
+	if device.AddedAt.IsZero() {
+		device.AddedAt = time.Now()
+	}
+	device.Notes = fmt.Sprintf("Added via Endpoint Manager - %s", endpoint.Type)
+}
+
+// syncDevice copies the endpoint-owned fields onto an inventory record
+func syncDevice(device *core.DeviceInventory, endpoint core.Endpoint) {
+	device.Name = endpoint.Name
+	if device.Model == "" {
+		// Collected facts replace the endpoint type with the hardware model
+		device.DeviceType = string(endpoint.Type)
+	}
+	device.URL = endpoint.URL
+	device.Username = endpoint.Username
+	device.Password = endpoint.Password
+	device.Type = string(endpoint.Type)
+}
@@ -0,0 +1,181 @@
+package mock
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cloudVisionFixtures holds recorded CloudVision responses. Routes with "body" answer
+// with one JSON document; routes with "stream" answer like the resource APIs, one JSON
+// object per line.
+//
+//go:embed fixtures/cloudvision.json
+var cloudVisionFixtures []byte
+
+// cvRoute is one recorded CloudVision response
+type cvRoute struct {
+	Method string            `json:"method"`
+	Path   string            `json:"path"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Stream []json.RawMessage `json:"stream,omitempty"`
+}
+
+// defaultSessionToken is issued by the mock login when no token is configured
+const defaultSessionToken = "mock-session-token"
+
+// loadCloudVisionRoutes parses the recorded CloudVision responses
+func loadCloudVisionRoutes() ([]cvRoute, error) {
+	var fixtures struct {
+		Routes []cvRoute `json:"routes"`
+	}
+	if err := json.Unmarshal(cloudVisionFixtures, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse CloudVision fixtures: %w", err)
+	}
+	return fixtures.Routes, nil
+}
+
+// serveCloudVision answers CloudVision REST and resource API requests
+func (s *Server) serveCloudVision(w http.ResponseWriter, r *http.Request, cfg Config) {
+	if r.URL.Path == "/cvpservice/login/authenticate.do" {
+		s.serveLogin(w, r, cfg)
+		return
+	}
+	if !checkBearer(r, cfg) {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"code": 16, "message": "unauthenticated: invalid or expired token"})
+		return
+	}
+
+	if r.URL.Path == "/api/resources/serviceaccount/v1/TokenConfig" {
+		s.serveTokenConfig(w, r)
+		return
+	}
+
+	for _, route := range s.cvRoutes {
+		if route.Method != r.Method || route.Path != r.URL.Path {
+			continue
+		}
+		if route.Body != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(route.Body)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		limit := len(route.Stream)
+		if n, err := parseLimit(r.URL.Query().Get("limit")); err == nil && n < limit {
+			limit = n
+		}
+		for _, item := range route.Stream[:limit] {
+			w.Write(item)
+			w.Write([]byte("\n"))
+		}
+		return
+	}
+
+	// Resource APIs answer unknown resources with a gRPC-style status
+	writeJSON(w, http.StatusNotFound, map[string]any{"code": 5, "message": "no recorded response for " + r.Method + " " + r.URL.Path})
+}
+
+// serveLogin emulates /cvpservice/login/authenticate.do, returning the configured
+// token as the session token
+func (s *Server) serveLogin(w http.ResponseWriter, r *http.Request, cfg Config) {
+	var creds struct {
+		UserID   string `json:"userId"`
+		Password string `json:"password"`
+	}
+	json.NewDecoder(r.Body).Decode(&creds)
+
+	if cfg.FailAuth || (cfg.Username != "" && (creds.UserID != cfg.Username || creds.Password != cfg.Password)) {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"errorCode": "112498", "errorMessage": "Unauthorized User"})
+		return
+	}
+
+	token := cfg.Token
+	if token == "" {
+		token = defaultSessionToken
+	}
+	http.SetCookie(w, &http.Cookie{Name: "access_token", Value: token, Path: "/", HttpOnly: true})
+	writeJSON(w, http.StatusOK, map[string]any{"sessionId": token, "userName": creds.UserID})
+}
+
+// serveTokenConfig emulates creating and revoking service account tokens. Created tokens
+// are unsigned JWTs carrying the requested expiry; the mock accepts them only when no
+// token is configured.
+func (s *Server) serveTokenConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var req struct {
+			User        string `json:"user"`
+			Description string `json:"description"`
+			ValidUntil  string `json:"valid_until"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.User == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": 3, "message": "user is required"})
+			return
+		}
+		validUntil, err := time.Parse(time.RFC3339, req.ValidUntil)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"code": 3, "message": "invalid valid_until"})
+			return
+		}
+
+		id := fmt.Sprintf("mock-%d", time.Now().UnixNano())
+		writeJSON(w, http.StatusOK, map[string]any{
+			"value": map[string]any{
+				"key":         map[string]any{"id": id},
+				"user":        req.User,
+				"description": req.Description,
+				"validUntil":  validUntil.UTC().Format(time.RFC3339),
+				"token":       unsignedJWT(id, req.User, validUntil),
+			},
+			"time": time.Now().UTC().Format(time.RFC3339),
+		})
+	case http.MethodDelete:
+		writeJSON(w, http.StatusOK, map[string]any{
+			"key":  map[string]any{"id": r.URL.Query().Get("key.id")},
+			"time": time.Now().UTC().Format(time.RFC3339),
+		})
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]any{"code": 12, "message": "method not allowed"})
+	}
+}
+
+// checkBearer verifies the CloudVision bearer token
+func checkBearer(r *http.Request, cfg Config) bool {
+	if cfg.FailAuth {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if cfg.Token == "" {
+		return ok && token != ""
+	}
+	return token == cfg.Token
+}
+
+// unsignedJWT builds a JWT with the claims CloudVision puts in service account tokens
+func unsignedJWT(id, user string, expires time.Time) string {
+	header, _ := json.Marshal(map[string]any{"alg": "none", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]any{
+		"jti": id,
+		"dsn": user,
+		"iss": "mock.cloudvision",
+		"iat": time.Now().Unix(),
+		"exp": expires.Unix(),
+	})
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(header) + "." + enc.EncodeToString(claims) + ".mock"
+}
+
+// parseLimit parses the limit query parameter of the resource APIs
+func parseLimit(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid limit %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,190 @@
+package mock
+
+import (
+	"arista_engine/internal/client"
+	"arista_engine/internal/eos"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// eapiFixtures holds outputs of commands the engine runs that have no typed model
+//
+//go:embed fixtures/eapi/*.json
+var eapiFixtures embed.FS
+
+// commandAliases maps commands to the command whose output they share
+var commandAliases = map[string]string{
+	"show system environment power": "show environment power",
+	"show lldp neighbor":            "show lldp neighbors",
+	"show lldp neighbor detail":     "show lldp neighbors detail",
+}
+
+// eAPI error codes returned by the mock, as EOS uses them
+const (
+	errParse          = -32700
+	errInvalidRequest = -32600
+	errMethodNotFound = -32601
+	errCommandFailed  = 1000
+	errInvalidCommand = 1002
+)
+
+// rpcRequest is a runCmds request as sent by clients
+type rpcRequest struct {
+	JSONRPC string               `json:"jsonrpc"`
+	Method  string               `json:"method"`
+	Params  client.RunCmdsParams `json:"params"`
+	ID      any                  `json:"id"`
+}
+
+// serveEAPI answers a JSON-RPC runCmds request
+func (s *Server) serveEAPI(w http.ResponseWriter, r *http.Request, cfg Config) {
+	if !checkBasicAuth(r, cfg) {
+		unauthorized(w)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req rpcRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeRPCError(w, nil, errParse, fmt.Sprintf("Parse error: %v", err), nil)
+		return
+	}
+	if req.Method != "runCmds" {
+		writeRPCError(w, req.ID, errMethodNotFound, fmt.Sprintf("Method not found: %s", req.Method), nil)
+		return
+	}
+	if len(req.Params.Cmds) == 0 {
+		writeRPCError(w, req.ID, errInvalidRequest, "Invalid request: no commands", nil)
+		return
+	}
+
+	results := make([]any, 0, len(req.Params.Cmds))
+	for i, cmd := range req.Params.Cmds {
+		start := time.Now()
+		output, code, cmdErr := s.runCommand(cmd, cfg)
+		if cmdErr != "" {
+			msg := fmt.Sprintf("CLI command %d of %d '%s' failed: %s", i+1, len(req.Params.Cmds), cmd.Cmd, strings.SplitN(cmdErr, " (", 2)[0])
+			if code == errInvalidCommand {
+				msg = fmt.Sprintf("CLI command %d of %d '%s' failed: invalid command", i+1, len(req.Params.Cmds), cmd.Cmd)
+			}
+			data := append(results, map[string]any{"errors": []string{cmdErr}})
+			writeRPCError(w, req.ID, code, msg, data)
+			return
+		}
+
+		if req.Params.Format == "text" {
+			text, _ := json.MarshalIndent(output, "", "  ")
+			output = map[string]any{"output": string(text) + "\n"}
+		}
+		if req.Params.Timestamps {
+			output["_meta"] = map[string]any{
+				"execStartTime": float64(start.UnixNano()) / float64(time.Second),
+				"execDuration":  time.Since(start).Seconds(),
+			}
+		}
+		results = append(results, output)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": results})
+}
+
+// runCommand returns the output of one command, or the eAPI error code and message.
+// Show commands answer from the fixtures; other commands succeed with empty output.
+// Text output is the JSON model, as the mock has no CLI renderer.
+func (s *Server) runCommand(cmd client.Command, cfg Config) (map[string]any, int, string) {
+	name := eos.NormalizeCommand(cmd.Cmd)
+	for _, failing := range cfg.FailCommands {
+		if eos.NormalizeCommand(failing) == name {
+			return nil, errCommandFailed, "Could not run command (mock: injected failure)"
+		}
+	}
+
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return nil, errInvalidCommand, "Incomplete command"
+	}
+	if fields[0] != "show" {
+		return map[string]any{}, 0, ""
+	}
+
+	raw, ok := commandFixture(name, cmd.Revision)
+	if !ok && cmd.Revision > 1 {
+		if _, known := commandFixture(name, 0); known {
+			return nil, errCommandFailed, fmt.Sprintf("Revision %d is not supported (mock: no fixture for this revision)", cmd.Revision)
+		}
+	}
+	if !ok {
+		token := ""
+		if len(fields) > 1 {
+			token = fields[1]
+		}
+		return nil, errInvalidCommand, fmt.Sprintf("Invalid input (at token 1: '%s')", token)
+	}
+	var output map[string]any
+	if err := json.Unmarshal(raw, &output); err != nil {
+		return nil, errCommandFailed, fmt.Sprintf("Could not run command (mock fixture: %v)", err)
+	}
+	applyIdentity(name, output, cfg)
+	return output, 0, ""
+}
+
+// commandFixture returns the recorded output of a command from the mock's own fixtures
+// or the EOS model fixtures
+func commandFixture(name string, revision int) ([]byte, bool) {
+	if alias, ok := commandAliases[name]; ok {
+		name = alias
+	}
+	file := "fixtures/eapi/" + strings.ReplaceAll(name, " ", "_") + ".json"
+	if raw, err := eapiFixtures.ReadFile(file); err == nil {
+		return raw, true
+	}
+	return eos.Fixture(name, revision)
+}
+
+// applyIdentity replaces the fixture hostname and serial number with the configured ones,
+// so several mock devices can be told apart
+func applyIdentity(name string, output map[string]any, cfg Config) {
+	switch name {
+	case "show version":
+		if cfg.SerialNumber != "" {
+			output["serialNumber"] = cfg.SerialNumber
+		}
+	case "show hostname":
+		if cfg.Hostname != "" {
+			output["hostname"] = cfg.Hostname
+			output["fqdn"] = cfg.Hostname + ".lab.local"
+		}
+	case "show inventory":
+		if info, ok := output["systemInformation"].(map[string]any); ok && cfg.SerialNumber != "" {
+			info["serialNum"] = cfg.SerialNumber
+		}
+	case "show lldp local-info":
+		if cfg.Hostname == "" {
+			return
+		}
+		if vrfs, ok := output["localInfo"].(map[string]any); ok {
+			for _, v := range vrfs {
+				if info, ok := v.(map[string]any); ok {
+					info["systemName"] = cfg.Hostname + ".lab.local"
+				}
+			}
+		}
+	}
+}
+
+// writeRPCError writes a JSON-RPC error; eAPI answers errors with HTTP 200
+func writeRPCError(w http.ResponseWriter, id any, code int, message string, data []any) {
+	rpcErr := map[string]any{"code": code, "message": message}
+	if data != nil {
+		rpcErr["data"] = data
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"jsonrpc": "2.0", "id": id, "error": rpcErr})
+}
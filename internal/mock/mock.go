@@ -0,0 +1,196 @@
+// Package mock serves an offline Arista device and CloudVision cluster: eAPI runCmds
+// from the EOS model fixtures, the /vRest EOS REST paths from the documented example
+// data, and CloudVision resource APIs from recorded responses. Latency, authentication
+// failures and errors can be injected so the engine can be exercised without hardware.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config controls the identity of the mock device and the faults it injects
+type Config struct {
+	Username     string   `json:"username"` // eAPI and /vRest basic auth; empty accepts any
+	Password     string   `json:"password"`
+	Token        string   `json:"token"`        // CloudVision bearer token; empty accepts any
+	Hostname     string   `json:"hostname"`     // overrides the fixture hostname
+	SerialNumber string   `json:"serialNumber"` // overrides the fixture serial number
+	LatencyMs    int      `json:"latencyMs"`    // added to every response
+	JitterMs     int      `json:"jitterMs"`     // random extra latency up to this value
+	ErrorRate    float64  `json:"errorRate"`    // fraction of requests answered with HTTP 503
+	FailAuth     bool     `json:"failAuth"`     // reject every credential
+	FailCommands []string `json:"failCommands"` // commands answered with an eAPI error
+}
+
+// Server is an http.Handler emulating an EOS device and a CloudVision cluster
+type Server struct {
+	mu  sync.RWMutex
+	cfg Config
+	rng *rand.Rand
+
+	rest     []RESTOperation
+	cvRoutes []cvRoute
+	requests atomic.Int64
+}
+
+// NewServer creates a mock server. restDoc is the path of the EOS REST API reference
+// (Enumerated_API.md) whose example data answers /vRest requests; empty disables /vRest.
+func NewServer(cfg Config, restDoc string) (*Server, error) {
+	s := &Server{
+		cfg: cfg,
+		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	routes, err := loadCloudVisionRoutes()
+	if err != nil {
+		return nil, err
+	}
+	s.cvRoutes = routes
+
+	if restDoc != "" {
+		ops, err := LoadRESTExamples(restDoc)
+		if err != nil {
+			return nil, err
+		}
+		s.rest = ops
+	}
+	return s, nil
+}
+
+// Config returns the current configuration
+func (s *Server) Config() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// SetConfig replaces the configuration; it applies to the next request
+func (s *Server) SetConfig(cfg Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+// Requests returns the number of requests served, including rejected ones
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// RESTOperations returns the number of /vRest operations with example data
+func (s *Server) RESTOperations() int {
+	return len(s.rest)
+}
+
+// ServeHTTP routes a request to the eAPI, EOS REST or CloudVision emulation
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/mock/") {
+		s.serveControl(w, r)
+		return
+	}
+	s.requests.Add(1)
+	cfg := s.Config()
+
+	if err := s.delay(r, cfg); err != nil {
+		return
+	}
+	if cfg.ErrorRate > 0 && s.chance() < cfg.ErrorRate {
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "mock: injected failure", http.StatusServiceUnavailable)
+		return
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/command-api" || path == "/command-api/":
+		s.serveEAPI(w, r, cfg)
+	case strings.HasPrefix(path, "/vRest/"):
+		s.serveREST(w, r, cfg)
+	case strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/cvpservice/"):
+		s.serveCloudVision(w, r, cfg)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveControl reads or replaces the configuration at /mock/config, so faults can be
+// switched on and off while a demo or test is running
+func (s *Server) serveControl(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/mock/config" {
+		http.NotFound(w, r)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Config())
+	case http.MethodPut, http.MethodPost:
+		var cfg Config
+		if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+			http.Error(w, fmt.Sprintf("invalid config: %v", err), http.StatusBadRequest)
+			return
+		}
+		s.SetConfig(cfg)
+		writeJSON(w, http.StatusOK, cfg)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// delay waits for the configured latency, returning early when the client goes away
+func (s *Server) delay(r *http.Request, cfg Config) error {
+	d := time.Duration(cfg.LatencyMs) * time.Millisecond
+	if cfg.JitterMs > 0 {
+		d += time.Duration(s.chance() * float64(cfg.JitterMs) * float64(time.Millisecond))
+	}
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-r.Context().Done():
+		return r.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// chance returns a random number in [0, 1)
+func (s *Server) chance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+// checkBasicAuth verifies eAPI and /vRest credentials
+func checkBasicAuth(r *http.Request, cfg Config) bool {
+	if cfg.FailAuth {
+		return false
+	}
+	if cfg.Username == "" {
+		return true
+	}
+	user, pass, ok := r.BasicAuth()
+	return ok && user == cfg.Username && pass == cfg.Password
+}
+
+// unauthorized answers like the EOS web server does, with an HTML page
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Basic realm="EOS"`)
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(http.StatusUnauthorized)
+	fmt.Fprint(w, "<html><head><title>401 Unauthorized</title></head><body><h1>Unauthorized</h1></body></html>")
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,351 @@
+package mock_test
+
+import (
+	"arista_engine/internal/client"
+	"arista_engine/internal/core"
+	"arista_engine/internal/eos"
+	"arista_engine/internal/mock"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder keeps the path and body of the requests reaching the mock
+type recorder struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies [][]byte
+}
+
+func (r *recorder) last() (string, map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var body map[string]any
+	json.Unmarshal(r.bodies[len(r.bodies)-1], &body)
+	return r.paths[len(r.paths)-1], body
+}
+
+// newMock serves a mock device and CloudVision cluster over httptest
+func newMock(t *testing.T, cfg mock.Config) (*mock.Server, string, *recorder) {
+	t.Helper()
+	srv, err := mock.NewServer(cfg, "")
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	rec := &recorder{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.paths = append(rec.paths, r.URL.Path)
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		srv.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	return srv, ts.URL, rec
+}
+
+var deviceConfig = mock.Config{Username: "admin", Password: "arista", SerialNumber: "MOCK0001"}
+
+func TestEAPIRunCmds(t *testing.T) {
+	_, url, rec := newMock(t, deviceConfig)
+	c := client.NewEAPIClient(false, 5*time.Second)
+
+	params := client.RunCmdsParams{Version: 1, Cmds: client.Commands("show version", "show ip bgp summary"), Format: "json"}
+	rpc, resp, _, err := c.RunCmds(context.Background(), url, "admin", "arista", params)
+	if err != nil {
+		t.Fatalf("RunCmds: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || len(rpc.Result) != 2 {
+		t.Fatalf("status %d, %d results", resp.StatusCode, len(rpc.Result))
+	}
+
+	// The base URL is posted to /command-api exactly once
+	path, body := rec.last()
+	if path != "/command-api" {
+		t.Errorf("posted to %q, want /command-api", path)
+	}
+	if body["method"] != "runCmds" || body["id"] != rpc.ID {
+		t.Errorf("request = %v, response id %q", body, rpc.ID)
+	}
+
+	version, err := eos.As[eos.ShowVersion](rpc.Result[0])
+	if err != nil || version.SerialNumber != "MOCK0001" || version.Version != "4.30.1F" {
+		t.Errorf("show version = %+v, %v", version, err)
+	}
+	bgp, err := eos.As[eos.ShowIPBGPSummary](rpc.Result[1])
+	if err != nil || !bgp.VRFs["default"].Peers["10.0.1.0"].Established() {
+		t.Errorf("show ip bgp summary = %+v, %v", bgp, err)
+	}
+
+	results, failed := rpc.Commands(params)
+	if failed != -1 || results[0].Status != core.CommandOK || results[1].Status != core.CommandOK {
+		t.Errorf("Commands = %+v, failed %d", results, failed)
+	}
+
+	// A trailing slash on the base URL does not change the target
+	if _, _, _, err := c.RunCmds(context.Background(), url+"/", "admin", "arista", params); err != nil {
+		t.Errorf("RunCmds with trailing slash: %v", err)
+	}
+	if path, _ := rec.last(); path != "/command-api" {
+		t.Errorf("posted to %q, want /command-api", path)
+	}
+}
+
+func TestEAPIBatchErrors(t *testing.T) {
+	srv, url, _ := newMock(t, deviceConfig)
+	c := client.NewEAPIClient(false, 5*time.Second)
+
+	params := client.RunCmdsParams{Version: 1, Cmds: client.Commands("show version", "show bogus", "show vlan"), Format: "json"}
+	rpc, _, _, err := c.RunCmds(context.Background(), url, "admin", "arista", params)
+	var rpcErr *client.JSONRPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("RunCmds error = %v, want a JSONRPCError", err)
+	}
+	if rpcErr.Code != 1002 || !strings.Contains(rpcErr.Message, "CLI command 2 of 3 'show bogus' failed") {
+		t.Errorf("error = %d %q", rpcErr.Code, rpcErr.Message)
+	}
+
+	// The commands before the failure keep their output; those after it are skipped
+	results, failed := rpc.Commands(params)
+	if failed != 1 {
+		t.Fatalf("failed index = %d, want 1", failed)
+	}
+	want := []string{core.CommandOK, core.CommandFailed, core.CommandSkipped}
+	for i, res := range results {
+		if res.Status != want[i] {
+			t.Errorf("command %d status = %s, want %s", i, res.Status, want[i])
+		}
+	}
+	if results[0].Output == nil || len(results[1].Errors) != 1 || !strings.Contains(results[1].Errors[0], "Invalid input") {
+		t.Errorf("results = %+v", results)
+	}
+
+	// Injected failures use the generic command error
+	srv.SetConfig(mock.Config{Username: "admin", Password: "arista", FailCommands: []string{"show vlan"}})
+	_, _, _, err = c.RunCmds(context.Background(), url, "admin", "arista", client.RunCmdsParams{Version: 1, Cmds: client.Commands("show vlan")})
+	if !errors.As(err, &rpcErr) || rpcErr.Code != 1000 {
+		t.Errorf("injected failure = %v", err)
+	}
+
+	// A revision the device does not have fails the command
+	pinned := client.RunCmdsParams{Version: 1, Cmds: []client.Command{{Cmd: "show version", Revision: 3}}}
+	if _, _, _, err := c.RunCmds(context.Background(), url, "admin", "arista", pinned); !errors.As(err, &rpcErr) || !strings.Contains(rpcErr.Message, "Revision 3 is not supported") {
+		t.Errorf("pinned revision = %v", err)
+	}
+}
+
+func TestEAPIVersionLatest(t *testing.T) {
+	_, url, rec := newMock(t, deviceConfig)
+	c := client.NewEAPIClient(false, 5*time.Second)
+
+	params := client.RunCmdsParams{
+		Version:    client.VersionLatest,
+		Cmds:       []client.Command{{Cmd: "show version"}, {Cmd: "enable", Input: "secret"}},
+		Timestamps: true,
+	}
+	rpc, _, _, err := c.RunCmds(context.Background(), url, "admin", "arista", params)
+	if err != nil {
+		t.Fatalf("RunCmds: %v", err)
+	}
+
+	_, body := rec.last()
+	reqParams := body["params"].(map[string]any)
+	if reqParams["version"] != "latest" {
+		t.Errorf("version sent as %#v, want \"latest\"", reqParams["version"])
+	}
+	cmds := reqParams["cmds"].([]any)
+	if cmds[0] != "show version" {
+		t.Errorf("plain command sent as %#v", cmds[0])
+	}
+	if cmd, ok := cmds[1].(map[string]any); !ok || cmd["cmd"] != "enable" || cmd["input"] != "secret" {
+		t.Errorf("command with input sent as %#v", cmds[1])
+	}
+
+	results, _ := rpc.Commands(params)
+	if results[0].StartedAt == nil || results[0].StartedAt.IsZero() {
+		t.Error("timestamps were not read from the output")
+	}
+	if _, err := eos.As[eos.ShowVersion](rpc.Result[0]); err != nil {
+		t.Errorf("show version: %v", err)
+	}
+}
+
+func TestEAPIFailures(t *testing.T) {
+	srv, url, _ := newMock(t, deviceConfig)
+	c := client.NewEAPIClient(false, 5*time.Second)
+	params := client.RunCmdsParams{Version: 1, Cmds: client.Commands("show version")}
+
+	// EOS rejects bad credentials with an HTML page
+	if _, resp, _, err := c.RunCmds(context.Background(), url, "admin", "wrong", params); err == nil || err.Error() != "HTTP 401 Unauthorized" || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("bad credentials = %v", err)
+	}
+
+	srv.SetConfig(mock.Config{ErrorRate: 1})
+	if _, _, _, err := c.RunCmds(context.Background(), url, "", "", params); err == nil || err.Error() != "HTTP 503 Service Unavailable" {
+		t.Errorf("injected 503 = %v", err)
+	}
+
+	// Responses must answer the request they were read for
+	stale := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": "ae-other-1", "result": []any{map[string]any{}}})
+	}))
+	defer stale.Close()
+	if _, _, _, err := c.RunCmds(context.Background(), stale.URL, "", "", params); err == nil || !strings.Contains(err.Error(), "does not match request") {
+		t.Errorf("mismatched response ID = %v", err)
+	}
+}
+
+func TestEAPIShowVersion(t *testing.T) {
+	_, url, _ := newMock(t, deviceConfig)
+	c := client.NewEAPIClient(false, 5*time.Second)
+
+	meta, status, _, err := c.ShowVersion(context.Background(), url, "admin", "arista")
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("ShowVersion = %d, %v", status, err)
+	}
+	if meta.Serial != "MOCK0001" || meta.Model != "DCS-7050SX3-48YC8" || meta.Version != "4.30.1F" {
+		t.Errorf("metadata = %+v", meta)
+	}
+
+	if ok, msg, _, err := c.TestConnection(context.Background(), url, "admin", "arista"); !ok || err != nil {
+		t.Errorf("TestConnection = %v, %q, %v", ok, msg, err)
+	}
+}
+
+// jwt builds an unsigned token expiring at exp
+func jwt(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	claims, _ := json.Marshal(map[string]any{"jti": "t1", "dsn": "svc", "exp": exp.Unix()})
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString(claims) + ".sig"
+}
+
+var clusterConfig = mock.Config{Username: "cvpadmin", Password: "cvp123", Token: "cv-token"}
+
+func TestCloudVisionLogin(t *testing.T) {
+	_, url, _ := newMock(t, clusterConfig)
+	c := client.NewCloudVisionClient(false, 5*time.Second)
+
+	token, err := c.Login(context.Background(), url, "cvpadmin", "cvp123")
+	if err != nil || token != "cv-token" {
+		t.Errorf("Login = %q, %v", token, err)
+	}
+	if _, err := c.Login(context.Background(), url, "cvpadmin", "wrong"); err == nil || !strings.Contains(err.Error(), "login rejected") {
+		t.Errorf("Login with a bad password = %v", err)
+	}
+	if _, err := c.Login(context.Background(), url, "", ""); err == nil {
+		t.Error("Login without credentials: expected an error")
+	}
+}
+
+func TestCloudVisionTestConnection(t *testing.T) {
+	srv, url, _ := newMock(t, clusterConfig)
+	c := client.NewCloudVisionClient(false, 5*time.Second)
+
+	tests := []struct {
+		name   string
+		token  string
+		ok     bool
+		status int
+		msg    string
+	}{
+		{"valid", "cv-token", true, http.StatusOK, "Connection successful"},
+		{"invalid", "other-token", false, http.StatusUnauthorized, "Token rejected: invalid or revoked (HTTP 401)"},
+		{"expired", jwt(time.Now().Add(-time.Hour)), false, http.StatusUnauthorized, "Token expired on"},
+		{"missing", "", false, 0, "No API token configured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, msg, status, _, err := c.TestConnection(context.Background(), url, tt.token)
+			if err != nil || ok != tt.ok || status != tt.status || !strings.HasPrefix(msg, tt.msg) {
+				t.Errorf("TestConnection = %v, %q, %d, %v", ok, msg, status, err)
+			}
+		})
+	}
+
+	// Tokens close to expiry still work but carry a warning
+	srv.SetConfig(mock.Config{})
+	ok, msg, _, _, err := c.TestConnection(context.Background(), url, jwt(time.Now().Add(72*time.Hour)))
+	if !ok || err != nil || !strings.Contains(msg, "but token expires in") {
+		t.Errorf("expiring token = %v, %q, %v", ok, msg, err)
+	}
+}
+
+func TestCloudVisionInventory(t *testing.T) {
+	_, url, _ := newMock(t, clusterConfig)
+	c := client.NewCloudVisionClient(false, 5*time.Second)
+
+	devices, err := c.ListDevices(context.Background(), url, "cv-token")
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if len(devices) != 3 {
+		t.Fatalf("got %d devices, want 3", len(devices))
+	}
+	want := core.CVDevice{
+		Serial:          "SSJ17115999",
+		Hostname:        "spine1",
+		FQDN:            "spine1.lab.local",
+		Model:           "DCS-7280SR3-48YC8",
+		Version:         "4.30.1F",
+		MAC:             "00:1c:73:01:00:01",
+		StreamingStatus: "inactive",
+		ManagementIP:    "192.168.0.1",
+	}
+	if devices[2] != want {
+		t.Errorf("spine1 = %+v, want %+v", devices[2], want)
+	}
+	if devices[0].StreamingStatus != "active" || devices[0].ManagementIP != "192.168.0.11" {
+		t.Errorf("leaf1 = %+v", devices[0])
+	}
+
+	meta, err := c.GetVersion(context.Background(), url, "cv-token")
+	if err != nil || meta.Version != "2023.3.1" || meta.Model != "CloudVision" {
+		t.Errorf("GetVersion = %+v, %v", meta, err)
+	}
+
+	if _, err := c.ListDevices(context.Background(), url, "wrong"); err == nil || !strings.Contains(err.Error(), "HTTP 401") {
+		t.Errorf("ListDevices with a bad token = %v", err)
+	}
+}
+
+func TestCloudVisionServiceAccountTokens(t *testing.T) {
+	_, url, _ := newMock(t, clusterConfig)
+	c := client.NewCloudVisionClient(false, 5*time.Second)
+
+	validUntil := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	token, err := c.CreateServiceAccountToken(context.Background(), url, "cv-token", "arista-engine", "automation", validUntil)
+	if err != nil {
+		t.Fatalf("CreateServiceAccountToken: %v", err)
+	}
+	if token.ID == "" || token.User != "arista-engine" || !token.ValidUntil.Equal(validUntil) {
+		t.Errorf("token = %+v", token)
+	}
+	info, err := client.ParseToken(token.Token, time.Now())
+	if err != nil || info.Subject != "arista-engine" || !info.ExpiresAt.Equal(validUntil) {
+		t.Errorf("ParseToken = %+v, %v", info, err)
+	}
+
+	tokens, err := c.ListServiceAccountTokens(context.Background(), url, "cv-token")
+	if err != nil || len(tokens) != 1 || tokens[0].User != "arista-engine" || tokens[0].ValidUntil.IsZero() {
+		t.Errorf("ListServiceAccountTokens = %+v, %v", tokens, err)
+	}
+
+	if err := c.DeleteServiceAccountToken(context.Background(), url, "cv-token", token.ID); err != nil {
+		t.Errorf("DeleteServiceAccountToken: %v", err)
+	}
+
+	if _, err := c.CreateServiceAccountToken(context.Background(), url, "cv-token", "", "", validUntil); err == nil || !strings.Contains(err.Error(), "user is required") {
+		t.Errorf("CreateServiceAccountToken without a user = %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package mock
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// RESTOperation is one documented EOS REST operation with its example response
+type RESTOperation struct {
+	Method   string
+	Path     string   // e.g. /aaa-tacacs/{name}, relative to /vRest
+	segments []string // path split on "/", with "{...}" matching any segment
+	Example  json.RawMessage
+}
+
+// restOperationLine matches an operation heading of the API reference, e.g. "get /aaa-tacacs/{name}"
+var restOperationLine = regexp.MustCompile(`^(get|post|put|delete|patch) (/\S*)$`)
+
+// LoadRESTExamples reads the EOS REST API reference and returns every operation that
+// documents example data. Each example follows an "Example data" line and a
+// Content-Type line, and runs until the "Produces" section.
+func LoadRESTExamples(path string) ([]RESTOperation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open REST API reference: %w", err)
+	}
+	defer f.Close()
+
+	var ops []RESTOperation
+	var current *RESTOperation
+	var example []string
+	inExample := false
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case inExample && line == "Produces":
+			inExample = false
+			raw := strings.TrimSpace(strings.Join(example, "\n"))
+			if current != nil && json.Valid([]byte(raw)) {
+				current.Example = json.RawMessage(raw)
+				ops = append(ops, *current)
+			}
+			current = nil
+		case inExample:
+			if !strings.HasPrefix(line, "Content-Type:") {
+				example = append(example, line)
+			}
+		case line == "Example data":
+			inExample = true
+			example = example[:0]
+		default:
+			if m := restOperationLine.FindStringSubmatch(line); m != nil {
+				current = &RESTOperation{
+					Method:   strings.ToUpper(m[1]),
+					Path:     m[2],
+					segments: strings.Split(strings.Trim(m[2], "/"), "/"),
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read REST API reference: %w", err)
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("no operations with example data in %s", path)
+	}
+	return ops, nil
+}
+
+// matches reports whether a request path, relative to /vRest, matches the operation.
+// Literal segments count for more than parameters, so /aaa-tacacs/status wins over
+// /aaa-tacacs/{name}; the returned score ranks candidates.
+func (op RESTOperation) matches(method string, segments []string) (int, bool) {
+	if op.Method != method || len(op.segments) != len(segments) {
+		return 0, false
+	}
+	score := 0
+	for i, seg := range op.segments {
+		switch {
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+		case seg == segments[i]:
+			score++
+		default:
+			return 0, false
+		}
+	}
+	return score, true
+}
+
+// serveREST answers an EOS REST request with the example data of the matching operation
+func (s *Server) serveREST(w http.ResponseWriter, r *http.Request, cfg Config) {
+	if !checkBasicAuth(r, cfg) {
+		unauthorized(w)
+		return
+	}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/vRest"), "/"), "/")
+	best, bestScore := -1, -1
+	for i, op := range s.rest {
+		if score, ok := op.matches(r.Method, segments); ok && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 {
+		writeJSON(w, http.StatusNotFound, map[string]any{
+			"status": "Failure",
+			"result": []map[string]any{{"code": 404, "message": "no such operation: " + r.Method + " " + r.URL.Path, "status": "Failure"}},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(s.rest[best].Example)
+}
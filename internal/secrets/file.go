@@ -0,0 +1,201 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase of the local secrets file
+const PassphraseEnv = "ARISTA_ENGINE_SECRETS_PASSPHRASE"
+
+// fileFormat is the on-disk layout of the encrypted secrets file
+type fileFormat struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// FileProvider resolves "file:<name>" from a local file of named secrets encrypted
+// with AES-256-GCM under a key derived (scrypt) from a passphrase
+type FileProvider struct {
+	path       string
+	passphrase string
+
+	mu sync.Mutex
+}
+
+// NewFileProvider creates a file provider for path, taking the passphrase from PassphraseEnv
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path, passphrase: os.Getenv(PassphraseEnv)}
+}
+
+// Scheme returns "file"
+func (p *FileProvider) Scheme() string { return "file" }
+
+// Resolve returns the named secret
+func (p *FileProvider) Resolve(_ context.Context, name string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries, _, err := p.load()
+	if err != nil {
+		return "", err
+	}
+	value, ok := entries[name]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found in %s", name, p.path)
+	}
+	return value, nil
+}
+
+// Set stores a named secret, creating the file if needed
+func (p *FileProvider) Set(name, value string) error {
+	if name == "" {
+		return errors.New("secret name is required")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries, salt, err := p.load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if entries == nil {
+		entries = make(map[string]string)
+	}
+	entries[name] = value
+	return p.save(entries, salt)
+}
+
+// Delete removes a named secret
+func (p *FileProvider) Delete(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries, salt, err := p.load()
+	if err != nil {
+		return err
+	}
+	delete(entries, name)
+	return p.save(entries, salt)
+}
+
+// Names lists the stored secret names
+func (p *FileProvider) Names() ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries, _, err := p.load()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// load decrypts the secrets file and returns its entries and salt
+func (p *FileProvider) load() (map[string]string, []byte, error) {
+	raw, err := os.ReadFile(p.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var f fileFormat
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+	if f.Version != 1 {
+		return nil, nil, fmt.Errorf("unsupported secrets file version %d", f.Version)
+	}
+
+	aead, err := p.cipher(f.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := aead.Open(nil, f.Nonce, f.Data, nil)
+	if err != nil {
+		return nil, nil, errors.New("failed to decrypt secrets file: wrong passphrase or corrupted file")
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse secrets: %w", err)
+	}
+	return entries, f.Salt, nil
+}
+
+// save encrypts and writes the secrets file, generating a salt for a new file
+func (p *FileProvider) save(entries map[string]string, salt []byte) error {
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	aead, err := p.cipher(salt)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	raw, err := json.Marshal(fileFormat{
+		Version: 1,
+		Salt:    salt,
+		Nonce:   nonce,
+		Data:    aead.Seal(nil, nonce, plain, nil),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal secrets file: %w", err)
+	}
+
+	// Write to a temporary file first so a failed write never truncates the secrets
+	tmp := p.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write secrets file: %w", err)
+	}
+	return os.Rename(tmp, p.path)
+}
+
+// cipher derives the file key from the passphrase and salt
+func (p *FileProvider) cipher(salt []byte) (cipher.AEAD, error) {
+	if p.passphrase == "" {
+		return nil, fmt.Errorf("%s is not set", PassphraseEnv)
+	}
+
+	key, err := scrypt.Key([]byte(p.passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,186 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// EnvProvider resolves "env:NAME" from environment variables
+type EnvProvider struct{}
+
+// Scheme returns "env"
+func (EnvProvider) Scheme() string { return "env" }
+
+// Resolve returns the value of the environment variable
+func (EnvProvider) Resolve(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+// ExecProvider resolves "exec:<command> <args...>" by running the command and taking the
+// first line of its output, as printed by password managers such as pass or gopass.
+// Only commands in the allow list can run; the command is executed without a shell.
+type ExecProvider struct {
+	allowed map[string]bool
+}
+
+// DefaultExecCommands are the password manager commands the exec provider allows by default
+var DefaultExecCommands = []string{"pass", "gopass", "op", "secret-tool", "security", "bw"}
+
+// NewExecProvider creates an exec provider allowing the named commands
+func NewExecProvider(allowed ...string) *ExecProvider {
+	p := &ExecProvider{allowed: make(map[string]bool)}
+	for _, cmd := range allowed {
+		p.allowed[cmd] = true
+	}
+	return p
+}
+
+// Scheme returns "exec"
+func (p *ExecProvider) Scheme() string { return "exec" }
+
+// Resolve runs the command and returns the first line of its output
+func (p *ExecProvider) Resolve(ctx context.Context, command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+	name := strings.TrimSuffix(filepath.Base(args[0]), ".exe")
+	if !p.allowed[name] {
+		return "", fmt.Errorf("command %s is not allowed for secrets", args[0])
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	line, err := bufio.NewReader(bytes.NewReader(out)).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("%s produced no output", args[0])
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// VaultProvider resolves "vault:<mount>/<path>#<field>" from a HashiCorp Vault KV
+// secrets engine over HTTP. KV version 2 is tried first, then version 1.
+type VaultProvider struct {
+	addr      string
+	token     string
+	namespace string
+	http      *http.Client
+}
+
+// NewVaultProvider creates a Vault provider from VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE
+func NewVaultProvider() *VaultProvider {
+	return &VaultProvider{
+		addr:      strings.TrimRight(os.Getenv("VAULT_ADDR"), "/"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		http:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Scheme returns "vault"
+func (p *VaultProvider) Scheme() string { return "vault" }
+
+// Resolve reads the field of a KV secret
+func (p *VaultProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	if p.addr == "" || p.token == "" {
+		return "", errors.New("VAULT_ADDR and VAULT_TOKEN must be set")
+	}
+
+	secretPath, field, ok := strings.Cut(ref, "#")
+	if !ok || field == "" {
+		return "", fmt.Errorf("vault reference %q needs a #field", ref)
+	}
+	mount, path, ok := strings.Cut(strings.Trim(secretPath, "/"), "/")
+	if !ok || path == "" {
+		return "", fmt.Errorf("vault reference %q needs <mount>/<path>", ref)
+	}
+
+	// KV v2 nests the secret under data.data
+	data, status, err := p.read(ctx, mount+"/data/"+path)
+	if err != nil {
+		return "", err
+	}
+	if status == http.StatusOK {
+		if inner, ok := data["data"].(map[string]any); ok {
+			return vaultField(inner, field, ref)
+		}
+	}
+
+	if status == http.StatusNotFound || status == http.StatusForbidden {
+		data, status, err = p.read(ctx, mount+"/"+path)
+		if err != nil {
+			return "", err
+		}
+		if status == http.StatusOK {
+			return vaultField(data, field, ref)
+		}
+	}
+	return "", fmt.Errorf("vault returned HTTP %d for %s", status, secretPath)
+}
+
+// read fetches a Vault path and returns its "data" object and the HTTP status
+func (p *VaultProvider) read(ctx context.Context, path string) (map[string]any, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.addr+"/v1/"+(&url.URL{Path: path}).EscapedPath(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.http.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, nil
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return body.Data, resp.StatusCode, nil
+}
+
+// vaultField extracts a string field from secret data
+func vaultField(data map[string]any, field, ref string) (string, error) {
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found in vault secret %s", field, ref)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Sprint(value), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,126 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long resolved secrets are reused before the provider is asked again
+const DefaultCacheTTL = 5 * time.Minute
+
+// resolveTimeout bounds a single provider lookup
+const resolveTimeout = 15 * time.Second
+
+// Provider resolves secret references of one scheme, e.g. "vault:kv/net/eapi#password"
+type Provider interface {
+	// Scheme returns the reference prefix handled by the provider, without the colon
+	Scheme() string
+	// Resolve returns the secret for the part of the reference after the scheme
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// cached is a resolved secret and its expiry
+type cached struct {
+	value   string
+	expires time.Time
+}
+
+// Registry dispatches secret references to the provider registered for their scheme
+type Registry struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	providers map[string]Provider
+	cache     map[string]cached
+}
+
+// NewRegistry creates a registry with the given providers
+func NewRegistry(ttl time.Duration, providers ...Provider) *Registry {
+	r := &Registry{
+		ttl:       ttl,
+		providers: make(map[string]Provider),
+		cache:     make(map[string]cached),
+	}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds or replaces the provider for its scheme
+func (r *Registry) Register(p Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[p.Scheme()] = p
+}
+
+// Schemes returns the registered schemes
+func (r *Registry) Schemes() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var schemes []string
+	for s := range r.providers {
+		schemes = append(schemes, s)
+	}
+	return schemes
+}
+
+// IsReference reports whether a value is a reference to a registered provider
+func (r *Registry) IsReference(value string) bool {
+	_, _, ok := r.provider(value)
+	return ok
+}
+
+// Resolve returns the secret a value refers to. Values that are not references to a
+// registered provider are returned unchanged, so plain passwords keep working.
+func (r *Registry) Resolve(ctx context.Context, value string) (string, error) {
+	p, ref, ok := r.provider(value)
+	if !ok {
+		return value, nil
+	}
+
+	r.mu.Lock()
+	if c, ok := r.cache[value]; ok && time.Now().Before(c.expires) {
+		r.mu.Unlock()
+		return c.value, nil
+	}
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, resolveTimeout)
+	defer cancel()
+
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s secret: %w", p.Scheme(), err)
+	}
+
+	if r.ttl > 0 {
+		r.mu.Lock()
+		r.cache[value] = cached{value: secret, expires: time.Now().Add(r.ttl)}
+		r.mu.Unlock()
+	}
+	return secret, nil
+}
+
+// Invalidate drops every cached secret, e.g. after a rotation
+func (r *Registry) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache = make(map[string]cached)
+}
+
+// provider finds the provider for a reference and returns the part after the scheme
+func (r *Registry) provider(value string) (Provider, string, bool) {
+	scheme, ref, ok := strings.Cut(value, ":")
+	if !ok || ref == "" {
+		return nil, "", false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.providers[scheme]
+	return p, ref, ok
+}
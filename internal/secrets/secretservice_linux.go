@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// SecretServiceProvider resolves "secretservice:<attr>=<value>[,<attr>=<value>...]" from the
+// freedesktop Secret Service (GNOME Keyring, KWallet) over the session D-Bus, e.g.
+// "secretservice:service=arista,user=admin". The first unlocked matching item is used.
+type SecretServiceProvider struct{}
+
+// NewSecretServiceProvider creates a Secret Service provider
+func NewSecretServiceProvider() *SecretServiceProvider {
+	return &SecretServiceProvider{}
+}
+
+// Scheme returns "secretservice"
+func (p *SecretServiceProvider) Scheme() string { return "secretservice" }
+
+// secret mirrors the Secret Service (oayays) secret struct
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// Resolve searches the default collection for items matching the attributes
+func (p *SecretServiceProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	attrs := make(map[string]string)
+	for _, pair := range strings.Split(ref, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return "", fmt.Errorf("invalid secret service attribute %q, expected key=value", pair)
+		}
+		attrs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	conn, err := dbus.ConnectSessionBus(dbus.WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+	defer conn.Close()
+
+	service := conn.Object("org.freedesktop.secrets", "/org/freedesktop/secrets")
+
+	var unlocked, locked []dbus.ObjectPath
+	if err := service.CallWithContext(ctx, "org.freedesktop.Secret.Service.SearchItems", 0, attrs).Store(&unlocked, &locked); err != nil {
+		return "", fmt.Errorf("failed to search secret service: %w", err)
+	}
+	if len(unlocked) == 0 {
+		if len(locked) > 0 {
+			return "", errors.New("matching item is locked; unlock the keyring first")
+		}
+		return "", fmt.Errorf("no secret service item matches %s", ref)
+	}
+
+	var output dbus.Variant
+	var session dbus.ObjectPath
+	if err := service.CallWithContext(ctx, "org.freedesktop.Secret.Service.OpenSession", 0, "plain", dbus.MakeVariant("")).Store(&output, &session); err != nil {
+		return "", fmt.Errorf("failed to open secret service session: %w", err)
+	}
+	defer conn.Object("org.freedesktop.secrets", session).CallWithContext(ctx, "org.freedesktop.Secret.Session.Close", 0)
+
+	var s secret
+	item := conn.Object("org.freedesktop.secrets", unlocked[0])
+	if err := item.CallWithContext(ctx, "org.freedesktop.Secret.Item.GetSecret", 0, session).Store(&s); err != nil {
+		return "", fmt.Errorf("failed to read secret: %w", err)
+	}
+	return string(s.Value), nil
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package secrets
+
+import (
+	"context"
+	"errors"
+)
+
+// SecretServiceProvider is only available on Linux, where the freedesktop Secret Service runs
+type SecretServiceProvider struct{}
+
+// NewSecretServiceProvider creates a Secret Service provider
+func NewSecretServiceProvider() *SecretServiceProvider {
+	return &SecretServiceProvider{}
+}
+
+// Scheme returns "secretservice"
+func (p *SecretServiceProvider) Scheme() string { return "secretservice" }
+
+// Resolve always fails outside Linux
+func (p *SecretServiceProvider) Resolve(_ context.Context, _ string) (string, error) {
+	return "", errors.New("the Secret Service is only available on Linux")
+}
@@ -59,7 +59,7 @@ func NewStore(dbPath string) (*Store, error) {
 // initBuckets initializes the database buckets
 func (s *Store) initBuckets() error {
 	return s.db.Update(func(tx *bolt.Tx) error {
-		buckets := []string{"endpoints", "query_log", "api_catalog", "device_inventory"}
+		buckets := []string{"endpoints", "query_log", "api_catalog", "device_inventory", "alert_rules", "alert_history", "alert_sinks", "device_test_history", "topology", "discovery_candidates", "credential_profiles"}
 		for _, bucket := range buckets {
 			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
 				return fmt.Errorf("failed to create bucket %s: %w", bucket, err)
@@ -247,6 +247,178 @@ func (s *Store) GetAPICatalog() (*core.APICatalog, error) {
 	return &catalog, err
 }
 
+// SaveCatalogSource stores a catalog source together with the catalog built from it
+func (s *Store) SaveCatalogSource(source core.CatalogSource, catalog *core.APICatalog) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("api_catalog"))
+		sources, err := bucket.CreateBucketIfNotExists([]byte("sources"))
+		if err != nil {
+			return fmt.Errorf("failed to create catalog sources bucket: %w", err)
+		}
+		catalogs, err := bucket.CreateBucketIfNotExists([]byte("source_catalogs"))
+		if err != nil {
+			return fmt.Errorf("failed to create source catalogs bucket: %w", err)
+		}
+
+		data, err := json.Marshal(source)
+		if err != nil {
+			return fmt.Errorf("failed to marshal catalog source: %w", err)
+		}
+		if err := sources.Put([]byte(source.ID), data); err != nil {
+			return err
+		}
+
+		data, err = json.Marshal(catalog)
+		if err != nil {
+			return fmt.Errorf("failed to marshal API catalog: %w", err)
+		}
+		return catalogs.Put([]byte(source.ID), data)
+	})
+}
+
+// GetCatalogSources retrieves all catalog sources
+func (s *Store) GetCatalogSources() ([]core.CatalogSource, error) {
+	var list []core.CatalogSource
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		sources := tx.Bucket([]byte("api_catalog")).Bucket([]byte("sources"))
+		if sources == nil {
+			return nil
+		}
+
+		return sources.ForEach(func(k, v []byte) error {
+			var source core.CatalogSource
+			if err := json.Unmarshal(v, &source); err != nil {
+				return err
+			}
+			list = append(list, source)
+			return nil
+		})
+	})
+
+	return list, err
+}
+
+// GetSourceCatalog retrieves the catalog built from a source
+func (s *Store) GetSourceCatalog(id string) (*core.APICatalog, error) {
+	var catalog core.APICatalog
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		catalogs := tx.Bucket([]byte("api_catalog")).Bucket([]byte("source_catalogs"))
+		if catalogs == nil {
+			return fmt.Errorf("catalog source %s not found", id)
+		}
+
+		data := catalogs.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("catalog source %s not found", id)
+		}
+
+		return json.Unmarshal(data, &catalog)
+	})
+
+	return &catalog, err
+}
+
+// DeleteCatalogSource removes a catalog source and its catalog
+func (s *Store) DeleteCatalogSource(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("api_catalog"))
+		sources := bucket.Bucket([]byte("sources"))
+		if sources == nil || sources.Get([]byte(id)) == nil {
+			return fmt.Errorf("catalog source %s not found", id)
+		}
+		if err := sources.Delete([]byte(id)); err != nil {
+			return err
+		}
+		if catalogs := bucket.Bucket([]byte("source_catalogs")); catalogs != nil {
+			return catalogs.Delete([]byte(id))
+		}
+		return nil
+	})
+}
+
+// SaveCatalogVersion stores a catalog under its source version, replacing an earlier
+// parse of the same version
+func (s *Store) SaveCatalogVersion(catalog *core.APICatalog) error {
+	if catalog.SourceVersion == "" {
+		return fmt.Errorf("catalog has no source version")
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		versions, err := tx.Bucket([]byte("api_catalog")).CreateBucketIfNotExists([]byte("versions"))
+		if err != nil {
+			return fmt.Errorf("failed to create catalog versions bucket: %w", err)
+		}
+
+		data, err := json.Marshal(catalog)
+		if err != nil {
+			return fmt.Errorf("failed to marshal API catalog: %w", err)
+		}
+
+		return versions.Put([]byte(catalog.SourceVersion), data)
+	})
+}
+
+// GetCatalogVersion retrieves a stored catalog version
+func (s *Store) GetCatalogVersion(version string) (*core.APICatalog, error) {
+	var catalog core.APICatalog
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket([]byte("api_catalog")).Bucket([]byte("versions"))
+		if versions == nil {
+			return fmt.Errorf("API catalog version %s not found", version)
+		}
+
+		data := versions.Get([]byte(version))
+		if data == nil {
+			return fmt.Errorf("API catalog version %s not found", version)
+		}
+
+		return json.Unmarshal(data, &catalog)
+	})
+
+	return &catalog, err
+}
+
+// ListCatalogVersions summarizes the stored catalog versions, ordered by version
+func (s *Store) ListCatalogVersions() ([]core.CatalogVersion, error) {
+	var list []core.CatalogVersion
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		versions := tx.Bucket([]byte("api_catalog")).Bucket([]byte("versions"))
+		if versions == nil {
+			return nil
+		}
+
+		return versions.ForEach(func(k, v []byte) error {
+			var catalog core.APICatalog
+			if err := json.Unmarshal(v, &catalog); err != nil {
+				return err
+			}
+			list = append(list, core.CatalogVersion{
+				Version:     string(k),
+				Source:      catalog.Source,
+				Endpoints:   len(catalog.EAPI) + len(catalog.CloudVision) + len(catalog.EOSREST) + len(catalog.Telemetry),
+				LastUpdated: catalog.LastUpdated,
+			})
+			return nil
+		})
+	})
+
+	return list, err
+}
+
+// DeleteCatalogVersion removes a stored catalog version
+func (s *Store) DeleteCatalogVersion(version string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		versions := tx.Bucket([]byte("api_catalog")).Bucket([]byte("versions"))
+		if versions == nil || versions.Get([]byte(version)) == nil {
+			return fmt.Errorf("API catalog version %s not found", version)
+		}
+		return versions.Delete([]byte(version))
+	})
+}
+
 // Device Inventory Methods
 
 // GetDeviceInventory retrieves all device inventory records
@@ -339,7 +511,484 @@ func (s *Store) GetDeviceInventoryByID(id string) (core.DeviceInventory, error)
 	return device, err
 }
 
+// Endpoint/Inventory Transactions
+
+// maxTestHistory caps the number of test results kept per device
+const maxTestHistory = 500
+
+// SaveEndpointWithDevice saves an endpoint and its inventory record in one transaction
+func (s *Store) SaveEndpointWithDevice(endpoint core.Endpoint, device core.DeviceInventory) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := putJSON(tx, "endpoints", endpoint.ID, endpoint); err != nil {
+			return err
+		}
+		return putJSON(tx, "device_inventory", device.ID, device)
+	})
+}
+
+// UpdateEndpointAndDevice loads an endpoint and its inventory record, applies fn and
+// saves both in one transaction. A missing inventory record is passed as a zero value.
+func (s *Store) UpdateEndpointAndDevice(id string, fn func(endpoint *core.Endpoint, device *core.DeviceInventory) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return updateEndpointAndDevice(tx, id, fn)
+	})
+}
+
+// RecordTestResult applies fn to an endpoint and its inventory record and appends the
+// test result to the device's history, all in one transaction
+func (s *Store) RecordTestResult(id string, entry core.TestHistoryEntry, fn func(endpoint *core.Endpoint, device *core.DeviceInventory) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := updateEndpointAndDevice(tx, id, fn); err != nil {
+			return err
+		}
+
+		history, err := tx.Bucket([]byte("device_test_history")).CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return fmt.Errorf("failed to create test history bucket: %w", err)
+		}
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal test history entry: %w", err)
+		}
+		if err := history.Put([]byte(fmt.Sprintf("%020d", entry.Timestamp.UnixNano())), data); err != nil {
+			return err
+		}
+
+		// Trim the oldest entries beyond the cap. Count with a cursor: Stats only
+		// reflects committed pages and misses the entry just added.
+		c := history.Cursor()
+		n := 0
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			n++
+		}
+		for ; n > maxTestHistory; n-- {
+			if k, _ := c.First(); k == nil {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTestHistory retrieves the most recent test results of a device, newest first
+func (s *Store) GetTestHistory(id string, limit int) ([]core.TestHistoryEntry, error) {
+	var entries []core.TestHistoryEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("device_test_history"))
+		if bucket == nil {
+			return fmt.Errorf("device_test_history bucket not found")
+		}
+		history := bucket.Bucket([]byte(id))
+		if history == nil {
+			return nil
+		}
+
+		c := history.Cursor()
+		for k, v := c.Last(); k != nil && (limit <= 0 || len(entries) < limit); k, v = c.Prev() {
+			var entry core.TestHistoryEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+
+	return entries, err
+}
+
+// DeleteEndpointCascade deletes an endpoint together with its inventory record and test history
+func (s *Store) DeleteEndpointCascade(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range []string{"endpoints", "device_inventory"} {
+			bucket := tx.Bucket([]byte(name))
+			if bucket == nil {
+				return fmt.Errorf("%s bucket not found", name)
+			}
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+
+		history := tx.Bucket([]byte("device_test_history"))
+		if history != nil && history.Bucket([]byte(id)) != nil {
+			return history.DeleteBucket([]byte(id))
+		}
+		return nil
+	})
+}
+
+// updateEndpointAndDevice is the transactional body shared by UpdateEndpointAndDevice and RecordTestResult
+func updateEndpointAndDevice(tx *bolt.Tx, id string, fn func(endpoint *core.Endpoint, device *core.DeviceInventory) error) error {
+	endpoints := tx.Bucket([]byte("endpoints"))
+	devices := tx.Bucket([]byte("device_inventory"))
+	if endpoints == nil || devices == nil {
+		return fmt.Errorf("endpoints or device_inventory bucket not found")
+	}
+
+	var endpoint core.Endpoint
+	data := endpoints.Get([]byte(id))
+	if data == nil {
+		return fmt.Errorf("endpoint not found")
+	}
+	if err := json.Unmarshal(data, &endpoint); err != nil {
+		return err
+	}
+
+	var device core.DeviceInventory
+	if data := devices.Get([]byte(id)); data != nil {
+		if err := json.Unmarshal(data, &device); err != nil {
+			return err
+		}
+	}
+
+	if err := fn(&endpoint, &device); err != nil {
+		return err
+	}
+
+	if err := putJSON(tx, "endpoints", id, endpoint); err != nil {
+		return err
+	}
+	return putJSON(tx, "device_inventory", id, device)
+}
+
+// putJSON marshals a value into a bucket
+func putJSON(tx *bolt.Tx, bucketName, key string, value any) error {
+	bucket := tx.Bucket([]byte(bucketName))
+	if bucket == nil {
+		return fmt.Errorf("%s bucket not found", bucketName)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", bucketName, err)
+	}
+
+	return bucket.Put([]byte(key), data)
+}
+
+// Alert Methods
+
+// SaveAlertRule saves an alert rule
+func (s *Store) SaveAlertRule(rule core.AlertRule) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_rules"))
+		if bucket == nil {
+			return fmt.Errorf("alert_rules bucket not found")
+		}
+
+		data, err := json.Marshal(rule)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert rule: %w", err)
+		}
+
+		return bucket.Put([]byte(rule.ID), data)
+	})
+}
+
+// GetAlertRules retrieves all alert rules
+func (s *Store) GetAlertRules() ([]core.AlertRule, error) {
+	var rules []core.AlertRule
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_rules"))
+		if bucket == nil {
+			return fmt.Errorf("alert_rules bucket not found")
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var rule core.AlertRule
+			if err := json.Unmarshal(v, &rule); err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+			return nil
+		})
+	})
+
+	return rules, err
+}
+
+// DeleteAlertRule deletes an alert rule by ID
+func (s *Store) DeleteAlertRule(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_rules"))
+		if bucket == nil {
+			return fmt.Errorf("alert_rules bucket not found")
+		}
+
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// maxAlertHistory caps the number of alert events kept
+const maxAlertHistory = 500
+
+// SaveAlertEvent appends an alert event to the alert history, dropping the oldest
+// events beyond maxAlertHistory
+func (s *Store) SaveAlertEvent(event core.AlertEvent) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_history"))
+		if bucket == nil {
+			return fmt.Errorf("alert_history bucket not found")
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert event: %w", err)
+		}
+
+		// Use timestamp as key for chronological ordering
+		key := fmt.Sprintf("%020d_%s", event.Timestamp.UnixNano(), event.ID)
+		if err := bucket.Put([]byte(key), data); err != nil {
+			return err
+		}
+
+		c := bucket.Cursor()
+		n := 0
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			n++
+		}
+		for ; n > maxAlertHistory; n-- {
+			if k, _ := c.First(); k == nil {
+				break
+			}
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetAlertHistory retrieves alert events newer than since (all events if since is zero)
+func (s *Store) GetAlertHistory(since time.Time) ([]core.AlertEvent, error) {
+	var events []core.AlertEvent
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_history"))
+		if bucket == nil {
+			return fmt.Errorf("alert_history bucket not found")
+		}
+
+		// The zero time has no UnixNano, so start from the first event
+		c := bucket.Cursor()
+		k, v := c.First()
+		if !since.IsZero() {
+			k, v = c.Seek([]byte(fmt.Sprintf("%020d", since.UnixNano())))
+		}
+		for ; k != nil; k, v = c.Next() {
+			var event core.AlertEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+
+	return events, err
+}
+
+// SaveAlertSink saves an alert notification sink
+func (s *Store) SaveAlertSink(sink core.AlertSink) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_sinks"))
+		if bucket == nil {
+			return fmt.Errorf("alert_sinks bucket not found")
+		}
+
+		data, err := json.Marshal(sink)
+		if err != nil {
+			return fmt.Errorf("failed to marshal alert sink: %w", err)
+		}
+
+		return bucket.Put([]byte(sink.ID), data)
+	})
+}
+
+// GetAlertSinks retrieves all alert notification sinks
+func (s *Store) GetAlertSinks() ([]core.AlertSink, error) {
+	var sinks []core.AlertSink
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_sinks"))
+		if bucket == nil {
+			return fmt.Errorf("alert_sinks bucket not found")
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var sink core.AlertSink
+			if err := json.Unmarshal(v, &sink); err != nil {
+				return err
+			}
+			sinks = append(sinks, sink)
+			return nil
+		})
+	})
+
+	return sinks, err
+}
+
+// DeleteAlertSink deletes an alert notification sink by ID
+func (s *Store) DeleteAlertSink(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("alert_sinks"))
+		if bucket == nil {
+			return fmt.Errorf("alert_sinks bucket not found")
+		}
+
+		return bucket.Delete([]byte(id))
+	})
+}
+
 // Close closes the database connection
 func (s *Store) Close() error {
 	return s.db.Close()
 }
+
+// Topology Methods
+
+// SaveTopology saves the latest topology snapshot
+func (s *Store) SaveTopology(topology core.Topology) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, "topology", "latest", topology)
+	})
+}
+
+// GetTopology retrieves the latest topology snapshot, or nil if none was saved
+func (s *Store) GetTopology() (*core.Topology, error) {
+	var topology *core.Topology
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("topology"))
+		if bucket == nil {
+			return fmt.Errorf("topology bucket not found")
+		}
+
+		data := bucket.Get([]byte("latest"))
+		if data == nil {
+			return nil
+		}
+		topology = &core.Topology{}
+		return json.Unmarshal(data, topology)
+	})
+
+	return topology, err
+}
+
+// Discovery Candidate Methods
+
+// SaveDiscoveryCandidates saves proposed endpoints from a discovery walk, replacing earlier proposals for the same IPs
+func (s *Store) SaveDiscoveryCandidates(candidates []core.DiscoveryCandidate) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, c := range candidates {
+			if err := putJSON(tx, "discovery_candidates", c.ID, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetDiscoveryCandidates retrieves all proposed endpoints awaiting review
+func (s *Store) GetDiscoveryCandidates() ([]core.DiscoveryCandidate, error) {
+	var candidates []core.DiscoveryCandidate
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("discovery_candidates"))
+		if bucket == nil {
+			return fmt.Errorf("discovery_candidates bucket not found")
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var c core.DiscoveryCandidate
+			if err := json.Unmarshal(v, &c); err != nil {
+				return err
+			}
+			candidates = append(candidates, c)
+			return nil
+		})
+	})
+
+	return candidates, err
+}
+
+// DeleteDiscoveryCandidate deletes a proposed endpoint
+func (s *Store) DeleteDiscoveryCandidate(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("discovery_candidates"))
+		if bucket == nil {
+			return fmt.Errorf("discovery_candidates bucket not found")
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// Credential Profile Methods
+
+// SaveCredentialProfile saves a credential profile
+func (s *Store) SaveCredentialProfile(profile core.CredentialProfile) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, "credential_profiles", profile.ID, profile)
+	})
+}
+
+// GetCredentialProfiles retrieves all credential profiles
+func (s *Store) GetCredentialProfiles() ([]core.CredentialProfile, error) {
+	var profiles []core.CredentialProfile
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("credential_profiles"))
+		if bucket == nil {
+			return fmt.Errorf("credential_profiles bucket not found")
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var profile core.CredentialProfile
+			if err := json.Unmarshal(v, &profile); err != nil {
+				return err
+			}
+			profiles = append(profiles, profile)
+			return nil
+		})
+	})
+
+	return profiles, err
+}
+
+// GetCredentialProfile retrieves a credential profile by ID
+func (s *Store) GetCredentialProfile(id string) (core.CredentialProfile, error) {
+	var profile core.CredentialProfile
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("credential_profiles"))
+		if bucket == nil {
+			return fmt.Errorf("credential_profiles bucket not found")
+		}
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("credential profile not found")
+		}
+
+		return json.Unmarshal(data, &profile)
+	})
+
+	return profile, err
+}
+
+// DeleteCredentialProfile deletes a credential profile
+func (s *Store) DeleteCredentialProfile(id string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte("credential_profiles"))
+		if bucket == nil {
+			return fmt.Errorf("credential_profiles bucket not found")
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
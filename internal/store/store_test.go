@@ -0,0 +1,78 @@
+package store
+
+import (
+	"arista_engine/internal/core"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordTestResultTrim(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	if err := s.SaveEndpoint(core.Endpoint{ID: "ep1", Name: "leaf1", Type: core.EndpointEAPI}); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	noop := func(*core.Endpoint, *core.DeviceInventory) error { return nil }
+	for i := 0; i < maxTestHistory+5; i++ {
+		entry := core.TestHistoryEntry{Timestamp: base.Add(time.Duration(i) * time.Second)}
+		if err := s.RecordTestResult("ep1", entry, noop); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := s.GetTestHistory("ep1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxTestHistory {
+		t.Fatalf("kept %d entries, want %d", len(entries), maxTestHistory)
+	}
+	// Newest first; the five oldest were trimmed
+	if newest := base.Add(time.Duration(maxTestHistory+4) * time.Second); !entries[0].Timestamp.Equal(newest) {
+		t.Errorf("newest entry at %v, want %v", entries[0].Timestamp, newest)
+	}
+	if oldest := base.Add(5 * time.Second); !entries[len(entries)-1].Timestamp.Equal(oldest) {
+		t.Errorf("oldest entry at %v, want %v", entries[len(entries)-1].Timestamp, oldest)
+	}
+}
+
+func TestAlertHistory(t *testing.T) {
+	s, err := NewStore(filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < maxAlertHistory+5; i++ {
+		event := core.AlertEvent{ID: fmt.Sprintf("alert_%d", i), RuleID: "r1", Timestamp: base.Add(time.Duration(i) * time.Second)}
+		if err := s.SaveAlertEvent(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A zero since returns every kept event, oldest first; the five oldest were dropped
+	events, err := s.GetAlertHistory(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != maxAlertHistory {
+		t.Fatalf("kept %d events, want %d", len(events), maxAlertHistory)
+	}
+	if events[0].ID != "alert_5" {
+		t.Errorf("oldest kept event = %s, want alert_5", events[0].ID)
+	}
+
+	since := base.Add(time.Duration(maxAlertHistory) * time.Second)
+	if events, err = s.GetAlertHistory(since); err != nil || len(events) != 5 {
+		t.Errorf("events since %v = %d, %v; want 5", since, len(events), err)
+	}
+}
@@ -0,0 +1,231 @@
+package topology
+
+import (
+	"arista_engine/internal/client"
+	"arista_engine/internal/core"
+	"arista_engine/internal/credentials"
+	"arista_engine/internal/store"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// discoveryWorkers bounds the number of endpoints polled concurrently
+const discoveryWorkers = 8
+
+// DiscoveryCommands are run on every eAPI endpoint to build the topology
+var DiscoveryCommands = []string{"show lldp local-info", "show lldp neighbors detail", "show interfaces status"}
+
+// DeviceLLDP is the LLDP view of one polled device
+type DeviceLLDP struct {
+	EndpointID  string
+	Name        string
+	ChassisID   string
+	Description string
+	Neighbors   []Neighbor
+	Speeds      map[string]int64 // interface bandwidth in bits per second
+}
+
+// Neighbor is one LLDP neighbor seen on a local port
+type Neighbor struct {
+	LocalPort    string
+	ChassisID    string
+	SystemName   string
+	Description  string
+	RemotePort   string
+	ManagementIP string
+}
+
+// Discoverer polls LLDP state from the inventory and builds the topology graph
+type Discoverer struct {
+	store      *store.Store
+	eapiClient *client.EAPIClient
+	creds      *credentials.Manager
+}
+
+// NewDiscoverer creates a new topology discoverer
+func NewDiscoverer(store *store.Store, eapiClient *client.EAPIClient, creds *credentials.Manager) *Discoverer {
+	return &Discoverer{store: store, eapiClient: eapiClient, creds: creds}
+}
+
+// Discover polls every eAPI endpoint, builds the topology and saves it as the latest snapshot.
+// Endpoints that cannot be polled are listed in the topology's errors.
+func (d *Discoverer) Discover(ctx context.Context) (core.Topology, error) {
+	endpoints, err := d.store.GetEndpoints()
+	if err != nil {
+		return core.Topology{}, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+
+	var targets []core.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.Type == core.EndpointEAPI {
+			targets = append(targets, endpoint)
+		}
+	}
+	if len(targets) == 0 {
+		return core.Topology{}, errors.New("no eAPI endpoints to discover")
+	}
+
+	var (
+		mu      sync.Mutex
+		devices []DeviceLLDP
+		errs    []string
+		wg      sync.WaitGroup
+	)
+	queue := make(chan core.Endpoint)
+	for i := 0; i < discoveryWorkers && i < len(targets); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for endpoint := range queue {
+				device, err := d.Poll(ctx, endpoint)
+				mu.Lock()
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %v", endpoint.Name, err))
+				} else {
+					devices = append(devices, device)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, endpoint := range targets {
+		queue <- endpoint
+	}
+	close(queue)
+	wg.Wait()
+
+	topology := Build(devices)
+	topology.Errors = errs
+
+	if err := d.store.SaveTopology(topology); err != nil {
+		return topology, fmt.Errorf("failed to save topology: %w", err)
+	}
+	return topology, nil
+}
+
+// Poll collects the LLDP view of one eAPI endpoint
+func (d *Discoverer) Poll(ctx context.Context, endpoint core.Endpoint) (DeviceLLDP, error) {
+	endpoint, err := d.creds.Resolve(endpoint)
+	if err != nil {
+		return DeviceLLDP{}, err
+	}
+	eapiClient, err := d.eapiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return DeviceLLDP{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	params := client.RunCmdsParams{
+		Version: 1,
+		Cmds:    client.Commands(DiscoveryCommands...),
+		Format:  "json",
+	}
+	rpc, _, _, err := eapiClient.RunCmds(client.WithIdempotent(ctx), endpoint.URL, endpoint.Username, endpoint.Password, params)
+	if err != nil {
+		return DeviceLLDP{}, err
+	}
+	if rpc.Error != nil {
+		return DeviceLLDP{}, fmt.Errorf("eAPI error %d: %s", rpc.Error.Code, rpc.Error.Message)
+	}
+	if len(rpc.Result) < len(DiscoveryCommands) {
+		return DeviceLLDP{}, fmt.Errorf("expected %d results, got %d", len(DiscoveryCommands), len(rpc.Result))
+	}
+
+	local, _ := rpc.Result[0].(map[string]any)
+	neighbors, _ := rpc.Result[1].(map[string]any)
+	status, _ := rpc.Result[2].(map[string]any)
+	return ParseLLDP(endpoint, local, neighbors, status), nil
+}
+
+// ParseLLDP builds the LLDP view of a device from the JSON output of the discovery commands
+func ParseLLDP(endpoint core.Endpoint, local, neighbors, status map[string]any) DeviceLLDP {
+	device := DeviceLLDP{
+		EndpointID: endpoint.ID,
+		Name:       endpoint.Name,
+		Speeds:     make(map[string]int64),
+	}
+
+	// Newer releases nest local info per VRF under localInfo
+	info := local
+	if nested, ok := local["localInfo"].(map[string]any); ok {
+		info = nested
+		for _, v := range nested {
+			if vrf, ok := v.(map[string]any); ok && str(vrf, "chassisId") != "" {
+				info = vrf
+				break
+			}
+		}
+	}
+	device.ChassisID = str(info, "chassisId")
+	device.Description = str(info, "systemDescription")
+	if name := str(info, "systemName"); name != "" {
+		device.Name = name
+	}
+
+	ports, _ := neighbors["lldpNeighbors"].(map[string]any)
+	for port, raw := range ports {
+		entry, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		infos, _ := entry["lldpNeighborInfo"].([]any)
+		for _, rawInfo := range infos {
+			n, ok := rawInfo.(map[string]any)
+			if !ok {
+				continue
+			}
+			neighbor := Neighbor{
+				LocalPort:   NormalizePort(port),
+				ChassisID:   str(n, "chassisId"),
+				SystemName:  str(n, "systemName"),
+				Description: str(n, "systemDescription"),
+			}
+			if intf, ok := n["neighborInterfaceInfo"].(map[string]any); ok {
+				remote := str(intf, "interfaceId_v2")
+				if remote == "" {
+					remote = unquote(str(intf, "interfaceId"))
+				}
+				neighbor.RemotePort = NormalizePort(remote)
+			}
+			if addrs, ok := n["managementAddresses"].([]any); ok {
+				for _, rawAddr := range addrs {
+					if addr, ok := rawAddr.(map[string]any); ok && str(addr, "address") != "" {
+						neighbor.ManagementIP = str(addr, "address")
+						break
+					}
+				}
+			}
+			device.Neighbors = append(device.Neighbors, neighbor)
+		}
+	}
+
+	statuses, _ := status["interfaceStatuses"].(map[string]any)
+	for port, raw := range statuses {
+		if s, ok := raw.(map[string]any); ok {
+			if bw, ok := s["bandwidth"].(float64); ok && bw > 0 {
+				device.Speeds[NormalizePort(port)] = int64(bw)
+			}
+		}
+	}
+
+	return device
+}
+
+// str returns a string field of a JSON object, or ""
+func str(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+// unquote strips the quotes EOS puts around some interface IDs
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
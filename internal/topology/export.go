@@ -0,0 +1,165 @@
+package topology
+
+import (
+	"arista_engine/internal/core"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// Export renders a topology as Graphviz DOT, GraphML or JSON
+func Export(topology core.Topology, format string) ([]byte, error) {
+	switch format {
+	case "dot":
+		return ExportDOT(topology), nil
+	case "graphml":
+		return ExportGraphML(topology)
+	case "json":
+		data, err := json.MarshalIndent(topology, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal topology: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported topology format: %s", format)
+	}
+}
+
+// ExportDOT renders a topology as an undirected Graphviz graph. Unknown neighbors
+// and links not confirmed from both ends are drawn dashed.
+func ExportDOT(topology core.Topology) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("graph topology {\n")
+	buf.WriteString("  node [shape=box];\n")
+
+	for _, n := range topology.Nodes {
+		attrs := "label=" + strconv.Quote(n.Name)
+		if !n.Known {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&buf, "  %s [%s];\n", strconv.Quote(n.ID), attrs)
+	}
+
+	for _, l := range topology.Links {
+		attrs := fmt.Sprintf("taillabel=%s, headlabel=%s", strconv.Quote(l.SourcePort), strconv.Quote(l.TargetPort))
+		if l.SpeedBps > 0 {
+			attrs += ", label=" + strconv.Quote(FormatSpeed(l.SpeedBps))
+		}
+		if l.Status != LinkConfirmed {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(&buf, "  %s -- %s [%s];\n", strconv.Quote(l.Source), strconv.Quote(l.Target), attrs)
+	}
+
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// graphML is the root element of a GraphML document
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+// graphMLKey declares a data attribute
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+// graphMLGraph holds the nodes and edges
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+// graphMLNode is a node element
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+// graphMLEdge is an edge element
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+// graphMLData is a data value of a node or edge
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportGraphML renders a topology as GraphML
+func ExportGraphML(topology core.Topology) ([]byte, error) {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "known", For: "node", AttrName: "known", AttrType: "boolean"},
+			{ID: "chassisId", For: "node", AttrName: "chassisId", AttrType: "string"},
+			{ID: "managementIp", For: "node", AttrName: "managementIp", AttrType: "string"},
+			{ID: "sourcePort", For: "edge", AttrName: "sourcePort", AttrType: "string"},
+			{ID: "targetPort", For: "edge", AttrName: "targetPort", AttrType: "string"},
+			{ID: "speedBps", For: "edge", AttrName: "speedBps", AttrType: "long"},
+			{ID: "status", For: "edge", AttrName: "status", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "topology", EdgeDefault: "undirected"},
+	}
+
+	for _, n := range topology.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "name", Value: n.Name},
+				{Key: "known", Value: strconv.FormatBool(n.Known)},
+				{Key: "chassisId", Value: n.ChassisID},
+				{Key: "managementIp", Value: n.ManagementIP},
+			},
+		})
+	}
+	for _, l := range topology.Links {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     l.ID,
+			Source: l.Source,
+			Target: l.Target,
+			Data: []graphMLData{
+				{Key: "sourcePort", Value: l.SourcePort},
+				{Key: "targetPort", Value: l.TargetPort},
+				{Key: "speedBps", Value: strconv.FormatInt(l.SpeedBps, 10)},
+				{Key: "status", Value: l.Status},
+			},
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal GraphML: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// FormatSpeed renders a link speed in bits per second as e.g. "10G" or "100M"
+func FormatSpeed(bps int64) string {
+	switch {
+	case bps >= 1e9 && bps%1e9 == 0:
+		return fmt.Sprintf("%dG", bps/1e9)
+	case bps >= 1e9:
+		return fmt.Sprintf("%.1fG", float64(bps)/1e9)
+	case bps >= 1e6:
+		return fmt.Sprintf("%dM", bps/1e6)
+	default:
+		return fmt.Sprintf("%d", bps)
+	}
+}
@@ -0,0 +1,190 @@
+package topology
+
+import (
+	"arista_engine/internal/core"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Link statuses
+const (
+	LinkConfirmed  = "confirmed"  // both ends report each other
+	LinkOneSided   = "one-sided"  // both ends were polled but only one reports the link
+	LinkUnverified = "unverified" // the far end was not polled
+)
+
+// portAbbreviations expands short interface names to their EOS long form
+var portAbbreviations = []struct{ short, long string }{
+	{"et", "Ethernet"},
+	{"ma", "Management"},
+	{"po", "Port-Channel"},
+}
+
+// NormalizePort expands abbreviated interface names ("Et1/1" becomes "Ethernet1/1")
+func NormalizePort(port string) string {
+	lower := strings.ToLower(port)
+	for _, a := range portAbbreviations {
+		if strings.HasPrefix(lower, a.short) && len(port) > len(a.short) && isDigit(port[len(a.short)]) {
+			return a.long + port[len(a.short):]
+		}
+	}
+	return port
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// normalizeChassis reduces a chassis ID (usually a MAC) to lowercase hex without separators
+func normalizeChassis(id string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", ":", "", "-", "").Replace(id))
+}
+
+// shortName strips the domain from a hostname and lowercases it
+func shortName(name string) string {
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return strings.ToLower(name)
+}
+
+// builder accumulates nodes and links while the graph is assembled
+type builder struct {
+	nodes   map[string]*core.TopologyNode
+	aliases map[string]string // chassis or short name to node ID
+	polled  map[string]bool
+	links   map[string]*core.TopologyLink
+	seen    map[string]map[string]bool // link ID to the node IDs that reported it
+}
+
+// Build reconciles the LLDP views of the polled devices into a topology graph.
+// Each link is reported once even when both ends see it; neighbors that were
+// not polled become unknown nodes.
+func Build(devices []DeviceLLDP) core.Topology {
+	b := &builder{
+		nodes:   make(map[string]*core.TopologyNode),
+		aliases: make(map[string]string),
+		polled:  make(map[string]bool),
+		links:   make(map[string]*core.TopologyLink),
+		seen:    make(map[string]map[string]bool),
+	}
+
+	// Register polled devices first so neighbors resolve to them
+	ids := make([]string, len(devices))
+	for i, dev := range devices {
+		id := normalizeChassis(dev.ChassisID)
+		if id == "" {
+			id = dev.EndpointID
+		}
+		ids[i] = id
+		b.polled[id] = true
+		b.nodes[id] = &core.TopologyNode{
+			ID:          id,
+			Name:        dev.Name,
+			EndpointID:  dev.EndpointID,
+			ChassisID:   dev.ChassisID,
+			Description: dev.Description,
+			Known:       true,
+		}
+		b.alias(normalizeChassis(dev.ChassisID), id)
+		b.alias(shortName(dev.Name), id)
+	}
+
+	for i, dev := range devices {
+		local := ids[i]
+		for _, n := range dev.Neighbors {
+			localPort, remotePort := NormalizePort(n.LocalPort), NormalizePort(n.RemotePort)
+			remote := b.resolve(n)
+			link := b.link(local, localPort, remote, remotePort)
+			b.seen[link.ID][local] = true
+			if speed := dev.Speeds[localPort]; speed > 0 && link.SpeedBps == 0 {
+				link.SpeedBps = speed
+			}
+		}
+	}
+
+	topology := core.Topology{CollectedAt: time.Now()}
+	for _, node := range b.nodes {
+		topology.Nodes = append(topology.Nodes, *node)
+	}
+	for id, link := range b.links {
+		switch {
+		case !b.polled[link.Source] || !b.polled[link.Target]:
+			link.Status = LinkUnverified
+		case len(b.seen[id]) == 2 || link.Source == link.Target:
+			link.Status = LinkConfirmed
+		default:
+			link.Status = LinkOneSided
+		}
+		topology.Links = append(topology.Links, *link)
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool { return topology.Nodes[i].Name < topology.Nodes[j].Name })
+	sort.Slice(topology.Links, func(i, j int) bool { return topology.Links[i].ID < topology.Links[j].ID })
+	return topology
+}
+
+// alias maps a chassis ID or name to a node unless already taken
+func (b *builder) alias(key, id string) {
+	if key == "" {
+		return
+	}
+	if _, ok := b.aliases[key]; !ok {
+		b.aliases[key] = id
+	}
+}
+
+// resolve finds the node of an LLDP neighbor, creating an unknown node if needed
+func (b *builder) resolve(n Neighbor) string {
+	if id, ok := b.aliases[normalizeChassis(n.ChassisID)]; ok && n.ChassisID != "" {
+		return id
+	}
+	if id, ok := b.aliases[shortName(n.SystemName)]; ok && n.SystemName != "" {
+		return id
+	}
+
+	key := normalizeChassis(n.ChassisID)
+	if key == "" {
+		key = shortName(n.SystemName)
+	}
+	id := "unknown:" + key
+	name := n.SystemName
+	if name == "" {
+		name = n.ChassisID
+	}
+	b.nodes[id] = &core.TopologyNode{
+		ID:           id,
+		Name:         name,
+		ChassisID:    n.ChassisID,
+		Description:  n.Description,
+		ManagementIP: n.ManagementIP,
+	}
+	b.alias(normalizeChassis(n.ChassisID), id)
+	b.alias(shortName(n.SystemName), id)
+	return id
+}
+
+// link returns the link between two node ports, creating it if needed. The
+// endpoints are ordered so both sides of a link map to the same ID.
+func (b *builder) link(a, aPort, z, zPort string) *core.TopologyLink {
+	if z+"|"+zPort < a+"|"+aPort {
+		a, aPort, z, zPort = z, zPort, a, aPort
+	}
+	id := a + "|" + aPort + "--" + z + "|" + zPort
+	if link, ok := b.links[id]; ok {
+		return link
+	}
+
+	link := &core.TopologyLink{
+		ID:         id,
+		Source:     a,
+		SourcePort: aPort,
+		Target:     z,
+		TargetPort: zPort,
+	}
+	b.links[id] = link
+	b.seen[id] = make(map[string]bool)
+	return link
+}
@@ -0,0 +1,230 @@
+package topology
+
+import (
+	"arista_engine/internal/core"
+	"context"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// walkStep is a device whose neighbors are explored during a walk
+type walkStep struct {
+	endpoint core.Endpoint
+	hop      int
+}
+
+// Walk explores outward from a seed endpoint using LLDP neighbor management addresses.
+// Every new address within the allowed ranges is tested over eAPI with the request's
+// credentials and returned as a candidate; reachable candidates within the hop limit
+// are explored in turn. Nothing is added to the inventory.
+func (d *Discoverer) Walk(ctx context.Context, req core.NeighborWalkRequest) ([]core.DiscoveryCandidate, error) {
+	seed, err := d.store.GetEndpoint(req.SeedEndpointID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load seed endpoint: %w", err)
+	}
+	if seed.Type != core.EndpointEAPI {
+		return nil, fmt.Errorf("seed endpoint %s is not an eAPI endpoint", seed.Name)
+	}
+
+	allow, err := parsePrefixes(req.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := parsePrefixes(req.Deny)
+	if err != nil {
+		return nil, err
+	}
+	maxHops := req.MaxHops
+	if maxHops <= 0 {
+		maxHops = 1
+	}
+
+	// Addresses already in the inventory are walked through but never proposed
+	endpoints, err := d.store.GetEndpoints()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load endpoints: %w", err)
+	}
+	known := make(map[string]core.Endpoint)
+	for _, ep := range endpoints {
+		if ep.Type != core.EndpointEAPI {
+			continue
+		}
+		if u, err := url.Parse(ep.URL); err == nil && u.Hostname() != "" {
+			known[strings.ToLower(u.Hostname())] = ep
+		}
+	}
+
+	visited := map[string]bool{seed.ID: true}
+	if u, err := url.Parse(seed.URL); err == nil {
+		visited[strings.ToLower(u.Hostname())] = true
+	}
+
+	var candidates []core.DiscoveryCandidate
+	queue := []walkStep{{endpoint: seed}}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return candidates, err
+		}
+		step := queue[0]
+		queue = queue[1:]
+
+		device, err := d.Poll(ctx, step.endpoint)
+		if err != nil {
+			if step.hop == 0 {
+				return nil, fmt.Errorf("failed to poll seed %s: %w", seed.Name, err)
+			}
+			continue
+		}
+
+		for _, n := range device.Neighbors {
+			addr, err := netip.ParseAddr(n.ManagementIP)
+			if err != nil || !walkable(addr, allow, deny) {
+				continue
+			}
+			host := strings.ToLower(addr.String())
+			if visited[host] {
+				continue
+			}
+			visited[host] = true
+			hop := step.hop + 1
+
+			if ep, ok := known[host]; ok {
+				if hop < maxHops && !visited[ep.ID] {
+					visited[ep.ID] = true
+					queue = append(queue, walkStep{endpoint: ep, hop: hop})
+				}
+				continue
+			}
+
+			candidate := d.probeCandidate(ctx, req, seed.Proxy, addr, n, device.Name, hop)
+			candidates = append(candidates, candidate)
+			if candidate.Reachable && hop < maxHops {
+				queue = append(queue, walkStep{endpoint: candidate.Endpoint, hop: hop})
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// probeCandidate tests a neighbor's management address over eAPI, through the seed's
+// proxy or jump host if it has one
+func (d *Discoverer) probeCandidate(ctx context.Context, req core.NeighborWalkRequest, proxy *core.ProxyConfig, addr netip.Addr, n Neighbor, via string, hop int) core.DiscoveryCandidate {
+	name := n.SystemName
+	if name == "" {
+		name = addr.String()
+	}
+
+	endpoint := core.Endpoint{
+		ID:            "discovery",
+		Name:          name,
+		Type:          core.EndpointEAPI,
+		URL:           "https://" + hostPort(addr),
+		Username:      req.Username,
+		Password:      req.Password,
+		CredentialRef: req.CredentialRef,
+		TLSVerify:     req.TLSVerify,
+		Proxy:         proxy,
+		Tags:          []string{"discovered"},
+	}
+
+	candidate := core.DiscoveryCandidate{
+		ID:            "cand_" + strings.NewReplacer(".", "_", ":", "_").Replace(addr.String()),
+		Name:          name,
+		ManagementIP:  addr.String(),
+		Hop:           hop,
+		DiscoveredVia: via,
+		ViaPort:       n.LocalPort,
+		NeighborPort:  n.RemotePort,
+		Discovered:    time.Now(),
+	}
+
+	candidate.Endpoint = endpoint
+
+	resolved, err := d.creds.Resolve(endpoint)
+	if err != nil {
+		candidate.Message = err.Error()
+		return candidate
+	}
+	eapiClient, err := d.eapiClient.ForEndpoint(resolved)
+	if err != nil {
+		candidate.Message = err.Error()
+		return candidate
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	meta, status, _, err := eapiClient.ShowVersion(probeCtx, resolved.URL, resolved.Username, resolved.Password)
+	switch {
+	case err != nil:
+		candidate.Message = err.Error()
+	case status != 200:
+		candidate.Message = fmt.Sprintf("HTTP %d", status)
+	default:
+		candidate.Reachable = true
+		candidate.Message = "Connection successful"
+		candidate.Model = meta.Model
+		candidate.Version = meta.Version
+		candidate.Serial = meta.Serial
+	}
+	return candidate
+}
+
+// parsePrefixes parses CIDR ranges; bare addresses are treated as single-host prefixes
+func parsePrefixes(ranges []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, r := range ranges {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if !strings.Contains(r, "/") {
+			addr, err := netip.ParseAddr(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid address range %q: %w", r, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address range %q: %w", r, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// walkable reports whether an address may be contacted under the allow and deny ranges
+func walkable(addr netip.Addr, allow, deny []netip.Prefix) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsLinkLocalUnicast() || addr.IsMulticast() || addr.IsUnspecified() {
+		return false
+	}
+	for _, p := range deny {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	for _, p := range allow {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// hostPort formats an address for use in a URL host
+func hostPort(addr netip.Addr) string {
+	if addr.Is6() && !addr.Is4In6() {
+		return "[" + addr.String() + "]"
+	}
+	return addr.Unmap().String()
+}
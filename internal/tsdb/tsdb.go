@@ -0,0 +1,501 @@
+package tsdb
+
+import (
+	"arista_engine/internal/core"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// Resolution buckets kept for every series. Raw samples are downsampled into
+// one-minute and one-hour rollups as they are written.
+const (
+	resRaw    = "raw"
+	resMinute = "1m"
+	resHour   = "1h"
+)
+
+// resolutionRank orders the resolutions from finest to coarsest
+var resolutionRank = map[string]int{resRaw: 0, resMinute: 1, resHour: 2}
+
+// seriesBucket holds one nested bucket per device/path series
+var seriesBucket = []byte("series")
+
+// Retention defines how long each resolution is kept
+type Retention struct {
+	Raw    time.Duration `json:"raw"`
+	Minute time.Duration `json:"minute"`
+	Hour   time.Duration `json:"hour"`
+}
+
+// DefaultRetention keeps a day of raw samples, a week of minute rollups and 90 days of hourly rollups
+var DefaultRetention = Retention{
+	Raw:    24 * time.Hour,
+	Minute: 7 * 24 * time.Hour,
+	Hour:   90 * 24 * time.Hour,
+}
+
+// Writes are buffered and stored many samples per transaction, when the buffer holds
+// maxBatch samples or every flushInterval
+const (
+	maxBatch      = 1000
+	flushInterval = time.Second
+)
+
+// DB is an embedded time-series store bucketed by device and path
+type DB struct {
+	db        *bolt.DB
+	retention Retention
+
+	flushMu sync.Mutex // keeps batches in write order
+	mu      sync.Mutex
+	pending []point
+	onError func(error)
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// point is a buffered sample waiting to be written
+type point struct {
+	device, path string
+	ts           time.Time
+	value        float64
+}
+
+// rollup is the stored aggregate for a downsampled bucket
+type rollup struct {
+	Min   float64  `json:"min"`
+	Max   float64  `json:"max"`
+	Sum   float64  `json:"sum"`
+	Count int64    `json:"count"`
+	First *float64 `json:"first,omitempty"` // missing in rollups written by older versions
+	Last  float64  `json:"last"`
+}
+
+// Open opens (or creates) a time-series database
+func Open(dbPath string, retention Retention) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create time-series directory: %w", err)
+	}
+
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open time-series database: %w", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(seriesBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize time-series buckets: %w", err)
+	}
+
+	d := &DB{db: db, retention: retention, stop: make(chan struct{}), done: make(chan struct{})}
+	go d.flushLoop()
+	return d, nil
+}
+
+// OnError sets a callback for errors of background flushes
+func (d *DB) OnError(fn func(error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onError = fn
+}
+
+// Close writes the buffered samples and closes the time-series database
+func (d *DB) Close() error {
+	close(d.stop)
+	<-d.done
+	if err := d.Flush(); err != nil {
+		d.db.Close()
+		return err
+	}
+	return d.db.Close()
+}
+
+// flushLoop writes the buffered samples every flushInterval until Close
+func (d *DB) flushLoop() {
+	defer close(d.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+			if err := d.Flush(); err != nil {
+				d.mu.Lock()
+				onError := d.onError
+				d.mu.Unlock()
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
+
+// seriesKey builds the bucket name for a device/path pair
+func seriesKey(device, path string) []byte {
+	return []byte(device + "|" + path)
+}
+
+// timeKey encodes a timestamp as a sortable bucket key
+func timeKey(ts time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(ts.UnixNano()))
+	return k
+}
+
+// keyTime decodes a bucket key back into a timestamp
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
+
+// Write buffers a sample for its series. The buffer is written when it is full, every
+// flushInterval and before reads; errors of a full buffer are returned here, those of
+// background flushes go to the OnError callback.
+func (d *DB) Write(device, path string, ts time.Time, value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("invalid sample value for %s %s", device, path)
+	}
+
+	d.mu.Lock()
+	d.pending = append(d.pending, point{device: device, path: path, ts: ts, value: value})
+	full := len(d.pending) >= maxBatch
+	d.mu.Unlock()
+	if full {
+		return d.Flush()
+	}
+	return nil
+}
+
+// Flush writes the buffered samples and updates their rollups in one transaction
+func (d *DB) Flush() error {
+	d.flushMu.Lock()
+	defer d.flushMu.Unlock()
+
+	d.mu.Lock()
+	batch := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		for _, p := range batch {
+			if err := writePoint(tx, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write %d samples: %w", len(batch), err)
+	}
+	return nil
+}
+
+// writePoint stores a sample in its series and updates its rollups
+func writePoint(tx *bolt.Tx, p point) error {
+	series, err := tx.Bucket(seriesBucket).CreateBucketIfNotExists(seriesKey(p.device, p.path))
+	if err != nil {
+		return fmt.Errorf("failed to create series bucket: %w", err)
+	}
+
+	raw, err := series.CreateBucketIfNotExists([]byte(resRaw))
+	if err != nil {
+		return err
+	}
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, math.Float64bits(p.value))
+	if err := raw.Put(timeKey(p.ts), v); err != nil {
+		return err
+	}
+
+	if err := addToRollup(series, resMinute, p.ts.Truncate(time.Minute), p.value); err != nil {
+		return err
+	}
+	return addToRollup(series, resHour, p.ts.Truncate(time.Hour), p.value)
+}
+
+// WriteSample writes a sample if its value is numeric; other values are ignored
+func (d *DB) WriteSample(sample core.Sample) error {
+	value, ok := core.SampleFloat(sample.Value)
+	if !ok {
+		return nil
+	}
+	return d.Write(sample.Device, sample.Path, sample.Timestamp, value)
+}
+
+// addToRollup merges a value into the rollup for the given bucket start
+func addToRollup(series *bolt.Bucket, res string, start time.Time, value float64) error {
+	b, err := series.CreateBucketIfNotExists([]byte(res))
+	if err != nil {
+		return err
+	}
+
+	key := timeKey(start)
+	first := value
+	agg := rollup{Min: value, Max: value, First: &first}
+	if data := b.Get(key); data != nil {
+		agg = rollup{}
+		if err := json.Unmarshal(data, &agg); err != nil {
+			return fmt.Errorf("failed to unmarshal rollup: %w", err)
+		}
+		agg.Min = math.Min(agg.Min, value)
+		agg.Max = math.Max(agg.Max, value)
+	}
+	agg.Sum += value
+	agg.Count++
+	agg.Last = value
+
+	data, err := json.Marshal(agg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rollup: %w", err)
+	}
+	return b.Put(key, data)
+}
+
+// ListSeries returns the paths recorded for a device (all devices if device is empty),
+// formatted as "device|path"
+func (d *DB) ListSeries(device string) ([]string, error) {
+	if err := d.Flush(); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(seriesBucket).ForEach(func(k, v []byte) error {
+			name := string(k)
+			if device == "" || strings.HasPrefix(name, device+"|") {
+				names = append(names, name)
+			}
+			return nil
+		})
+	})
+	sort.Strings(names)
+	return names, err
+}
+
+// Query returns the points of a series between from and to. When step is set the
+// points are grouped into step-sized buckets and the coarsest rollup that still
+// satisfies the step is used. Windows older than a resolution's retention are read
+// from the finest rollup still retained for their start, as in Aggregate.
+func (d *DB) Query(device, path string, from, to time.Time, step time.Duration) ([]core.SeriesPoint, error) {
+	if err := d.Flush(); err != nil {
+		return nil, err
+	}
+
+	var points []core.SeriesPoint
+	res := resRaw
+	switch {
+	case step >= time.Hour:
+		res = resHour
+	case step >= time.Minute:
+		res = resMinute
+	}
+	if retained := d.resolutionFor(from, time.Now()); resolutionRank[retained] > resolutionRank[res] {
+		res = retained
+	}
+
+	// Rollup buckets are keyed by their start, so align the window to include a partial first bucket
+	switch res {
+	case resMinute:
+		from = from.Truncate(time.Minute)
+	case resHour:
+		from = from.Truncate(time.Hour)
+	}
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(seriesBucket).Bucket(seriesKey(device, path))
+		if series == nil {
+			return fmt.Errorf("series not found: %s %s", device, path)
+		}
+		b := series.Bucket([]byte(res))
+		if b == nil {
+			return nil
+		}
+
+		c := b.Cursor()
+		end := timeKey(to)
+		for k, v := c.Seek(timeKey(from)); k != nil && string(k) <= string(end); k, v = c.Next() {
+			if res == resRaw {
+				value := math.Float64frombits(binary.BigEndian.Uint64(v))
+				points = append(points, core.SeriesPoint{Timestamp: keyTime(k), Value: value, Min: value, Max: value, Count: 1})
+				continue
+			}
+
+			var agg rollup
+			if err := json.Unmarshal(v, &agg); err != nil {
+				return fmt.Errorf("failed to unmarshal rollup: %w", err)
+			}
+			points = append(points, core.SeriesPoint{
+				Timestamp: keyTime(k),
+				Value:     agg.Sum / float64(agg.Count),
+				Min:       agg.Min,
+				Max:       agg.Max,
+				Count:     agg.Count,
+			})
+		}
+		return nil
+	})
+	if err != nil || step <= 0 {
+		return points, err
+	}
+
+	return regroup(points, step), nil
+}
+
+// regroup merges points into step-sized buckets
+func regroup(points []core.SeriesPoint, step time.Duration) []core.SeriesPoint {
+	var out []core.SeriesPoint
+	for _, p := range points {
+		start := p.Timestamp.Truncate(step)
+		if n := len(out); n > 0 && out[n-1].Timestamp.Equal(start) {
+			last := &out[n-1]
+			total := last.Value*float64(last.Count) + p.Value*float64(p.Count)
+			last.Count += p.Count
+			last.Value = total / float64(last.Count)
+			last.Min = math.Min(last.Min, p.Min)
+			last.Max = math.Max(last.Max, p.Max)
+			continue
+		}
+		p.Timestamp = start
+		out = append(out, p)
+	}
+	return out
+}
+
+// Aggregate computes min/max/avg over a series in a window. The finest resolution
+// still retained for the start of the window is used, so windows older than the raw
+// retention are answered from the minute or hour rollups, at bucket granularity.
+func (d *DB) Aggregate(device, path string, from, to time.Time) (core.SeriesStats, error) {
+	stats := core.SeriesStats{Device: device, Path: path, From: from, To: to}
+	if err := d.Flush(); err != nil {
+		return stats, err
+	}
+	res := d.resolutionFor(from, time.Now())
+	stats.Resolution = res
+
+	err := d.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(seriesBucket).Bucket(seriesKey(device, path))
+		if series == nil {
+			return fmt.Errorf("series not found: %s %s", device, path)
+		}
+		b := series.Bucket([]byte(res))
+		if b == nil {
+			return nil
+		}
+
+		start := from
+		switch res {
+		case resMinute:
+			start = from.Truncate(time.Minute)
+		case resHour:
+			start = from.Truncate(time.Hour)
+		}
+
+		var sum float64
+		c := b.Cursor()
+		end := timeKey(to)
+		for k, v := c.Seek(timeKey(start)); k != nil && string(k) <= string(end); k, v = c.Next() {
+			var agg rollup
+			if res == resRaw {
+				value := math.Float64frombits(binary.BigEndian.Uint64(v))
+				agg = rollup{Min: value, Max: value, Sum: value, Count: 1, First: &value, Last: value}
+			} else if err := json.Unmarshal(v, &agg); err != nil {
+				return fmt.Errorf("failed to unmarshal rollup: %w", err)
+			}
+			if agg.Count == 0 {
+				continue
+			}
+
+			if stats.Count == 0 {
+				stats.Min, stats.Max = agg.Min, agg.Max
+				stats.First = agg.Sum / float64(agg.Count)
+				if agg.First != nil {
+					stats.First = *agg.First
+				}
+			}
+			stats.Min = math.Min(stats.Min, agg.Min)
+			stats.Max = math.Max(stats.Max, agg.Max)
+			stats.Last = agg.Last
+			stats.Count += agg.Count
+			sum += agg.Sum
+		}
+		if stats.Count > 0 {
+			stats.Avg = sum / float64(stats.Count)
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// resolutionFor returns the finest resolution whose retention still covers from
+func (d *DB) resolutionFor(from, now time.Time) string {
+	age := now.Sub(from)
+	switch {
+	case d.retention.Raw <= 0 || age <= d.retention.Raw:
+		return resRaw
+	case d.retention.Minute <= 0 || age <= d.retention.Minute:
+		return resMinute
+	default:
+		return resHour
+	}
+}
+
+// Prune removes samples and rollups that fall outside the retention window
+func (d *DB) Prune(now time.Time) error {
+	limits := map[string]time.Duration{
+		resRaw:    d.retention.Raw,
+		resMinute: d.retention.Minute,
+		resHour:   d.retention.Hour,
+	}
+	if err := d.Flush(); err != nil {
+		return err
+	}
+
+	return d.db.Update(func(tx *bolt.Tx) error {
+		// Collect series names first; buckets must not change while iterating
+		var names [][]byte
+		if err := tx.Bucket(seriesBucket).ForEach(func(name, _ []byte) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			series := tx.Bucket(seriesBucket).Bucket(name)
+			if series == nil {
+				continue
+			}
+			for res, keep := range limits {
+				b := series.Bucket([]byte(res))
+				if b == nil || keep <= 0 {
+					continue
+				}
+				cutoff := timeKey(now.Add(-keep))
+				c := b.Cursor()
+				for k, _ := c.First(); k != nil && string(k) < string(cutoff); k, _ = c.First() {
+					if err := c.Delete(); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,231 @@
+package tsdb
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "ts.db"), DefaultRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// storedSamples counts the raw samples of a series that reached the database
+func storedSamples(t *testing.T, db *DB, device, path string) int {
+	t.Helper()
+	n := 0
+	err := db.db.View(func(tx *bolt.Tx) error {
+		if series := tx.Bucket(seriesBucket).Bucket(seriesKey(device, path)); series != nil {
+			n = series.Bucket([]byte(resRaw)).Stats().KeyN
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestWriteBatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ts.db")
+	db, err := Open(path, DefaultRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Truncate(time.Hour)
+
+	// Samples stay buffered until the batch is full
+	for i := 0; i < maxBatch-1; i++ {
+		if err := db.Write("leaf1", "cpu", base.Add(time.Duration(i)*time.Millisecond), float64(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := storedSamples(t, db, "leaf1", "cpu"); n != 0 {
+		t.Errorf("stored before the batch is full = %d, want 0", n)
+	}
+	if err := db.Write("leaf1", "cpu", base.Add(time.Second), 1); err != nil {
+		t.Fatal(err)
+	}
+	if n := storedSamples(t, db, "leaf1", "cpu"); n != maxBatch {
+		t.Errorf("stored after a full batch = %d, want %d", n, maxBatch)
+	}
+
+	// Reads see buffered samples, and Close writes the rest
+	if err := db.Write("leaf1", "memory", base, 42); err != nil {
+		t.Fatal(err)
+	}
+	if points, err := db.Query("leaf1", "memory", base, base, 0); err != nil || len(points) != 1 {
+		t.Errorf("query of a buffered sample = %v, %v", points, err)
+	}
+	if err := db.Write("leaf1", "memory", base.Add(time.Second), 43); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	db, err = Open(path, DefaultRetention)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if n := storedSamples(t, db, "leaf1", "memory"); n != 2 {
+		t.Errorf("stored after Close = %d, want 2", n)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	// Two minutes of samples in each age range
+	write := func(base time.Time) time.Time {
+		base = base.Truncate(time.Hour)
+		samples := []struct {
+			offset time.Duration
+			value  float64
+		}{
+			{0, 1}, {10 * time.Second, 2}, {20 * time.Second, 3},
+			{time.Minute, 10}, {time.Minute + 30*time.Second, 20},
+		}
+		for _, s := range samples {
+			if err := db.Write("leaf1", "cpu", base.Add(s.offset), s.value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return base
+	}
+	recent := write(now.Add(-2 * time.Hour))
+	week := write(now.Add(-3 * 24 * time.Hour))
+	month := write(now.Add(-30 * 24 * time.Hour))
+
+	tests := []struct {
+		name       string
+		from, to   time.Time
+		resolution string
+		min, max   float64
+		avg        float64
+		first      float64
+		last       float64
+		count      int64
+	}{
+		{"raw window", recent, recent.Add(2 * time.Minute), resRaw, 1, 20, 36.0 / 5, 1, 20, 5},
+		{"raw partial window", recent.Add(5 * time.Second), recent.Add(65 * time.Second), resRaw, 2, 10, 5, 2, 10, 3},
+		{"minute rollups", week, week.Add(2 * time.Minute), resMinute, 1, 20, 36.0 / 5, 1, 20, 5},
+		// Rollups are used whole: the window starts inside the first minute
+		{"minute partial window", week.Add(30 * time.Second), week.Add(45 * time.Second), resMinute, 1, 3, 2, 1, 3, 3},
+		{"hour rollups", month, month.Add(time.Hour), resHour, 1, 20, 36.0 / 5, 1, 20, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := db.Aggregate("leaf1", "cpu", tt.from, tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Resolution != tt.resolution {
+				t.Errorf("resolution = %q, want %q", stats.Resolution, tt.resolution)
+			}
+			if stats.Min != tt.min || stats.Max != tt.max || stats.Avg != tt.avg || stats.Count != tt.count {
+				t.Errorf("min/max/avg/count = %v/%v/%v/%d, want %v/%v/%v/%d", stats.Min, stats.Max, stats.Avg, stats.Count, tt.min, tt.max, tt.avg, tt.count)
+			}
+			if stats.First != tt.first || stats.Last != tt.last {
+				t.Errorf("first/last = %v/%v, want %v/%v", stats.First, stats.Last, tt.first, tt.last)
+			}
+		})
+	}
+
+	// Once raw samples are pruned, a window past the raw retention still has data
+	if err := db.Prune(now); err != nil {
+		t.Fatal(err)
+	}
+	stats, err := db.Aggregate("leaf1", "cpu", week, week.Add(2*time.Minute))
+	if err != nil || stats.Count != 5 {
+		t.Errorf("after prune: %+v, %v", stats, err)
+	}
+
+	if _, err := db.Aggregate("leaf1", "memory", recent, now); err == nil {
+		t.Error("Aggregate of an unknown series: expected an error")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	// Three samples in each of two minutes, written into every age range
+	write := func(base time.Time) time.Time {
+		base = base.Truncate(time.Hour)
+		for i, v := range []float64{1, 2, 3, 10, 20, 30} {
+			ts := base.Add(time.Duration(i/3)*time.Minute + time.Duration(i%3)*10*time.Second)
+			if err := db.Write("leaf1", "cpu", ts, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return base
+	}
+	recent := write(now.Add(-2 * time.Hour))
+	week := write(now.Add(-3 * 24 * time.Hour))
+	month := write(now.Add(-30 * 24 * time.Hour))
+	if err := db.Prune(now); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		from   time.Time
+		step   time.Duration
+		counts []int64
+		values []float64
+	}{
+		{"raw samples", recent, 0, []int64{1, 1, 1, 1, 1, 1}, []float64{1, 2, 3, 10, 20, 30}},
+		{"raw grouped by minute", recent, time.Minute, []int64{3, 3}, []float64{2, 20}},
+		// Past the raw retention the minute rollups answer even without a step
+		{"older than raw retention", week, 0, []int64{3, 3}, []float64{2, 20}},
+		{"older than raw retention with a short step", week.Add(5 * time.Second), 10 * time.Second, []int64{3, 3}, []float64{2, 20}},
+		{"older than minute retention", month, time.Minute, []int64{6}, []float64{11}},
+		{"step coarser than retention", week, time.Hour, []int64{6}, []float64{11}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := db.Query("leaf1", "cpu", tt.from, tt.from.Add(2*time.Minute), tt.step)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(points) != len(tt.counts) {
+				t.Fatalf("got %d points, want %d: %+v", len(points), len(tt.counts), points)
+			}
+			for i, p := range points {
+				if p.Count != tt.counts[i] || p.Value != tt.values[i] {
+					t.Errorf("point %d: count/value = %d/%v, want %d/%v", i, p.Count, p.Value, tt.counts[i], tt.values[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolutionFor(t *testing.T) {
+	db := &DB{retention: DefaultRetention}
+	now := time.Now()
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{time.Hour, resRaw},
+		{24 * time.Hour, resRaw},
+		{25 * time.Hour, resMinute},
+		{7 * 24 * time.Hour, resMinute},
+		{8 * 24 * time.Hour, resHour},
+	}
+	for _, tt := range tests {
+		if got := db.resolutionFor(now.Add(-tt.age), now); got != tt.want {
+			t.Errorf("resolutionFor(-%v) = %q, want %q", tt.age, got, tt.want)
+		}
+	}
+}
@@ -3,11 +3,13 @@ package uiapi
 import (
 	"arista_engine/internal/client"
 	"arista_engine/internal/core"
+	"arista_engine/internal/credentials"
 	"arista_engine/internal/store"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -16,14 +18,20 @@ type ExplorerAPI struct {
 	store      *store.Store
 	eapiClient *client.EAPIClient
 	cvClient   *client.CloudVisionClient
+	gnmiClient *client.GNMIClient
+	sshClient  *client.SSHClient
+	creds      *credentials.Manager
 }
 
 // NewExplorerAPI creates a new ExplorerAPI instance
-func NewExplorerAPI(store *store.Store, eapiClient *client.EAPIClient, cvClient *client.CloudVisionClient) *ExplorerAPI {
+func NewExplorerAPI(store *store.Store, eapiClient *client.EAPIClient, cvClient *client.CloudVisionClient, gnmiClient *client.GNMIClient, sshClient *client.SSHClient, creds *credentials.Manager) *ExplorerAPI {
 	return &ExplorerAPI{
 		store:      store,
 		eapiClient: eapiClient,
 		cvClient:   cvClient,
+		gnmiClient: gnmiClient,
+		sshClient:  sshClient,
+		creds:      creds,
 	}
 }
 
@@ -35,6 +43,12 @@ func (e *ExplorerAPI) RunAPIRequest(ctx context.Context, request core.ExplorerRe
 		return core.ExplorerResponse{}, fmt.Errorf("failed to get endpoint: %w", err)
 	}
 
+	// Resolve the credential profile at dispatch time so rotations apply immediately
+	endpoint, err = e.creds.Resolve(endpoint)
+	if err != nil {
+		return core.ExplorerResponse{}, err
+	}
+
 	// Set timeout
 	timeout := 30 * time.Second
 	if request.TimeoutMs > 0 {
@@ -51,6 +65,10 @@ func (e *ExplorerAPI) RunAPIRequest(ctx context.Context, request core.ExplorerRe
 		response, err = e.handleEAPIRequest(ctx, endpoint, request)
 	case core.EndpointCV:
 		response, err = e.handleCloudVisionRequest(ctx, endpoint, request)
+	case core.EndpointTelemetry:
+		response, err = e.handleTelemetryRequest(ctx, endpoint, request)
+	case core.EndpointSSH:
+		response, err = e.handleSSHRequest(ctx, endpoint, request)
 	default:
 		return core.ExplorerResponse{}, fmt.Errorf("unsupported endpoint type: %s", endpoint.Type)
 	}
@@ -90,8 +108,13 @@ func (e *ExplorerAPI) handleEAPIRequest(ctx context.Context, endpoint core.Endpo
 		return core.ExplorerResponse{}, fmt.Errorf("failed to convert request body: %w", err)
 	}
 
+	eapiClient, err := e.eapiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.ExplorerResponse{}, err
+	}
+
 	// Execute the request
-	rpc, httpResp, elapsed, err := e.eapiClient.RunCmds(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params)
+	rpc, httpResp, elapsed, err := eapiClient.RunCmds(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params)
 	if err != nil && rpc == nil {
 		return core.ExplorerResponse{}, err
 	}
@@ -106,17 +129,58 @@ func (e *ExplorerAPI) handleEAPIRequest(ctx context.Context, endpoint core.Endpo
 
 	// Handle JSON-RPC response
 	if rpc != nil {
-		if rpc.Error != nil {
-			response.Error = rpc.Error.Message
-			response.JSON = map[string]any{
-				"error": rpc.Error,
-			}
-		} else {
-			response.JSON = map[string]any{
-				"result": rpc.Result,
-			}
+		applyRunCmdsResult(&response, rpc, params)
+	}
+
+	return response, err
+}
+
+// applyRunCmdsResult fills a response with the JSON-RPC result and the outcome of
+// each command, including the partial results of a failed batch
+func applyRunCmdsResult(response *core.ExplorerResponse, rpc *client.JSONRPCResponse, params client.RunCmdsParams) {
+	commands, failed := rpc.Commands(params)
+	response.Commands = commands
+	if failed >= 0 {
+		response.FailedCommand = &failed
+	}
+
+	if rpc.Error != nil {
+		response.Error = rpc.Error.Message
+		response.JSON = map[string]any{
+			"error": rpc.Error,
+		}
+	} else {
+		response.JSON = map[string]any{
+			"result": rpc.Result,
 		}
 	}
+}
+
+// handleSSHRequest runs an eAPI-style request over the SSH CLI, so eAPI requests and
+// templates work unchanged against devices without eAPI
+func (e *ExplorerAPI) handleSSHRequest(ctx context.Context, endpoint core.Endpoint, request core.ExplorerRequest) (core.ExplorerResponse, error) {
+	params, err := e.convertToRunCmdsParams(request.Body)
+	if err != nil {
+		return core.ExplorerResponse{}, fmt.Errorf("failed to convert request body: %w", err)
+	}
+
+	sshClient, err := e.sshClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.ExplorerResponse{}, err
+	}
+
+	rpc, elapsed, err := sshClient.RunCmds(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params)
+	if err != nil && rpc == nil {
+		return core.ExplorerResponse{}, err
+	}
+
+	// There is no HTTP exchange; a completed CLI session reports 200 like eAPI does
+	response := core.ExplorerResponse{
+		Status:     200,
+		ElapsedMs:  elapsed.Milliseconds(),
+		EndpointID: endpoint.ID,
+	}
+	applyRunCmdsResult(&response, rpc, params)
 
 	return response, err
 }
@@ -126,8 +190,13 @@ func (e *ExplorerAPI) handleCloudVisionRequest(ctx context.Context, endpoint cor
 	// Build full URL
 	fullURL := endpoint.URL + request.Path
 
+	cvClient, err := e.cvClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.ExplorerResponse{}, err
+	}
+
 	// Execute the request
-	resp, elapsed, err := e.cvClient.DoREST(ctx, request.Method, fullURL, endpoint.Token, request.Body)
+	resp, elapsed, err := cvClient.DoREST(ctx, request.Method, fullURL, endpoint.Token, request.Body)
 	if err != nil {
 		return core.ExplorerResponse{}, err
 	}
@@ -153,6 +222,133 @@ func (e *ExplorerAPI) handleCloudVisionRequest(ctx context.Context, endpoint cor
 	return response, nil
 }
 
+// handleTelemetryRequest handles gNMI Capabilities, Get and Subscribe requests.
+// The request method selects the RPC; the body carries the paths and subscription options.
+func (e *ExplorerAPI) handleTelemetryRequest(ctx context.Context, endpoint core.Endpoint, request core.ExplorerRequest) (core.ExplorerResponse, error) {
+	gnmiClient, err := e.gnmiClient.ForEndpoint(endpoint)
+	if err != nil {
+		return core.ExplorerResponse{}, err
+	}
+
+	response := core.ExplorerResponse{
+		Status:     200,
+		EndpointID: endpoint.ID,
+	}
+
+	switch strings.ToLower(request.Method) {
+	case "capabilities":
+		caps, elapsed, err := gnmiClient.Capabilities(ctx, endpoint.URL, endpoint.Username, endpoint.Password)
+		response.ElapsedMs = elapsed.Milliseconds()
+		if err != nil {
+			return response, err
+		}
+		var models []map[string]any
+		for _, m := range caps.GetSupportedModels() {
+			models = append(models, map[string]any{
+				"name":         m.GetName(),
+				"organization": m.GetOrganization(),
+				"version":      m.GetVersion(),
+			})
+		}
+		var encodings []string
+		for _, enc := range caps.GetSupportedEncodings() {
+			encodings = append(encodings, enc.String())
+		}
+		response.JSON = map[string]any{
+			"gnmiVersion": caps.GetGNMIVersion(),
+			"models":      models,
+			"encodings":   encodings,
+		}
+		return response, nil
+
+	case "get", "":
+		params, err := e.convertToSubscribeParams(request.Body)
+		if err != nil {
+			return core.ExplorerResponse{}, fmt.Errorf("failed to convert request body: %w", err)
+		}
+		updates, elapsed, err := gnmiClient.Get(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params.Paths, params.Encoding)
+		response.ElapsedMs = elapsed.Milliseconds()
+		response.JSON = map[string]any{"updates": updates}
+		return response, err
+
+	case "subscribe":
+		params, err := e.convertToSubscribeParams(request.Body)
+		if err != nil {
+			return core.ExplorerResponse{}, fmt.Errorf("failed to convert request body: %w", err)
+		}
+
+		// Stream subscriptions are bounded so the explorer always gets a response
+		if strings.EqualFold(params.Mode, "stream") {
+			duration := 10 * time.Second
+			if d, ok := request.Body["durationMs"].(float64); ok && d > 0 {
+				duration = time.Duration(d) * time.Millisecond
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, duration)
+			defer cancel()
+			if params.MaxUpdates == 0 {
+				params.MaxUpdates = 1000
+			}
+		}
+
+		var updates []client.GNMIUpdate
+		elapsed, err := gnmiClient.Subscribe(ctx, endpoint.URL, endpoint.Username, endpoint.Password, params, func(u client.GNMIUpdate) error {
+			updates = append(updates, u)
+			return nil
+		})
+		response.ElapsedMs = elapsed.Milliseconds()
+		response.JSON = map[string]any{"updates": updates}
+		return response, err
+
+	default:
+		return core.ExplorerResponse{}, fmt.Errorf("unsupported telemetry method: %s", request.Method)
+	}
+}
+
+// convertToSubscribeParams converts a request body to GNMISubscribeParams
+func (e *ExplorerAPI) convertToSubscribeParams(body map[string]any) (client.GNMISubscribeParams, error) {
+	params := client.GNMISubscribeParams{
+		Mode:     "once",
+		Encoding: "json",
+	}
+
+	// Extract paths (a single "path" string is accepted as well)
+	if paths, ok := body["paths"].([]interface{}); ok {
+		for _, p := range paths {
+			if pathStr, ok := p.(string); ok {
+				params.Paths = append(params.Paths, pathStr)
+			}
+		}
+	} else if path, ok := body["path"].(string); ok {
+		params.Paths = []string{path}
+	}
+	if len(params.Paths) == 0 {
+		return params, errors.New("paths field is required and must be an array of strings")
+	}
+
+	// Extract optional fields
+	if mode, ok := body["mode"].(string); ok {
+		params.Mode = mode
+	}
+	if streamMode, ok := body["streamMode"].(string); ok {
+		params.StreamMode = streamMode
+	}
+	if interval, ok := body["sampleIntervalMs"].(float64); ok {
+		params.SampleInterval = time.Duration(interval) * time.Millisecond
+	}
+	if encoding, ok := body["encoding"].(string); ok {
+		params.Encoding = encoding
+	}
+	if polls, ok := body["polls"].(float64); ok {
+		params.Polls = int(polls)
+	}
+	if maxUpdates, ok := body["maxUpdates"].(float64); ok {
+		params.MaxUpdates = int(maxUpdates)
+	}
+
+	return params, nil
+}
+
 // convertToRunCmdsParams converts a request body to RunCmdsParams
 func (e *ExplorerAPI) convertToRunCmdsParams(body map[string]any) (client.RunCmdsParams, error) {
 	params := client.RunCmdsParams{
@@ -162,20 +358,40 @@ func (e *ExplorerAPI) convertToRunCmdsParams(body map[string]any) (client.RunCmd
 		ExpandAliases: true,
 	}
 
-	// Extract commands
+	// Extract commands: strings or {cmd, revision, input} objects
 	if cmds, ok := body["cmds"].([]interface{}); ok {
-		for _, cmd := range cmds {
-			if cmdStr, ok := cmd.(string); ok {
-				params.Cmds = append(params.Cmds, cmdStr)
+		for i, cmd := range cmds {
+			switch c := cmd.(type) {
+			case string:
+				params.Cmds = append(params.Cmds, client.Command{Cmd: c})
+			case map[string]any:
+				command := client.Command{}
+				command.Cmd, _ = c["cmd"].(string)
+				if command.Cmd == "" {
+					return params, errors.New("command objects need a cmd field")
+				}
+				if revision, ok := c["revision"].(float64); ok {
+					command.Revision = int(revision)
+				}
+				command.Input, _ = c["input"].(string)
+				params.Cmds = append(params.Cmds, command)
+			default:
+				return params, fmt.Errorf("cmds[%d] must be a string or a command object, got %T", i, cmd)
 			}
 		}
 	} else {
-		return params, errors.New("cmds field is required and must be an array of strings")
+		return params, errors.New("cmds field is required and must be an array of strings or command objects")
 	}
 
 	// Extract optional fields
-	if version, ok := body["version"].(float64); ok {
-		params.Version = int(version)
+	switch version := body["version"].(type) {
+	case float64:
+		params.Version = client.Version(version)
+	case string:
+		if version != "latest" {
+			return params, fmt.Errorf("invalid version %q: use a number or \"latest\"", version)
+		}
+		params.Version = client.VersionLatest
 	}
 	if format, ok := body["format"].(string); ok {
 		params.Format = format
@@ -186,6 +402,15 @@ func (e *ExplorerAPI) convertToRunCmdsParams(body map[string]any) (client.RunCmd
 	if expandAliases, ok := body["expandAliases"].(bool); ok {
 		params.ExpandAliases = expandAliases
 	}
+	if timestamps, ok := body["timestamps"].(bool); ok {
+		params.Timestamps = timestamps
+	}
+	if streaming, ok := body["streaming"].(bool); ok {
+		params.Streaming = streaming
+	}
+	if errorDetail, ok := body["includeErrorDetail"].(bool); ok {
+		params.IncludeErrorDetail = errorDetail
+	}
 
 	return params, nil
 }